@@ -16,5 +16,6 @@ func Start(vi *version.BuildInfo, rootCmd *cobra.Command) error {
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newServeCmd())
 	rootCmd.AddCommand(newLintCmd())
+	rootCmd.AddCommand(newExplainCmd())
 	return rootCmd.Execute()
 }
@@ -0,0 +1,39 @@
+package cmds
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	"github.com/spf13/cobra"
+)
+
+func newExplainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain [code]",
+		Short: "Print the documentation of a diagnostic rule or list all rules",
+		Long: "Print the documentation of a diagnostic rule. The rule can be given by its code (e.g. HLS001), " +
+			"its ID (e.g. type-check/field-access) or its name (e.g. field-access). All rules are listed if no rule is given.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				for _, rule := range diagnosticrules.Rules {
+					id := rule.ID()
+					if id == rule.Code {
+						id = ""
+					}
+					fmt.Fprintf(writer, "%s\t%s\t%s\n", rule.Code, id, rule.Summary)
+				}
+				return writer.Flush()
+			}
+
+			rule, ok := diagnosticrules.Find(args[0])
+			if !ok {
+				return fmt.Errorf("unknown rule %s, run helm_ls explain to list all rules", args[0])
+			}
+			fmt.Fprint(cmd.OutOrStdout(), rule.Explain())
+			return nil
+		},
+	}
+}
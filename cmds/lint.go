@@ -6,12 +6,15 @@ import (
 
 	"github.com/mrjosh/helm-ls/internal/charts"
 	helmlint "github.com/mrjosh/helm-ls/internal/helm_lint"
+	"github.com/mrjosh/helm-ls/internal/util"
 	"github.com/spf13/cobra"
 	"go.lsp.dev/uri"
 )
 
 func newLintCmd() *cobra.Command {
-	return &cobra.Command{
+	config := util.DefaultConfig
+
+	cmd := &cobra.Command{
 		Use:   "lint",
 		Short: "Lint a helm project",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -26,13 +29,20 @@ func newLintCmd() *cobra.Command {
 				return err
 			}
 
-			msgs := helmlint.GetDiagnostics(rootPath, chart.ValuesFiles.MainValuesFile.Values)
+			msgs := helmlint.GetDiagnostics(rootPath, chart.ValuesFiles.MainValuesFile.Values, config.RenderConfig)
 
-			for _, msg := range msgs {
-				fmt.Println(msg)
+			for filePath, msg := range msgs {
+				fmt.Println(helmlint.SuppressDiagnostics(filePath, msg, config.DisabledRules))
 			}
 
 			return nil
 		},
 	}
+
+	// helm lint only supports the namespace and the Kubernetes version of the render config
+	cmd.Flags().StringVar(&config.RenderConfig.Namespace, "namespace", config.RenderConfig.Namespace, "Namespace of the release")
+	cmd.Flags().StringVar(&config.RenderConfig.KubeVersion, "kube-version", config.RenderConfig.KubeVersion, "Kubernetes version used for the capabilities, the default of helm is used if empty")
+	cmd.Flags().StringSliceVar(&config.DisabledRules, "disable-rule", config.DisabledRules, "Code, ID, name or group of a diagnostic rule that is not reported, can be repeated")
+
+	return cmd
 }
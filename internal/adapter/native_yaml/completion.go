@@ -0,0 +1,90 @@
+package nativeyaml
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/jsonschema"
+	"github.com/mrjosh/helm-ls/internal/protocol"
+	lsp "go.lsp.dev/protocol"
+)
+
+// CallCompletion completes the keys of the object at the position or,
+// behind the colon of a key, the values of its enum
+func (b *Backend) CallCompletion(_ context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error) {
+	result := &lsp.CompletionList{Items: []lsp.CompletionItem{}}
+
+	text, chart, ok := b.getTemplate(params.TextDocument.URI)
+	if !ok {
+		return result, nil
+	}
+	yamlContext := getYamlContext(text, params.Position)
+	if !yamlContext.hasKind {
+		return result, nil
+	}
+	schema, ok := b.schemas.getSchema(chart, yamlContext.kind)
+	if !ok {
+		return result, nil
+	}
+
+	if yamlContext.isValue {
+		info, ok := jsonschema.GetValuesSchemaInfo(schema, append(yamlContext.path, yamlContext.key))
+		if !ok {
+			return result, nil
+		}
+		for _, value := range info.Enum {
+			result.Items = append(result.Items, lsp.CompletionItem{
+				Label:         fmt.Sprint(value),
+				Kind:          lsp.CompletionItemKindEnumMember,
+				Documentation: info.Description,
+			})
+		}
+		return result, nil
+	}
+
+	properties := jsonschema.GetSchemaProperties(schema, yamlContext.path)
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		if !slices.Contains(yamlContext.siblings, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		info := properties[name]
+		result.Items = append(result.Items, lsp.CompletionItem{
+			Label:         name,
+			Kind:          lsp.CompletionItemKindProperty,
+			Detail:        strings.Join(info.Types, " | "),
+			Documentation: info.Description,
+			InsertText:    name + ": ",
+		})
+	}
+	return result, nil
+}
+
+// CallHoverOrComplete shows the documentation of the key at the position
+func (b *Backend) CallHoverOrComplete(_ context.Context, params lsp.HoverParams, _ string) (*lsp.Hover, error) {
+	text, chart, ok := b.getTemplate(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	yamlContext := getYamlContext(text, params.Position)
+	if !yamlContext.hasKind || yamlContext.key == "" || yamlContext.isValue {
+		return nil, nil
+	}
+	schema, ok := b.schemas.getSchema(chart, yamlContext.kind)
+	if !ok {
+		return nil, nil
+	}
+
+	info, ok := jsonschema.GetValuesSchemaInfo(schema, append(yamlContext.path, yamlContext.key))
+	if !ok {
+		return nil, nil
+	}
+	return protocol.BuildHoverResponse(info.Markdown(), yamlContext.keyRange), nil
+}
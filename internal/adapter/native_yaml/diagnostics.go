@@ -0,0 +1,139 @@
+package nativeyaml
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	"github.com/mrjosh/helm-ls/internal/jsonschema"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
+	"github.com/mrjosh/helm-ls/internal/util"
+	lsp "go.lsp.dev/protocol"
+	"gopkg.in/yaml.v3"
+)
+
+const diagnosticsSource = "Helm-ls NativeYaml"
+
+// updateDiagnostics validates the template in the background and publishes the diagnostics
+// together with the other diagnostics of the document
+func (b *Backend) updateDiagnostics(uri lsp.DocumentURI) {
+	if !b.config.DiagnosticsEnabled || b.client == nil {
+		return
+	}
+	text, chart, ok := b.getTemplate(uri)
+	if !ok {
+		return
+	}
+	doc, _ := b.documents.GetTemplateDoc(uri)
+	ast := doc.Ast.Copy()
+
+	go func() {
+		diagnostics := lsplocal.SuppressYamlDiagnostics(b.getDiagnostics(text, chart), ast, b.config.DiagnosticsFilters)
+		doc, ok := b.documents.GetTemplateDoc(uri)
+		if !ok {
+			return
+		}
+		doc.DiagnosticsCache.SetYamlDiagnostics(diagnostics)
+		if !doc.DiagnosticsCache.ShouldShowDiagnosticsOnNewYamlDiagnostics() {
+			return
+		}
+		err := b.client.PublishDiagnostics(context.Background(), &lsp.PublishDiagnosticsParams{
+			URI:         uri,
+			Diagnostics: doc.DiagnosticsCache.GetMergedDiagnostics(),
+		})
+		if err != nil {
+			logger.Error("Error publishing native yaml diagnostics", err)
+		}
+	}()
+}
+
+// getDiagnostics validates every YAML document of the template against the schema of its kind.
+// Documents that are not valid YAML or whose kind is set by actions are skipped. Values that
+// are empty after removing the actions and missing required fields are not reported,
+// because they are usually set by actions.
+func (b *Backend) getDiagnostics(text string, chart *charts.Chart) []lsp.Diagnostic {
+	diagnostics := []lsp.Diagnostic{}
+
+	lines := strings.Split(text, "\n")
+	start := 0
+	for end := 0; end <= len(lines); end++ {
+		if end < len(lines) && !isDocumentSeparator(lines[end]) {
+			continue
+		}
+		diagnostics = append(diagnostics, b.getDocumentDiagnostics(strings.Join(lines[start:end], "\n"), start, chart)...)
+		start = end + 1
+	}
+	return diagnostics
+}
+
+func (b *Backend) getDocumentDiagnostics(text string, lineOffset int, chart *charts.Chart) []lsp.Diagnostic {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(text), root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+	object := map[string]any{}
+	if err := root.Decode(&object); err != nil {
+		return nil
+	}
+	apiVersion, _ := object["apiVersion"].(string)
+	kind, _ := object["kind"].(string)
+	if apiVersion == "" || kind == "" {
+		return nil
+	}
+	schema, ok := b.schemas.getSchema(chart, kubernetesschema.GroupVersionKind{APIVersion: apiVersion, Kind: kind})
+	if !ok {
+		return nil
+	}
+
+	schemaErrors, err := jsonschema.Validate(schema, object, "the schema of "+kind)
+	if err != nil {
+		logger.Debug("Could not validate the template", err)
+		return nil
+	}
+
+	diagnostics := []lsp.Diagnostic{}
+	for _, schemaError := range schemaErrors {
+		if schemaError.Type == "required" {
+			continue
+		}
+		key, value := util.GetYamlNodesForPath(root.Content[0], schemaError.Path)
+		if value == nil || value.Tag == "!!null" {
+			continue
+		}
+		node := value
+		if schemaError.Type == "additional_property_not_allowed" {
+			if key, _ = util.GetYamlNodesForPath(value, []string{schemaError.Property}); key == nil {
+				continue
+			}
+			node = key
+		} else if key != nil && value.Kind != yaml.ScalarNode {
+			node = key
+		}
+		diagnostics = append(diagnostics, lsp.Diagnostic{
+			Range:           getRangeOfNode(node, lineOffset),
+			Severity:        lsp.DiagnosticSeverityError,
+			Code:            diagnosticrules.YamllsSchema.Code,
+			CodeDescription: diagnosticrules.YamllsSchema.CodeDescription(),
+			Source:          diagnosticsSource,
+			Message:         schemaError.Message,
+		})
+	}
+	return diagnostics
+}
+
+func getRangeOfNode(node *yaml.Node, lineOffset int) lsp.Range {
+	start := lsp.Position{Line: uint32(node.Line - 1 + lineOffset), Character: uint32(node.Column - 1)}
+	length := 1
+	if node.Kind == yaml.ScalarNode && !strings.Contains(node.Value, "\n") {
+		length = max(len(node.Value), 1)
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+			length += 2
+		}
+	}
+	return lsp.Range{
+		Start: start,
+		End:   lsp.Position{Line: start.Line, Character: start.Character + uint32(length)},
+	}
+}
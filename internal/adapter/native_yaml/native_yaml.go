@@ -0,0 +1,119 @@
+package nativeyaml
+
+import (
+	"context"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	"github.com/mrjosh/helm-ls/internal/log"
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+)
+
+var logger = log.GetLogger()
+
+// Backend provides completion, hover and schema validation for the YAML text of templates
+// without yaml-language-server. It works on the templates with the actions removed
+// and uses the JSON schemas of the Kubernetes kinds and the CRDs of the chart.
+// Features that only yaml-language-server provides (e.g. formatting) return empty results.
+type Backend struct {
+	config     util.YamllsConfiguration
+	client     lsp.Client
+	documents  *document.DocumentStore
+	chartStore *charts.ChartStore
+	schemas    *schemaStore
+}
+
+// NewBackend creates the backend, kubeVersion selects the downloaded schemas of the kinds that are not bundled
+// like render.kubeVersion and crdSchemas converts the CRDs of the charts
+func NewBackend(config util.YamllsConfiguration, kubeVersion string, crdSchemas *kubernetesschema.CRDSchemaCache, client lsp.Client, documents *document.DocumentStore, chartStore *charts.ChartStore) *Backend {
+	backend := &Backend{
+		config:     config,
+		client:     client,
+		documents:  documents,
+		chartStore: chartStore,
+		schemas:    newSchemaStore(getSchemaCacheDir(), kubeVersion, crdSchemas),
+	}
+	backend.schemas.onDownloaded = backend.updateOpenDocuments
+	return backend
+}
+
+// updateOpenDocuments validates the open templates again, e.g. after a schema was downloaded
+func (b *Backend) updateOpenDocuments() {
+	for _, doc := range b.documents.GetAllTemplateDocs() {
+		if doc.IsOpen && doc.IsYaml {
+			b.updateDiagnostics(doc.URI)
+		}
+	}
+}
+
+// getTemplate returns the template with the actions removed and the chart of the document
+func (b *Backend) getTemplate(uri lsp.DocumentURI) (string, *charts.Chart, bool) {
+	doc, ok := b.documents.GetTemplateDoc(uri)
+	if !ok || !doc.IsYaml || doc.Ast == nil {
+		return "", nil, false
+	}
+
+	var chart *charts.Chart
+	if b.chartStore != nil {
+		var err error
+		if chart, err = b.chartStore.GetChartForDoc(uri); err != nil {
+			logger.Debug("Could not get a chart for the document", uri, err)
+		}
+	}
+	return lsplocal.TrimTemplate(doc.Ast.Copy(), doc.Content), chart, true
+}
+
+func (b *Backend) InitiallySyncOpenTemplateDocuments(docs []*document.TemplateDocument) {
+	for _, doc := range docs {
+		if !doc.IsOpen {
+			continue
+		}
+		doc.IsYaml = document.IsYamllsEnabled(doc.URI, b.config)
+		b.updateDiagnostics(doc.URI)
+	}
+}
+
+func (b *Backend) DocumentDidOpenTemplate(_ *sitter.Tree, params lsp.DidOpenTextDocumentParams) {
+	b.updateDiagnostics(params.TextDocument.URI)
+}
+
+func (b *Backend) DocumentDidSaveTemplate(doc *document.TemplateDocument, _ lsp.DidSaveTextDocumentParams) {
+	b.updateDiagnostics(doc.URI)
+}
+
+func (b *Backend) DocumentDidChangeFullSyncTemplate(doc *document.TemplateDocument, _ lsp.DidChangeTextDocumentParams) {
+	if b.config.ShowDiagnosticsDirectly {
+		b.updateDiagnostics(doc.URI)
+	}
+}
+
+func (b *Backend) CallDocumentSymbol(_ context.Context, _ *lsp.DocumentSymbolParams) ([]interface{}, error) {
+	return []interface{}{}, nil
+}
+
+func (b *Backend) CallCodeAction(_ context.Context, _ *lsp.CodeActionParams) ([]lsp.CodeAction, error) {
+	return []lsp.CodeAction{}, nil
+}
+
+func (b *Backend) CallFoldingRanges(_ context.Context, _ *lsp.FoldingRangeParams) ([]lsp.FoldingRange, error) {
+	return []lsp.FoldingRange{}, nil
+}
+
+func (b *Backend) CallSelectionRange(_ context.Context, _ *lsp.SelectionRangeParams) ([]lsp.SelectionRange, error) {
+	return []lsp.SelectionRange{}, nil
+}
+
+func (b *Backend) CallDocumentLink(_ context.Context, _ *lsp.DocumentLinkParams) ([]lsp.DocumentLink, error) {
+	return []lsp.DocumentLink{}, nil
+}
+
+func (b *Backend) CallFormatting(_ context.Context, _ *lsp.DocumentFormattingParams) ([]lsp.TextEdit, error) {
+	return []lsp.TextEdit{}, nil
+}
+
+// Stop does nothing, the native backend has no process to stop
+func (b *Backend) Stop() {}
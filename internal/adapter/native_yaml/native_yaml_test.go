@@ -0,0 +1,216 @@
+package nativeyaml
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/jsonschema"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+const apiServiceSchema = `{
+  "type": "object",
+  "properties": {
+    "apiVersion": {"type": "string"},
+    "kind": {"type": "string"},
+    "spec": {"type": "object", "description": "Spec contains information for locating and communicating with a server"}
+  }
+}`
+
+const template = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+spec:
+  replicas: {{ .Values.replicas }}
+  strategy:
+    type:
+  unknown: true
+---
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: "three"
+`
+
+func newSchemaServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "apiservice-apiregistration-v1.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(apiServiceSchema))
+	}))
+	t.Cleanup(server.Close)
+
+	previousURL := kubernetesschema.KubernetesSchemaBaseURL
+	kubernetesschema.KubernetesSchemaBaseURL = server.URL
+	t.Cleanup(func() { kubernetesschema.KubernetesSchemaBaseURL = previousURL })
+	return server
+}
+
+func newTestBackend(t *testing.T, text string) (*Backend, uri.URI) {
+	fileURI := uri.File("/chart/templates/deployment.yaml")
+	documents := document.NewDocumentStore()
+	_, err := documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: fileURI, Text: text},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+
+	backend := NewBackend(util.DefaultConfig.YamllsConfiguration, "", kubernetesschema.NewCRDSchemaCache(), nil, documents, nil)
+	backend.schemas = newSchemaStore(t.TempDir(), "", kubernetesschema.NewCRDSchemaCache())
+	return backend, fileURI
+}
+
+func TestCallCompletion(t *testing.T) {
+	backend, fileURI := newTestBackend(t, template)
+
+	testCases := []struct {
+		desc     string
+		position lsp.Position
+		expected []string
+	}{
+		{"keys at the root without the existing ones", lsp.Position{Line: 4, Character: 0}, []string{"spec", "status"}},
+		{"keys of an object", lsp.Position{Line: 7, Character: 4}, []string{"rollingUpdate", "type"}},
+		{"enum of a value", lsp.Position{Line: 1, Character: 6}, []string{"Deployment"}},
+		{"no completions for unknown paths", lsp.Position{Line: 8, Character: 11}, []string{}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result, err := backend.CallCompletion(context.Background(), &lsp.CompletionParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: fileURI},
+					Position:     tt.position,
+				},
+			})
+			assert.NoError(t, err)
+			labels := []string{}
+			for _, item := range result.Items {
+				labels = append(labels, item.Label)
+			}
+			assert.Equal(t, tt.expected, labels)
+		})
+	}
+}
+
+func TestCallHoverOrComplete(t *testing.T) {
+	backend, fileURI := newTestBackend(t, template)
+
+	result, err := backend.CallHoverOrComplete(context.Background(), lsp.HoverParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: fileURI},
+			Position:     lsp.Position{Line: 5, Character: 4},
+		},
+	}, "replicas:")
+	assert.NoError(t, err)
+	assert.Equal(t, "Number of desired pods. This is a pointer to distinguish between explicit zero and not specified. Defaults to 1.\n\nType: `integer`", result.Contents.Value)
+	assert.Equal(t, lsp.Range{Start: lsp.Position{Line: 5, Character: 2}, End: lsp.Position{Line: 5, Character: 10}}, *result.Range)
+}
+
+func TestGetDiagnostics(t *testing.T) {
+	backend, fileURI := newTestBackend(t, template)
+
+	text, chart, ok := backend.getTemplate(fileURI)
+	assert.True(t, ok)
+
+	diagnostics := backend.getDiagnostics(text, chart)
+	ranges := []lsp.Range{}
+	for _, diagnostic := range diagnostics {
+		assert.Equal(t, diagnosticsSource, diagnostic.Source)
+		ranges = append(ranges, diagnostic.Range)
+	}
+	assert.ElementsMatch(t, []lsp.Range{
+		{Start: lsp.Position{Line: 8, Character: 2}, End: lsp.Position{Line: 8, Character: 9}},
+		{Start: lsp.Position{Line: 13, Character: 12}, End: lsp.Position{Line: 13, Character: 19}},
+	}, ranges)
+}
+
+func TestSchemaStoreUsesBundledSchemas(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	previousURL := kubernetesschema.KubernetesSchemaBaseURL
+	kubernetesschema.KubernetesSchemaBaseURL = server.URL
+	defer func() { kubernetesschema.KubernetesSchemaBaseURL = previousURL }()
+
+	store := newSchemaStore(t.TempDir(), "", kubernetesschema.NewCRDSchemaCache())
+	schema, ok := store.getSchema(nil, kubernetesschema.GroupVersionKind{APIVersion: "apps/v1", Kind: "Deployment"})
+	assert.True(t, ok)
+	info, ok := jsonschema.GetValuesSchemaInfo(schema, []string{"spec", "replicas"})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"integer"}, info.Types)
+
+	// custom kinds without a CRD in the chart are not downloaded
+	_, ok = store.getSchema(nil, kubernetesschema.GroupVersionKind{APIVersion: "example.com/v1", Kind: "Unknown"})
+	assert.False(t, ok)
+	store.downloads.Wait()
+	assert.Equal(t, int32(0), requests.Load())
+}
+
+func TestSchemaStoreCachesDownloadedSchemasOnDisk(t *testing.T) {
+	server := newSchemaServer(t)
+	cacheDir := t.TempDir()
+	apiService := kubernetesschema.GroupVersionKind{APIVersion: "apiregistration.k8s.io/v1", Kind: "APIService"}
+
+	store := newSchemaStore(cacheDir, "", nil)
+	downloaded := false
+	store.onDownloaded = func() { downloaded = true }
+	_, ok := store.getSchema(nil, apiService)
+	assert.False(t, ok)
+	store.downloads.Wait()
+	assert.True(t, downloaded)
+	schema, ok := store.getSchema(nil, apiService)
+	assert.True(t, ok)
+	assert.JSONEq(t, apiServiceSchema, string(schema))
+
+	server.Close()
+	schema, ok = newSchemaStore(cacheDir, "", nil).getSchema(nil, apiService)
+	assert.True(t, ok)
+	assert.JSONEq(t, apiServiceSchema, string(schema))
+
+	cacheFiles, _ := filepath.Glob(filepath.Join(cacheDir, "*", "apiservice-apiregistration-v1.json"))
+	assert.Len(t, cacheFiles, 1)
+}
+
+func TestSchemaStoreRetriesFailedDownloads(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	previousURL := kubernetesschema.KubernetesSchemaBaseURL
+	kubernetesschema.KubernetesSchemaBaseURL = server.URL
+	defer func() { kubernetesschema.KubernetesSchemaBaseURL = previousURL }()
+	previousInterval := retryInterval
+	defer func() { retryInterval = previousInterval }()
+
+	store := newSchemaStore(t.TempDir(), "", kubernetesschema.NewCRDSchemaCache())
+	apiService := kubernetesschema.GroupVersionKind{APIVersion: "apiregistration.k8s.io/v1", Kind: "APIService"}
+	_, ok := store.getSchema(nil, apiService)
+	assert.False(t, ok)
+	store.downloads.Wait()
+	_, ok = store.getSchema(nil, apiService)
+	assert.False(t, ok)
+	store.downloads.Wait()
+	assert.Equal(t, int32(1), requests.Load())
+
+	retryInterval = 0
+	_, ok = store.getSchema(nil, apiService)
+	assert.False(t, ok)
+	store.downloads.Wait()
+	assert.Equal(t, int32(2), requests.Load())
+}
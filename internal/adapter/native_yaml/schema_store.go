@@ -0,0 +1,169 @@
+package nativeyaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+)
+
+const downloadTimeout = 10 * time.Second
+
+// retryInterval is the time after which a failed download is tried again
+var retryInterval = time.Minute
+
+// schemaStore loads the JSON schemas of Kubernetes kinds. The schemas of built-in kinds are bundled,
+// the schemas of other kinds of the Kubernetes API groups (e.g. CustomResourceDefinition) are
+// downloaded in the background and cached on disk, so that they are available offline afterwards.
+type schemaStore struct {
+	mu          sync.Mutex
+	cacheDir    string
+	kubeVersion string
+	crdSchemas  *kubernetesschema.CRDSchemaCache
+	schemas     map[string][]byte
+	// downloading contains the URLs that are currently downloaded
+	downloading map[string]bool
+	// failed contains the time of the last failed download of a URL, it is tried again after the retryInterval
+	failed     map[string]time.Time
+	downloads  sync.WaitGroup
+	httpClient *http.Client
+	// onDownloaded is called after a schema was downloaded, e.g. to validate the open documents again
+	onDownloaded func()
+}
+
+func newSchemaStore(cacheDir string, kubeVersion string, crdSchemas *kubernetesschema.CRDSchemaCache) *schemaStore {
+	return &schemaStore{
+		cacheDir:    cacheDir,
+		kubeVersion: kubeVersion,
+		crdSchemas:  crdSchemas,
+		schemas:     map[string][]byte{},
+		downloading: map[string]bool{},
+		failed:      map[string]time.Time{},
+		httpClient:  &http.Client{Timeout: downloadTimeout},
+	}
+}
+
+// getSchemaCacheDir returns the directory for the downloaded schemas in the user cache directory
+func getSchemaCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "helm-ls", "kubernetes-json-schema")
+}
+
+// getSchema returns the schema of the kind, the CRDs of the chart take precedence over the built-in kinds.
+// Returns false if the kind is unknown or its schema is not available yet. Schemas of kinds that are not
+// bundled and not cached on disk are downloaded in the background.
+func (s *schemaStore) getSchema(chart *charts.Chart, kind kubernetesschema.GroupVersionKind) ([]byte, bool) {
+	if schema, ok := s.crdSchemas.Get(chart)[kind]; ok {
+		return schema, true
+	}
+	if schema, ok := kubernetesschema.GetBundledSchema(kind); ok {
+		return schema, true
+	}
+	if !isKubernetesAPIGroup(kind) {
+		return nil, false
+	}
+
+	url := string(kubernetesschema.GetBuiltInSchemaURL(kind, s.kubeVersion))
+
+	s.mu.Lock()
+	schema, ok := s.schemas[url]
+	s.mu.Unlock()
+	if ok {
+		return schema, true
+	}
+
+	cacheFile := filepath.Join(s.cacheDir, path.Base(kubernetesschema.GetKubernetesSchemaURL(s.kubeVersion)), path.Base(url))
+	if schema, err := os.ReadFile(cacheFile); err == nil && json.Valid(schema) {
+		s.mu.Lock()
+		s.schemas[url] = schema
+		s.mu.Unlock()
+		return schema, true
+	}
+
+	s.startDownload(url, cacheFile)
+	return nil, false
+}
+
+// isKubernetesAPIGroup returns true if the kind belongs to an API group of Kubernetes itself,
+// only those kinds have schemas at the download location
+func isKubernetesAPIGroup(kind kubernetesschema.GroupVersionKind) bool {
+	group, _, found := strings.Cut(kind.APIVersion, "/")
+	return !found || strings.HasSuffix(group, ".k8s.io")
+}
+
+// startDownload downloads the schema in the background unless it is already downloaded
+// or its last download failed less than the retryInterval ago
+func (s *schemaStore) startDownload(url string, cacheFile string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if failedAt, ok := s.failed[url]; s.downloading[url] || (ok && time.Since(failedAt) < retryInterval) {
+		return
+	}
+	s.downloading[url] = true
+	s.downloads.Add(1)
+
+	go func() {
+		defer s.downloads.Done()
+		schema, err := s.download(url)
+
+		s.mu.Lock()
+		delete(s.downloading, url)
+		if err != nil {
+			s.failed[url] = time.Now()
+			s.mu.Unlock()
+			logger.Error("Could not download the schema", url, err)
+			return
+		}
+		delete(s.failed, url)
+		s.schemas[url] = schema
+		onDownloaded := s.onDownloaded
+		s.mu.Unlock()
+
+		if err := writeCacheFile(cacheFile, schema); err != nil {
+			logger.Error("Could not cache the schema", url, err)
+		}
+		if onDownloaded != nil {
+			onDownloaded()
+		}
+	}()
+}
+
+func (s *schemaStore) download(url string) ([]byte, error) {
+	logger.Debug("Downloading schema", url)
+	response, err := s.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s for %s", response.Status, url)
+	}
+	schema, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(schema) {
+		return nil, fmt.Errorf("invalid schema at %s", url)
+	}
+	return schema, nil
+}
+
+func writeCacheFile(file string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(file, content, 0o600)
+}
@@ -0,0 +1,179 @@
+package nativeyaml
+
+import (
+	"strings"
+
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	lsp "go.lsp.dev/protocol"
+)
+
+// yamlLine is a line of the YAML text reduced to the parts that are relevant for the structure
+type yamlLine struct {
+	blank      bool
+	indent     int
+	keyColumn  int
+	isListItem bool
+	key        string
+	hasValue   bool
+	// valueColumn is the column after the colon of the key
+	valueColumn int
+}
+
+func parseLine(line string) yamlLine {
+	content := strings.TrimLeft(line, " ")
+	indent := len(line) - len(content)
+	if strings.TrimSpace(content) == "" || strings.HasPrefix(content, "#") {
+		return yamlLine{blank: true, indent: indent}
+	}
+
+	result := yamlLine{indent: indent, keyColumn: indent}
+	for content == "-" || strings.HasPrefix(content, "- ") {
+		result.isListItem = true
+		trimmed := strings.TrimLeft(content[1:], " ")
+		result.keyColumn += len(content) - len(trimmed)
+		content = trimmed
+	}
+
+	if content == "" || strings.ContainsAny(content[:1], "{[\"'|>&*!%@`") {
+		return result
+	}
+	colon := strings.Index(content, ": ")
+	if colon < 0 && strings.HasSuffix(strings.TrimRight(content, " \t\r"), ":") {
+		colon = len(strings.TrimRight(content, " \t\r")) - 1
+	}
+	if colon < 0 {
+		return result
+	}
+	result.key = strings.TrimSpace(content[:colon])
+	result.valueColumn = result.keyColumn + colon + 1
+	value := strings.TrimSpace(content[colon+1:])
+	result.hasValue = value != "" && !strings.HasPrefix(value, "#")
+	return result
+}
+
+func isDocumentSeparator(line string) bool {
+	return strings.HasPrefix(line, "---")
+}
+
+// yamlContext describes the position of the cursor in the YAML text of a template
+type yamlContext struct {
+	kind    kubernetesschema.GroupVersionKind
+	hasKind bool
+	// path of the object that contains the cursor, list items are represented by "0"
+	path []string
+	// key of the line of the cursor
+	key      string
+	keyRange lsp.Range
+	// isValue is true if the cursor is behind the colon of the key
+	isValue bool
+	// siblings are the keys of the object that contains the cursor, except the key of the cursor line
+	siblings []string
+}
+
+// getYamlContext determines the path and kind of the object at the position by the indentation of the lines,
+// so that it works on YAML that is incomplete while typing
+func getYamlContext(text string, position lsp.Position) yamlContext {
+	lines := strings.Split(text, "\n")
+	if int(position.Line) >= len(lines) {
+		return yamlContext{}
+	}
+	lineNumber := int(position.Line)
+	column := int(position.Character)
+	current := parseLine(lines[lineNumber])
+
+	result := yamlContext{path: []string{}, siblings: []string{}}
+	indent := column
+	if !current.blank && current.keyColumn <= column {
+		indent = current.keyColumn
+		result.key = current.key
+		if current.key != "" {
+			result.keyRange = lsp.Range{
+				Start: lsp.Position{Line: position.Line, Character: uint32(current.keyColumn)},
+				End:   lsp.Position{Line: position.Line, Character: uint32(current.keyColumn + len(current.key))},
+			}
+			result.isValue = column >= current.valueColumn
+		}
+	}
+
+	reversedPath := []string{}
+	if current.isListItem && current.keyColumn <= column {
+		reversedPath = append(reversedPath, "0")
+		indent = current.indent
+	}
+
+	siblingIndent := indent
+	collectSiblings := !current.isListItem
+	for i := lineNumber - 1; i >= 0 && !isDocumentSeparator(lines[i]); i-- {
+		line := parseLine(lines[i])
+		if line.blank || line.indent > indent {
+			continue
+		}
+		if line.indent == indent {
+			if collectSiblings && !line.isListItem && line.key != "" {
+				result.siblings = append(result.siblings, line.key)
+			}
+			continue
+		}
+
+		if line.isListItem {
+			if collectSiblings && line.keyColumn == siblingIndent && line.key != "" {
+				result.siblings = append(result.siblings, line.key)
+			}
+			if line.keyColumn < indent && line.key != "" && !line.hasValue {
+				reversedPath = append(reversedPath, line.key)
+			}
+			reversedPath = append(reversedPath, "0")
+		} else {
+			if line.key == "" {
+				break
+			}
+			reversedPath = append(reversedPath, line.key)
+		}
+		indent = line.indent
+		collectSiblings = false
+	}
+
+	if !current.isListItem {
+		for i := lineNumber + 1; i < len(lines) && !isDocumentSeparator(lines[i]); i++ {
+			line := parseLine(lines[i])
+			if line.blank || line.indent > siblingIndent {
+				continue
+			}
+			if line.indent < siblingIndent || line.isListItem {
+				break
+			}
+			if line.key != "" {
+				result.siblings = append(result.siblings, line.key)
+			}
+		}
+	}
+
+	for i := len(reversedPath) - 1; i >= 0; i-- {
+		result.path = append(result.path, reversedPath[i])
+	}
+
+	kinds, complete := kubernetesschema.GetGroupVersionKinds(getDocumentAt(lines, lineNumber))
+	if complete && len(kinds) == 1 {
+		result.kind, result.hasKind = kinds[0], true
+	}
+	return result
+}
+
+// getDocumentAt returns the text of the YAML document that contains the line
+func getDocumentAt(lines []string, lineNumber int) string {
+	start := lineNumber
+	for start > 0 && !isDocumentSeparator(lines[start]) {
+		start--
+	}
+	if isDocumentSeparator(lines[start]) {
+		start++
+	}
+	end := lineNumber + 1
+	for end < len(lines) && !isDocumentSeparator(lines[end]) {
+		end++
+	}
+	if start > end {
+		return ""
+	}
+	return strings.Join(lines[start:end], "\n")
+}
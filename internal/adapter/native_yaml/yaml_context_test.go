@@ -0,0 +1,101 @@
+package nativeyaml
+
+import (
+	"testing"
+
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+)
+
+func TestGetYamlContext(t *testing.T) {
+	text := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx
+
+        - name:
+---
+apiVersion: v1
+kind: Service
+`
+	deployment := kubernetesschema.GroupVersionKind{APIVersion: "apps/v1", Kind: "Deployment"}
+
+	testCases := []struct {
+		desc             string
+		position         lsp.Position
+		expectedPath     []string
+		expectedKey      string
+		expectedIsValue  bool
+		expectedSiblings []string
+		expectedKind     kubernetesschema.GroupVersionKind
+	}{
+		{
+			desc:             "empty line in metadata",
+			position:         lsp.Position{Line: 4, Character: 2},
+			expectedPath:     []string{"metadata"},
+			expectedSiblings: []string{"name"},
+			expectedKind:     deployment,
+		},
+		{
+			desc:             "key at the root",
+			position:         lsp.Position{Line: 5, Character: 1},
+			expectedPath:     []string{},
+			expectedKey:      "spec",
+			expectedSiblings: []string{"kind", "apiVersion", "metadata"},
+			expectedKind:     deployment,
+		},
+		{
+			desc:             "value of a key",
+			position:         lsp.Position{Line: 6, Character: 13},
+			expectedPath:     []string{"spec"},
+			expectedKey:      "replicas",
+			expectedIsValue:  true,
+			expectedSiblings: []string{"template"},
+			expectedKind:     deployment,
+		},
+		{
+			desc:             "empty line in a list item",
+			position:         lsp.Position{Line: 12, Character: 10},
+			expectedPath:     []string{"spec", "template", "spec", "containers", "0"},
+			expectedSiblings: []string{"image", "name"},
+			expectedKind:     deployment,
+		},
+		{
+			desc:             "first key of a list item",
+			position:         lsp.Position{Line: 13, Character: 11},
+			expectedPath:     []string{"spec", "template", "spec", "containers", "0"},
+			expectedKey:      "name",
+			expectedIsValue:  false,
+			expectedSiblings: []string{},
+			expectedKind:     deployment,
+		},
+		{
+			desc:             "second document",
+			position:         lsp.Position{Line: 16, Character: 2},
+			expectedPath:     []string{},
+			expectedKey:      "kind",
+			expectedSiblings: []string{"apiVersion"},
+			expectedKind:     kubernetesschema.GroupVersionKind{APIVersion: "v1", Kind: "Service"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result := getYamlContext(text, tt.position)
+			assert.Equal(t, tt.expectedPath, result.path)
+			assert.Equal(t, tt.expectedKey, result.key)
+			assert.Equal(t, tt.expectedIsValue, result.isValue)
+			assert.ElementsMatch(t, tt.expectedSiblings, result.siblings)
+			assert.True(t, result.hasKind)
+			assert.Equal(t, tt.expectedKind, result.kind)
+		})
+	}
+}
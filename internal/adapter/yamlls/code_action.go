@@ -0,0 +1,16 @@
+package yamlls
+
+import (
+	"context"
+
+	lsp "go.lsp.dev/protocol"
+)
+
+// CallCodeAction returns the code actions of yamlls, e.g. quick fixes for schema errors
+func (yamllsConnector Connector) CallCodeAction(ctx context.Context, params *lsp.CodeActionParams) ([]lsp.CodeAction, error) {
+	server := yamllsConnector.getServerFor(params.TextDocument.URI)
+	if server == nil {
+		return []lsp.CodeAction{}, nil
+	}
+	return server.CodeAction(ctx, params)
+}
@@ -7,9 +7,10 @@ import (
 )
 
 func (yamllsConnector Connector) CallCompletion(ctx context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error) {
-	if !yamllsConnector.shouldRun(params.TextDocument.URI) {
+	server := yamllsConnector.getServerFor(params.TextDocument.URI)
+	if server == nil {
 		return &lsp.CompletionList{}, nil
 	}
 
-	return yamllsConnector.server.Completion(ctx, params)
+	return server.Completion(ctx, params)
 }
@@ -13,8 +13,9 @@ func (y Connector) Configuration(_ context.Context, _ *protocol.ConfigurationPar
 }
 
 func (y Connector) DidChangeConfiguration(ctx context.Context) (err error) {
-	if y.server == nil {
+	server := y.getServer()
+	if server == nil {
 		return nil
 	}
-	return y.server.DidChangeConfiguration(ctx, &protocol.DidChangeConfigurationParams{})
+	return server.DidChangeConfiguration(ctx, &protocol.DidChangeConfigurationParams{})
 }
@@ -56,6 +56,10 @@ func NewCustomSchemaProviderHandler(provider CustomSchemaProvider) jsonrpc2.Hand
 					logger.Error(err)
 					return reply(ctx, nil, err)
 				}
+				if schemaURI == "" {
+					// yaml-language-server uses the schemas from its settings if no schema is returned
+					continue
+				}
 				results = append(results, schemaURI)
 			}
 
@@ -0,0 +1,88 @@
+package yamlls
+
+import (
+	"testing"
+
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+)
+
+func TestFilterDiagnostics(t *testing.T) {
+	content := []byte(`replicas: {{ .Values.replicas }}
+wrong: value
+{{- if .Values.a }}
+a: b
+{{- else }}
+a: c
+{{- end }}
+`)
+	ast := templateast.ParseAst(nil, content)
+	diagnosticAt := func(message string, startLine, startCharacter, endLine, endCharacter uint32) lsp.Diagnostic {
+		return lsp.Diagnostic{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: startLine, Character: startCharacter},
+				End:   lsp.Position{Line: endLine, Character: endCharacter},
+			},
+			Message: message,
+		}
+	}
+
+	filtered := filterDiagnostics([]lsp.Diagnostic{
+		diagnosticAt("Incorrect type. Expected integer.", 0, 10, 0, 11),
+		diagnosticAt("Property wrong is not allowed.", 1, 0, 1, 5),
+		diagnosticAt("Value is too long.", 0, 0, 0, 12),
+		diagnosticAt("Map keys must be unique", 5, 0, 5, 1),
+		diagnosticAt("Implicit map keys need to be followed by map values", 1, 0, 1, 5),
+		diagnosticAt("All mapping items must start at the same column", 3, 0, 3, 1),
+	}, ast, content, util.DefaultConfig.YamllsConfiguration.DiagnosticsFilters)
+
+	expected := []lsp.Diagnostic{
+		diagnosticAt("Yamlls: Property wrong is not allowed.", 1, 0, 1, 5),
+		diagnosticAt("Yamlls: Value is too long.", 0, 0, 0, 32),
+	}
+	for i := range expected {
+		expected[i].Code = diagnosticrules.YamllsSyntax.Code
+		expected[i].CodeDescription = diagnosticrules.YamllsSyntax.CodeDescription()
+	}
+	assert.Equal(t, expected, filtered)
+}
+
+func TestFilterDiagnosticsWithConfiguredFilters(t *testing.T) {
+	content := []byte(`{{- if .Values.a }}
+a: b
+{{- end }}
+c: d
+`)
+	ast := templateast.ParseAst(nil, content)
+	diagnostics := []lsp.Diagnostic{
+		{Range: lsp.Range{Start: lsp.Position{Line: 1}, End: lsp.Position{Line: 1, Character: 1}}, Message: "Property a is not allowed.", Severity: lsp.DiagnosticSeverityError},
+		{Range: lsp.Range{Start: lsp.Position{Line: 3}, End: lsp.Position{Line: 3, Character: 1}}, Message: "Property c is not allowed.", Severity: lsp.DiagnosticSeverityError},
+		{Range: lsp.Range{Start: lsp.Position{Line: 3}, End: lsp.Position{Line: 3, Character: 1}}, Message: "Deprecated", Severity: lsp.DiagnosticSeverityWarning},
+	}
+
+	testCases := []struct {
+		desc     string
+		filters  []util.YamllsDiagnosticsFilter
+		expected []string
+	}{
+		{"no filters", nil, []string{"Yamlls: Property a is not allowed.", "Yamlls: Property c is not allowed.", "Yamlls: Deprecated"}},
+		{"message", []util.YamllsDiagnosticsFilter{{Message: "^Property"}}, []string{"Yamlls: Deprecated"}},
+		{"severity", []util.YamllsDiagnosticsFilter{{Severity: "warning"}}, []string{"Yamlls: Property a is not allowed.", "Yamlls: Property c is not allowed."}},
+		{"inside of if actions", []util.YamllsDiagnosticsFilter{{Message: "not allowed", Branch: util.BranchIf}}, []string{"Yamlls: Property c is not allowed.", "Yamlls: Deprecated"}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			config := util.YamllsConfiguration{DiagnosticsFilters: tt.filters}
+			config.CompileDiagnosticsFilters()
+
+			messages := []string{}
+			for _, diagnostic := range filterDiagnostics(diagnostics, ast, content, config.DiagnosticsFilters) {
+				messages = append(messages, diagnostic.Message)
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
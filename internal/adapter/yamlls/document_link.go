@@ -0,0 +1,15 @@
+package yamlls
+
+import (
+	"context"
+
+	lsp "go.lsp.dev/protocol"
+)
+
+func (yamllsConnector Connector) CallDocumentLink(ctx context.Context, params *lsp.DocumentLinkParams) ([]lsp.DocumentLink, error) {
+	server := yamllsConnector.getServerFor(params.TextDocument.URI)
+	if server == nil {
+		return []lsp.DocumentLink{}, nil
+	}
+	return server.DocumentLink(ctx, params)
+}
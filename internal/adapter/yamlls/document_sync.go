@@ -8,7 +8,7 @@ import (
 )
 
 func (yamllsConnector Connector) InitiallySyncOpenYamlDocuments(docs []*document.YamlDocument) {
-	if yamllsConnector.server == nil {
+	if yamllsConnector.getServer() == nil {
 		return
 	}
 
@@ -27,32 +27,35 @@ func (yamllsConnector Connector) InitiallySyncOpenYamlDocuments(docs []*document
 }
 
 func (yamllsConnector Connector) DocumentDidOpen(params *lsp.DidOpenTextDocumentParams) {
-	if yamllsConnector.server == nil {
+	server := yamllsConnector.getServer()
+	if server == nil {
 		return
 	}
-	logger.Debug("YamllsConnector DocumentDidOpen ", params.TextDocument.URI, yamllsConnector.server)
-	err := yamllsConnector.server.DidOpen(context.Background(), params)
+	logger.Debug("YamllsConnector DocumentDidOpen ", params.TextDocument.URI)
+	err := server.DidOpen(context.Background(), params)
 	if err != nil {
 		logger.Error("Error calling yamlls for didOpen", err)
 	}
 }
 
 func (yamllsConnector Connector) DocumentDidSave(params *lsp.DidSaveTextDocumentParams) {
-	if yamllsConnector.server == nil {
+	server := yamllsConnector.getServer()
+	if server == nil {
 		return
 	}
-	err := yamllsConnector.server.DidSave(context.Background(), params)
+	err := server.DidSave(context.Background(), params)
 	if err != nil {
 		logger.Error("Error calling yamlls for didSave", err)
 	}
 }
 
 func (yamllsConnector Connector) DocumentDidChange(params *lsp.DidChangeTextDocumentParams) {
-	if yamllsConnector.server == nil {
+	server := yamllsConnector.getServer()
+	if server == nil {
 		return
 	}
 	logger.Debug("Sending DocumentDidChange previous ", params.TextDocument.URI)
-	err := yamllsConnector.server.DidChange(context.Background(), params)
+	err := server.DidChange(context.Background(), params)
 	if err != nil {
 		logger.Error("Error calling yamlls for didChange", err)
 	}
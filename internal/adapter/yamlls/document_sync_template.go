@@ -3,13 +3,12 @@ package yamlls
 import (
 	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
 	"github.com/mrjosh/helm-ls/internal/lsp/document"
-	"github.com/mrjosh/helm-ls/internal/util"
 	sitter "github.com/smacker/go-tree-sitter"
 	lsp "go.lsp.dev/protocol"
 )
 
 func (yamllsConnector Connector) InitiallySyncOpenTemplateDocuments(docs []*document.TemplateDocument) {
-	if yamllsConnector.server == nil {
+	if yamllsConnector.getServer() == nil {
 		return
 	}
 
@@ -39,7 +38,7 @@ func (yamllsConnector Connector) DocumentDidOpenTemplate(ast *sitter.Tree, param
 	}
 
 	logger.Debug("YamllsConnector DocumentDidOpen", params.TextDocument.URI)
-	params.TextDocument.Text = lsplocal.TrimTemplate(ast, []byte(params.TextDocument.Text))
+	params.TextDocument.Text = lsplocal.ProjectTemplate(ast.Copy(), []byte(params.TextDocument.Text)).Text
 
 	yamllsConnector.DocumentDidOpen(&params)
 }
@@ -60,42 +59,18 @@ func (yamllsConnector Connector) DocumentDidSaveTemplate(doc *document.TemplateD
 	})
 }
 
-func (yamllsConnector Connector) DocumentDidChangeTemplate(doc *document.TemplateDocument, params lsp.DidChangeTextDocumentParams) {
-	if !yamllsConnector.shouldRun(doc.URI) {
-		return
-	}
-	trimmedText := lsplocal.TrimTemplate(doc.Ast, doc.Content)
-
-	logger.Debug("Sending DocumentDidChange previous", params)
-	for i, change := range params.ContentChanges {
-		var (
-			start = util.PositionToIndex(change.Range.Start, []byte(doc.Content))
-			end   = start + len([]byte(change.Text))
-		)
-
-		if end >= len(trimmedText) {
-			end = max(len(trimmedText)-1, 0)
-		}
-
-		logger.Debug("Start end", start, end)
-		logger.Debug("Setting change text to ", trimmedText[start:end])
-		params.ContentChanges[i].Text = trimmedText[start:end]
-	}
-
-	yamllsConnector.DocumentDidChange(&params)
-}
-
+// DocumentDidChangeFullSyncTemplate sends the whole projection of the template to yamlls.
+// Changes are always synced fully, because the placeholders of the actions depend on
+// the lines around them and can change with an edit in another part of the template.
 func (yamllsConnector Connector) DocumentDidChangeFullSyncTemplate(doc *document.TemplateDocument, params lsp.DidChangeTextDocumentParams) {
 	if !yamllsConnector.shouldRun(doc.URI) {
 		return
 	}
 
 	logger.Debug("Sending DocumentDidChange with full sync, current content:", string(doc.Content))
-	trimmedText := lsplocal.TrimTemplate(doc.Ast.Copy(), doc.Content)
-
 	params.ContentChanges = []lsp.TextDocumentContentChangeEvent{
 		{
-			Text: trimmedText,
+			Text: lsplocal.ProjectTemplate(doc.Ast.Copy(), doc.Content).Text,
 		},
 	}
 
@@ -0,0 +1,15 @@
+package yamlls
+
+import (
+	"context"
+
+	lsp "go.lsp.dev/protocol"
+)
+
+func (yamllsConnector Connector) CallFoldingRanges(ctx context.Context, params *lsp.FoldingRangeParams) ([]lsp.FoldingRange, error) {
+	server := yamllsConnector.getServerFor(params.TextDocument.URI)
+	if server == nil {
+		return []lsp.FoldingRange{}, nil
+	}
+	return server.FoldingRanges(ctx, params)
+}
@@ -0,0 +1,15 @@
+package yamlls
+
+import (
+	"context"
+
+	lsp "go.lsp.dev/protocol"
+)
+
+func (yamllsConnector Connector) CallFormatting(ctx context.Context, params *lsp.DocumentFormattingParams) ([]lsp.TextEdit, error) {
+	server := yamllsConnector.getServerFor(params.TextDocument.URI)
+	if server == nil {
+		return []lsp.TextEdit{}, nil
+	}
+	return server.Formatting(ctx, params)
+}
@@ -9,11 +9,12 @@ import (
 
 // Calls the Hover method of yamlls to get a fitting hover response
 func (yamllsConnector Connector) CallHover(ctx context.Context, params lsp.HoverParams) (*lsp.Hover, error) {
-	if !yamllsConnector.shouldRun(params.TextDocument.URI) {
+	server := yamllsConnector.getServerFor(params.TextDocument.URI)
+	if server == nil {
 		return nil, nil
 	}
 
-	return yamllsConnector.server.Hover(ctx, &params)
+	return server.Hover(ctx, &params)
 }
 
 // Calls the Hover method of yamlls to get a fitting hover response
@@ -22,11 +23,12 @@ func (yamllsConnector Connector) CallHover(ctx context.Context, params lsp.Hover
 // Yamlls can not handle hover if the schema validation returns errors,
 // thats why we fall back to calling completion
 func (yamllsConnector Connector) CallHoverOrComplete(ctx context.Context, params lsp.HoverParams, word string) (*lsp.Hover, error) {
-	if !yamllsConnector.shouldRun(params.TextDocument.URI) {
+	server := yamllsConnector.getServerFor(params.TextDocument.URI)
+	if server == nil {
 		return nil, nil
 	}
 
-	hoverResponse, err := yamllsConnector.server.Hover(ctx, &params)
+	hoverResponse, err := server.Hover(ctx, &params)
 	if err != nil {
 		return hoverResponse, err
 	}
@@ -48,7 +50,11 @@ func (yamllsConnector Connector) getHoverFromCompletion(ctx context.Context, par
 
 	word = removeTrailingColon(word)
 
-	completionList, err := yamllsConnector.server.Completion(ctx, &completionParams)
+	server := yamllsConnector.getServer()
+	if server == nil {
+		return &lsp.Hover{}, nil
+	}
+	completionList, err := server.Completion(ctx, &completionParams)
 	if err != nil {
 		logger.Error("Error calling yamlls for Completion", err)
 		return &lsp.Hover{}, err
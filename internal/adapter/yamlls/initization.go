@@ -9,8 +9,14 @@ import (
 	"go.lsp.dev/uri"
 )
 
-func (yamllsConnector Connector) CallInitialize(ctx context.Context, workspaceURI uri.URI) error {
-	if yamllsConnector.server == nil {
+func (yamllsConnector *Connector) CallInitialize(ctx context.Context, workspaceURI uri.URI) error {
+	if yamllsConnector.state != nil {
+		yamllsConnector.state.mutex.Lock()
+		yamllsConnector.state.workspaceURI = workspaceURI
+		yamllsConnector.state.mutex.Unlock()
+	}
+	server, conn := yamllsConnector.getServer(), yamllsConnector.getConn()
+	if server == nil {
 		return nil
 	}
 
@@ -35,22 +41,22 @@ func (yamllsConnector Connector) CallInitialize(ctx context.Context, workspaceUR
 	ctxT, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	_, err := yamllsConnector.server.Initialize(ctxT, &params)
+	_, err := server.Initialize(ctxT, &params)
 	if err != nil {
 		logger.Error("Error calling yamlls for initialize ", err)
 		return err
 	}
 	logger.Debug("Calling yamlls for didChangeConfiguration")
-	err = yamllsConnector.server.DidChangeConfiguration(ctx, &lsp.DidChangeConfigurationParams{})
+	err = server.DidChangeConfiguration(ctx, &lsp.DidChangeConfigurationParams{})
 	if err != nil {
 		return err
 	}
 
 	defer func() {
-		if err := yamllsConnector.customHandler.PostInitialize(ctx, yamllsConnector.conn); err != nil {
+		if err := yamllsConnector.customHandler.PostInitialize(ctx, conn); err != nil {
 			logger.Error("Failed to post-initialize custom handler:", err)
 		}
 	}()
 
-	return yamllsConnector.server.Initialized(ctx, &lsp.InitializedParams{})
+	return server.Initialized(ctx, &lsp.InitializedParams{})
 }
@@ -57,7 +57,7 @@ func getYamllsConnector(t *testing.T, config util.YamllsConfiguration, customHan
 
 	yamllsConnector := NewConnector(context.Background(), config, client, documents, customHandler)
 
-	if yamllsConnector.server == nil {
+	if yamllsConnector.getServer() == nil {
 		t.Fatal("Could not connect to yaml-language-server")
 	}
 
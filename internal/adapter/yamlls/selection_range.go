@@ -0,0 +1,21 @@
+package yamlls
+
+import (
+	"context"
+
+	lsp "go.lsp.dev/protocol"
+)
+
+// SelectionRangeMethod is not part of the server interface of the protocol package, therefore
+// the request is sent directly over the connection
+const SelectionRangeMethod = "textDocument/selectionRange"
+
+func (yamllsConnector Connector) CallSelectionRange(ctx context.Context, params *lsp.SelectionRangeParams) ([]lsp.SelectionRange, error) {
+	result := []lsp.SelectionRange{}
+	conn := yamllsConnector.getConn()
+	if !yamllsConnector.shouldRun(params.TextDocument.URI) || conn == nil {
+		return result, nil
+	}
+	_, err := conn.Call(ctx, SelectionRangeMethod, params, &result)
+	return result, err
+}
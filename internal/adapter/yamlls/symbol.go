@@ -7,8 +7,9 @@ import (
 )
 
 func (yamllsConnector Connector) CallDocumentSymbol(ctx context.Context, params *lsp.DocumentSymbolParams) (result []interface{}, err error) {
-	if !yamllsConnector.shouldRun(params.TextDocument.URI) {
+	server := yamllsConnector.getServerFor(params.TextDocument.URI)
+	if server == nil {
 		return []interface{}{}, nil
 	}
-	return yamllsConnector.server.DocumentSymbol(ctx, params)
+	return server.DocumentSymbol(ctx, params)
 }
@@ -2,9 +2,13 @@ package yamlls
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"sync"
+	"time"
 
 	"github.com/gobwas/glob"
 	"github.com/mrjosh/helm-ls/internal/log"
@@ -12,15 +16,22 @@ import (
 	"github.com/mrjosh/helm-ls/internal/util"
 	"go.lsp.dev/jsonrpc2"
 	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
 	"go.uber.org/zap"
 )
 
 var logger = log.GetLogger()
 
+var (
+	// restartBackoff is the delay before the first restart of yamlls, it is doubled for every further restart
+	restartBackoff    = time.Second
+	maxRestartBackoff = 30 * time.Second
+)
+
 type Connector struct {
 	config util.YamllsConfiguration
-	server lsp.Server
-	conn   jsonrpc2.Conn
+	// state is shared by all copies of the connector, it is modified by the supervisor of the yamlls process
+	state *connectorState
 	// IDEA: yamlls only needs GetTemplateDoc (or abstracted GetDocument)
 	// so we introduce a new interface for this method and not access the whole document store
 	documents                 *document.DocumentStore
@@ -29,29 +40,154 @@ type Connector struct {
 	EnabledForFilesGlobObject glob.Glob
 }
 
+type connectorState struct {
+	mutex  sync.RWMutex
+	server lsp.Server
+	conn   jsonrpc2.Conn
+	// process is the running yamlls subprocess of conn
+	process *os.Process
+	// workspaceURI is the uri of the last initialize call, it is used to initialize yamlls again after a restart
+	workspaceURI uri.URI
+	// onRestart is called after yamlls was restarted and initialized, it should sync the open documents
+	onRestart func()
+	// restarts counts the restarts since the last successful start, it is reset once a restarted server is initialized
+	restarts int
+	// stopped is set on shutdown, the server is not restarted afterwards
+	stopped bool
+}
+
 func NewConnector(ctx context.Context,
 	yamllsConfiguration util.YamllsConfiguration,
 	client lsp.Client,
 	documents *document.DocumentStore,
 	customHandler *CustomHandler,
 ) *Connector {
-	yamllsCmd := exec.Command(yamllsConfiguration.Path, "--stdio")
+	yamllsConnector := Connector{
+		config:                    yamllsConfiguration,
+		documents:                 documents,
+		client:                    client,
+		customHandler:             customHandler,
+		EnabledForFilesGlobObject: yamllsConfiguration.EnabledForFilesGlobObject,
+		state:                     &connectorState{},
+	}
+
+	if err := yamllsConnector.start(ctx); err != nil {
+		logger.Error("Could not start yaml-language-server, some features may be missing.", err)
+		return &Connector{}
+	}
+	return &yamllsConnector
+}
+
+// SetRestartCallback sets the function that is called after yamlls was restarted and initialized again
+func (yamllsConnector *Connector) SetRestartCallback(onRestart func()) {
+	if yamllsConnector.state == nil {
+		return
+	}
+	yamllsConnector.state.mutex.Lock()
+	defer yamllsConnector.state.mutex.Unlock()
+	yamllsConnector.state.onRestart = onRestart
+}
+
+// Stop closes the connection to yamlls, the process is killed by its supervisor and not restarted
+func (yamllsConnector *Connector) Stop() {
+	if yamllsConnector.state == nil {
+		return
+	}
+	yamllsConnector.state.mutex.Lock()
+	yamllsConnector.state.stopped = true
+	conn := yamllsConnector.state.conn
+	yamllsConnector.state.server = nil
+	yamllsConnector.state.conn = nil
+	yamllsConnector.state.process = nil
+	yamllsConnector.state.mutex.Unlock()
+
+	if conn != nil {
+		if err := conn.Close(); err != nil {
+			logger.Error("Error closing connection to yaml-language-server", err)
+		}
+	}
+}
+
+func (yamllsConnector Connector) getServer() lsp.Server {
+	if yamllsConnector.state == nil {
+		return nil
+	}
+	yamllsConnector.state.mutex.RLock()
+	defer yamllsConnector.state.mutex.RUnlock()
+	return yamllsConnector.state.server
+}
+
+func (yamllsConnector Connector) getConn() jsonrpc2.Conn {
+	if yamllsConnector.state == nil {
+		return nil
+	}
+	yamllsConnector.state.mutex.RLock()
+	defer yamllsConnector.state.mutex.RUnlock()
+	return yamllsConnector.state.conn
+}
+
+func (yamllsConnector Connector) isStopped() bool {
+	yamllsConnector.state.mutex.RLock()
+	defer yamllsConnector.state.mutex.RUnlock()
+	return yamllsConnector.state.stopped
+}
+
+// setConnection stores the connection of a started server, it returns false if the connector was stopped in the meantime
+func (yamllsConnector Connector) setConnection(server lsp.Server, conn jsonrpc2.Conn, process *os.Process) bool {
+	yamllsConnector.state.mutex.Lock()
+	defer yamllsConnector.state.mutex.Unlock()
+	if yamllsConnector.state.stopped {
+		return false
+	}
+	yamllsConnector.state.server = server
+	yamllsConnector.state.conn = conn
+	yamllsConnector.state.process = process
+	return true
+}
+
+// killProcess kills the running yamlls subprocess, its supervisor restarts it like after a crash
+func (yamllsConnector Connector) killProcess() {
+	yamllsConnector.state.mutex.RLock()
+	process := yamllsConnector.state.process
+	yamllsConnector.state.mutex.RUnlock()
+	if process == nil {
+		return
+	}
+	if err := process.Kill(); err != nil {
+		logger.Error("Could not kill yaml-language-server", err)
+	}
+}
+
+// clearConnection removes the connection of an exited server, it returns false if the connector was stopped
+func (yamllsConnector Connector) clearConnection(conn jsonrpc2.Conn) bool {
+	yamllsConnector.state.mutex.Lock()
+	defer yamllsConnector.state.mutex.Unlock()
+	if yamllsConnector.state.stopped {
+		return false
+	}
+	if yamllsConnector.state.conn == conn {
+		yamllsConnector.state.server = nil
+		yamllsConnector.state.conn = nil
+		yamllsConnector.state.process = nil
+	}
+	return true
+}
+
+// start spawns the yamlls subprocess and restarts it when it exits
+func (yamllsConnector *Connector) start(ctx context.Context) error {
+	yamllsCmd := exec.Command(yamllsConnector.config.Path, "--stdio")
 
 	stdin, err := yamllsCmd.StdinPipe()
 	if err != nil {
-		logger.Error("Could not connect to stdin of yaml-language-server, some features may be missing.")
-		return &Connector{}
+		return fmt.Errorf("could not connect to stdin of yaml-language-server: %w", err)
 	}
 	stout, err := yamllsCmd.StdoutPipe()
 	if err != nil {
-		logger.Error("Could not connect to stdout of yaml-language-server, some features may be missing.")
-		return &Connector{}
+		return fmt.Errorf("could not connect to stdout of yaml-language-server: %w", err)
 	}
-
 	strderr, err := yamllsCmd.StderrPipe()
 	if err != nil {
-		logger.Error("Could not connect to stderr of yaml-language-server, some features may be missing.")
-		return &Connector{}
+		return fmt.Errorf("could not connect to stderr of yaml-language-server: %w", err)
 	}
 
 	readWriteCloser := readWriteCloseSubprocess{
@@ -59,49 +195,115 @@ func NewConnector(ctx context.Context,
 		stdin,
 	}
 
-	logger.Debug("Starting yaml-language-server ", yamllsConfiguration.Path)
+	logger.Debug("Starting yaml-language-server ", yamllsConnector.config.Path)
 
 	err = yamllsCmd.Start()
 	if err != nil {
-		switch e := err.(type) {
-		case *exec.Error:
-			logger.Error("Could not start yaml-language-server, some features may be missing. Spawning subprocess failed.", err)
-			return &Connector{}
-		case *exec.ExitError:
-			logger.Error("Could not start yaml-language-server, some features may be missing. Command exit rc =", e.ExitCode())
-			return &Connector{}
-		default:
-			logger.Error("Could not start yaml-language-server, some features may be missing. Spawning subprocess failed.", err)
-			return &Connector{}
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			return fmt.Errorf("spawning subprocess failed with exit code %d: %w", exitError.ExitCode(), err)
 		}
+		return fmt.Errorf("spawning subprocess failed: %w", err)
 	}
 
-	logger.Debug("Started yaml-language-server ", yamllsConfiguration.Path)
+	logger.Debug("Started yaml-language-server ", yamllsConnector.config.Path)
 
 	go func() {
 		io.Copy(os.Stderr, strderr)
 	}()
 
-	yamllsConnector := Connector{
-		config:                    yamllsConfiguration,
-		documents:                 documents,
-		client:                    client,
-		customHandler:             customHandler,
-		EnabledForFilesGlobObject: yamllsConfiguration.EnabledForFilesGlobObject,
-	}
-
 	zapLogger, _ := zap.NewProduction()
 	_, conn, server := yamllsConnector.CustomNewClient(ctx, yamllsConnector, jsonrpc2.NewStream(readWriteCloser), zapLogger)
 
+	stopped := !yamllsConnector.setConnection(server, conn, yamllsCmd.Process)
+
 	go func() {
 		<-conn.Done()
-		logger.Error("yaml-language-server exited unexpectedly")
-		yamllsConnector.server = nil
+		// the process may still be running if only the connection failed
+		_ = yamllsCmd.Process.Kill()
+		_ = yamllsCmd.Wait()
+		if !yamllsConnector.clearConnection(conn) {
+			logger.Debug("yaml-language-server stopped")
+			return
+		}
+		logger.Error("yaml-language-server exited unexpectedly", conn.Err())
+		yamllsConnector.restart(ctx)
 	}()
 
-	yamllsConnector.server = server
-	yamllsConnector.conn = conn
-	return &yamllsConnector
+	if stopped {
+		_ = conn.Close()
+		return errors.New("yaml-language-server was stopped")
+	}
+	return nil
+}
+
+// restart starts yamlls again with an exponential backoff until the configured number of restarts is reached.
+// The restarted server is initialized with the workspace of the last initialize call before the open documents are synced.
+func (yamllsConnector *Connector) restart(ctx context.Context) {
+	for {
+		state := yamllsConnector.state
+		state.mutex.Lock()
+		if state.stopped {
+			state.mutex.Unlock()
+			return
+		}
+		if state.restarts >= yamllsConnector.config.MaxRestarts {
+			state.mutex.Unlock()
+			break
+		}
+		backoff := min(restartBackoff<<state.restarts, maxRestartBackoff)
+		state.restarts++
+		restarts := state.restarts
+		state.mutex.Unlock()
+		logger.Println(fmt.Sprintf("Restarting yaml-language-server in %s (attempt %d of %d)", backoff, restarts, yamllsConnector.config.MaxRestarts))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if yamllsConnector.isStopped() {
+			return
+		}
+
+		if err := yamllsConnector.start(ctx); err != nil {
+			logger.Error("Could not restart yaml-language-server", err)
+			continue
+		}
+		state.mutex.RLock()
+		workspaceURI := state.workspaceURI
+		state.mutex.RUnlock()
+		// if the restarted server exits again it is restarted by its own supervisor
+		if err := yamllsConnector.CallInitialize(ctx, workspaceURI); err != nil {
+			logger.Error("Error initializing restarted yaml-language-server", err)
+			// handled like a crash: the supervisor of the restarted server restarts it again
+			// with the next backoff, this attempt is already counted
+			yamllsConnector.killProcess()
+			return
+		}
+
+		state.mutex.Lock()
+		state.restarts = 0
+		onRestart := state.onRestart
+		state.mutex.Unlock()
+		if onRestart != nil {
+			onRestart()
+		}
+		return
+	}
+
+	logger.Error("yaml-language-server could not be restarted, giving up")
+	if yamllsConnector.client == nil {
+		return
+	}
+	err := yamllsConnector.client.ShowMessage(ctx, &lsp.ShowMessageParams{
+		Type: lsp.MessageTypeError,
+		Message: fmt.Sprintf("yaml-language-server stopped and could not be restarted %d times, "+
+			"features that depend on it are disabled until helm-ls is restarted", yamllsConnector.config.MaxRestarts),
+	})
+	if err != nil {
+		logger.Error("Error showing message about yaml-language-server", err)
+	}
 }
 
 func (yamllsConnector *Connector) isRelevantFile(uri lsp.URI) bool {
@@ -114,9 +316,19 @@ func (yamllsConnector *Connector) isRelevantFile(uri lsp.URI) bool {
 }
 
 func (yamllsConnector *Connector) shouldRun(uri lsp.DocumentURI) bool {
-	if yamllsConnector.server == nil {
-		return false
+	return yamllsConnector.getServerFor(uri) != nil
+}
+
+// getServerFor returns the running server if the document is relevant for yamlls, nil otherwise
+func (yamllsConnector *Connector) getServerFor(uri lsp.DocumentURI) lsp.Server {
+	server := yamllsConnector.getServer()
+	if server == nil || !yamllsConnector.isRelevantFile(uri) {
+		return nil
 	}
+	return server
+}
 
-	return yamllsConnector.isRelevantFile(uri)
+// IsRunning returns true if the yamlls subprocess was started and is connected
+func (yamllsConnector *Connector) IsRunning() bool {
+	return yamllsConnector.getServer() != nil
 }
@@ -1,12 +1,18 @@
 package yamlls
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/mrjosh/helm-ls/internal/lsp/document"
 	"github.com/mrjosh/helm-ls/internal/util"
+	mocks "github.com/mrjosh/helm-ls/mocks/go.lsp.dev/protocol"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	lsp "go.lsp.dev/protocol"
 	"go.lsp.dev/uri"
 )
 
@@ -43,3 +49,88 @@ func TestShouldRun(t *testing.T) {
 	assert.False(t, connector.shouldRun(uri.File("test.yaml")))
 	assert.False(t, connector.shouldRun(uri.File("_helpers.tpl")))
 }
+
+func TestRestartsUntilMaxRestartsAndShowsMessage(t *testing.T) {
+	restartBackoff = time.Millisecond
+	defer func() { restartBackoff = time.Second }()
+
+	mockClient := mocks.NewMockClient(t)
+	messages := make(chan *lsp.ShowMessageParams, 1)
+	mockClient.EXPECT().ShowMessage(mock.Anything, mock.Anything).Run(func(_ context.Context, params *lsp.ShowMessageParams) {
+		messages <- params
+	}).Return(nil).Once()
+
+	config := util.DefaultConfig.YamllsConfiguration
+	// false exits immediately, so every start of the server is followed by a restart
+	config.Path = "false"
+	config.MaxRestarts = 2
+	connector := NewConnector(context.Background(), config, mockClient, document.NewDocumentStore(), &DefaultCustomHandler)
+
+	select {
+	case message := <-messages:
+		assert.Equal(t, lsp.MessageTypeError, message.Type)
+		assert.Contains(t, message.Message, "could not be restarted 2 times")
+	case <-time.After(10 * time.Second):
+		t.Fatal("yaml-language-server was not given up")
+	}
+	connector.state.mutex.RLock()
+	assert.Equal(t, 2, connector.state.restarts)
+	connector.state.mutex.RUnlock()
+	assert.Nil(t, connector.getServer())
+}
+
+func TestStopPreventsRestart(t *testing.T) {
+	restartBackoff = 50 * time.Millisecond
+	defer func() { restartBackoff = time.Second }()
+
+	// the mock fails the test if the message about giving up is shown
+	mockClient := mocks.NewMockClient(t)
+
+	config := util.DefaultConfig.YamllsConfiguration
+	config.Path = "false"
+	config.MaxRestarts = 2
+	connector := NewConnector(context.Background(), config, mockClient, document.NewDocumentStore(), &DefaultCustomHandler)
+	connector.Stop()
+
+	time.Sleep(5 * restartBackoff)
+
+	connector.state.mutex.RLock()
+	assert.True(t, connector.state.stopped)
+	assert.LessOrEqual(t, connector.state.restarts, 1)
+	connector.state.mutex.RUnlock()
+	assert.False(t, connector.IsRunning())
+}
+
+func TestRestartsWhenInitializeFailsAfterRestart(t *testing.T) {
+	restartBackoff = time.Millisecond
+	defer func() { restartBackoff = time.Second }()
+
+	// the server keeps running but never answers, so initialize times out
+	path := filepath.Join(t.TempDir(), "yaml-language-server")
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nexec sleep 60\n"), 0o755))
+
+	mockClient := mocks.NewMockClient(t)
+	messages := make(chan *lsp.ShowMessageParams, 1)
+	mockClient.EXPECT().ShowMessage(mock.Anything, mock.Anything).Run(func(_ context.Context, params *lsp.ShowMessageParams) {
+		messages <- params
+	}).Return(nil).Once()
+
+	config := util.DefaultConfig.YamllsConfiguration
+	config.Path = path
+	config.MaxRestarts = 2
+	config.InitTimeoutSeconds = 0
+	connector := NewConnector(context.Background(), config, mockClient, document.NewDocumentStore(), &DefaultCustomHandler)
+	defer connector.Stop()
+	// the first server crashes, the restarted ones fail to initialize
+	connector.killProcess()
+
+	select {
+	case message := <-messages:
+		assert.Contains(t, message.Message, "could not be restarted 2 times")
+	case <-time.After(10 * time.Second):
+		t.Fatal("yaml-language-server was not restarted after the failed initialize")
+	}
+	connector.state.mutex.RLock()
+	assert.Equal(t, 2, connector.state.restarts)
+	connector.state.mutex.RUnlock()
+}
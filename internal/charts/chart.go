@@ -26,7 +26,7 @@ type Chart struct {
 func NewChart(rootURI uri.URI, valuesFilesConfig util.ValuesFilesConfig) *Chart {
 	helmChart := loadHelmChart(rootURI)
 
-	return &Chart{
+	chart := &Chart{
 		ValuesFiles: NewValuesFiles(rootURI,
 			valuesFilesConfig.MainValuesFileName,
 			valuesFilesConfig.LintOverlayValuesFileName,
@@ -36,6 +36,17 @@ func NewChart(rootURI uri.URI, valuesFilesConfig util.ValuesFilesConfig) *Chart
 		ParentChart:   newParentChart(rootURI),
 		HelmChart:     helmChart,
 	}
+	chart.applyActiveProfile(valuesFilesConfig.ActiveProfiles)
+	chart.ValuesFiles.SetOverrides(rootURI, NewValuesOverrides(valuesFilesConfig))
+
+	return chart
+}
+
+func (c *Chart) applyActiveProfile(activeProfiles map[string][]string) {
+	err := c.ValuesFiles.SetActiveProfile(activeProfiles[c.Name()])
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error setting the active values profile for chart %s: %s", c.Name(), err.Error()))
+	}
 }
 
 func loadHelmChart(rootURI uri.URI) (helmChart *chart.Chart) {
@@ -0,0 +1,81 @@
+package charts
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.lsp.dev/uri"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// CRDsDirName is the directory of a chart that contains the CustomResourceDefinitions
+const CRDsDirName = "crds"
+
+// GetChartDirForCRDFile returns the directory of the chart if the file is in its crds directory
+func GetChartDirForCRDFile(file uri.URI) (string, bool) {
+	dir := filepath.Dir(file.Filename())
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		if filepath.Base(dir) == CRDsDirName && isChartDirectory(parent) {
+			return parent, true
+		}
+		dir = parent
+	}
+}
+
+// ReloadCRDs reads the files in the crds directory of the chart and of its unpacked dependencies again
+func (c *Chart) ReloadCRDs() {
+	if c.HelmChart == nil {
+		return
+	}
+	reloadCRDs(c.HelmChart, c.RootURI.Filename())
+}
+
+func reloadCRDs(helmChart *chart.Chart, rootDir string) {
+	files := []*chart.File{}
+	for _, file := range helmChart.Files {
+		if !strings.HasPrefix(file.Name, CRDsDirName+"/") {
+			files = append(files, file)
+		}
+	}
+
+	err := filepath.WalkDir(filepath.Join(rootDir, CRDsDirName), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &chart.File{Name: filepath.ToSlash(name), Data: data})
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		logger.Error("Error reloading CRDs of chart", rootDir, err)
+	}
+
+	helmChart.Files = files
+
+	for _, dependency := range helmChart.Dependencies() {
+		dependencyDir := filepath.Join(rootDir, "charts", dependency.Name())
+		if isChartDirectory(dependencyDir) {
+			reloadCRDs(dependency, dependencyDir)
+		}
+	}
+}
+
+// getParentChartDir returns the directory of the chart that contains the chart as an unpacked dependency
+func getParentChartDir(chartDir string) (string, bool) {
+	chartsDir := filepath.Dir(chartDir)
+	parent := filepath.Dir(chartsDir)
+	return parent, filepath.Base(chartsDir) == "charts" && isChartDirectory(parent)
+}
@@ -2,6 +2,7 @@ package charts
 
 import (
 	"path/filepath"
+	"slices"
 
 	"github.com/mrjosh/helm-ls/internal/util"
 	"go.lsp.dev/uri"
@@ -47,7 +48,14 @@ func (s *ChartStore) SetValuesFilesConfig(valuesFilesConfig util.ValuesFilesConf
 	logger.Debug("SetValuesFilesConfig", valuesFilesConfig)
 	if valuesFilesConfig.MainValuesFileName == s.valuesFilesConfig.MainValuesFileName &&
 		valuesFilesConfig.AdditionalValuesFilesGlobPattern == s.valuesFilesConfig.AdditionalValuesFilesGlobPattern &&
-		valuesFilesConfig.LintOverlayValuesFileName == s.valuesFilesConfig.LintOverlayValuesFileName {
+		valuesFilesConfig.LintOverlayValuesFileName == s.valuesFilesConfig.LintOverlayValuesFileName &&
+		slices.Equal(valuesFilesConfig.SetValues, s.valuesFilesConfig.SetValues) &&
+		slices.Equal(valuesFilesConfig.SetStringValues, s.valuesFilesConfig.SetStringValues) &&
+		slices.Equal(valuesFilesConfig.SetJSONValues, s.valuesFilesConfig.SetJSONValues) {
+		s.valuesFilesConfig = valuesFilesConfig
+		for _, chart := range s.Charts {
+			chart.applyActiveProfile(valuesFilesConfig.ActiveProfiles)
+		}
 		return
 	}
 	s.valuesFilesConfig = valuesFilesConfig
@@ -96,6 +104,22 @@ func (s *ChartStore) ReloadValuesFile(file uri.URI) {
 	}
 }
 
+// ReloadCRDFile reloads the CRDs of the chart whose crds directory contains the file
+// and of the charts that contain this chart as a dependency
+func (s *ChartStore) ReloadCRDFile(file uri.URI) {
+	logger.Println("Reloading CRD file", file)
+	chartDir, ok := GetChartDirForCRDFile(file)
+	for ok {
+		chart, err := s.GetChartForURI(uri.File(chartDir))
+		if err != nil {
+			logger.Error("Error reloading CRD file", file, err)
+		} else {
+			chart.ReloadCRDs()
+		}
+		chartDir, ok = getParentChartDir(chartDir)
+	}
+}
+
 func (s *ChartStore) loadChartDependencies(chart *Chart) {
 	for _, dependency := range chart.HelmChart.Dependencies() {
 		dependencyURI := chart.GetDependecyURI(dependency.Name())
@@ -104,3 +104,64 @@ func TestReloadValuesFiles(t *testing.T) {
 	s.ReloadValuesFile(uri.File(filepath.Join(tempDir, "notfound.yaml")))
 	s.ReloadValuesFile(uri.File("/notFound.yaml"))
 }
+
+func TestReloadCRDFile(t *testing.T) {
+	tempDir := t.TempDir()
+	crdsDir := filepath.Join(tempDir, "crds", "nested")
+	assert.NoError(t, os.MkdirAll(crdsDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	crdFile := filepath.Join(crdsDir, "crd.yaml")
+	assert.NoError(t, os.WriteFile(crdFile, []byte("kind: CustomResourceDefinition"), 0o644))
+
+	s := NewChartStore(uri.File(tempDir), NewChart, addChartCallback)
+	chart, err := s.GetChartForURI(uri.File(tempDir))
+	assert.NoError(t, err)
+	assert.Len(t, chart.HelmChart.CRDObjects(), 1)
+
+	chartDir, ok := GetChartDirForCRDFile(uri.File(crdFile))
+	assert.True(t, ok)
+	assert.Equal(t, tempDir, chartDir)
+	_, ok = GetChartDirForCRDFile(uri.File(filepath.Join(tempDir, "values.yaml")))
+	assert.False(t, ok)
+
+	assert.NoError(t, os.WriteFile(crdFile, []byte("kind: CustomResourceDefinition\n# changed"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "crds", "other.yaml"), []byte("kind: CustomResourceDefinition"), 0o644))
+	s.ReloadCRDFile(uri.File(crdFile))
+
+	crds := chart.HelmChart.CRDObjects()
+	assert.Len(t, crds, 2)
+	for _, crd := range crds {
+		if crd.Name == "crds/nested/crd.yaml" {
+			assert.Contains(t, string(crd.File.Data), "# changed")
+		}
+	}
+}
+
+func TestReloadCRDFileOfDependency(t *testing.T) {
+	tempDir := t.TempDir()
+	dependencyDir := filepath.Join(tempDir, "charts", "dependency")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dependencyDir, "crds"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dependencyDir, "Chart.yaml"), []byte("name: dependency\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	crdFile := filepath.Join(dependencyDir, "crds", "crd.yaml")
+	assert.NoError(t, os.WriteFile(crdFile, []byte("kind: CustomResourceDefinition"), 0o644))
+
+	s := NewChartStore(uri.File(tempDir), NewChart, addChartCallback)
+	chart, err := s.GetChartForURI(uri.File(tempDir))
+	assert.NoError(t, err)
+	assert.Len(t, chart.HelmChart.CRDObjects(), 1)
+
+	assert.NoError(t, os.WriteFile(crdFile, []byte("kind: CustomResourceDefinition\n# changed"), 0o644))
+	chart.ReloadCRDs()
+
+	crds := chart.HelmChart.CRDObjects()
+	assert.Len(t, crds, 1)
+	assert.Contains(t, string(crds[0].File.Data), "# changed")
+
+	assert.NoError(t, os.WriteFile(crdFile, []byte("kind: CustomResourceDefinition\n# changed again"), 0o644))
+	s.ReloadCRDFile(uri.File(crdFile))
+
+	crds = chart.HelmChart.CRDObjects()
+	assert.Len(t, crds, 1)
+	assert.Contains(t, string(crds[0].File.Data), "# changed again")
+}
@@ -255,3 +255,18 @@ func TestGetValueLocation(t *testing.T) {
 
 	assert.Equal(t, expected, valueLocation)
 }
+
+func TestNewChartAppliesActiveProfile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("apiVersion: v2\nname: hello-world\nversion: 0.1.0"), 0o644)
+	_ = os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(`foo: main`), 0o644)
+	_ = os.WriteFile(filepath.Join(tempDir, "values-prod.yaml"), []byte(`foo: prod`), 0o644)
+
+	valuesFilesConfig := util.DefaultConfig.ValuesFilesConfig
+	valuesFilesConfig.ActiveProfiles = map[string][]string{"hello-world": {"values.yaml", "values-prod.yaml"}}
+	chart := charts.NewChart(uri.File(tempDir), valuesFilesConfig)
+
+	assert.Len(t, chart.ValuesFiles.ActiveProfile, 2)
+	assert.Equal(t, "prod", chart.ValuesFiles.GetRenderValues()["foo"])
+}
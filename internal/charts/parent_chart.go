@@ -42,3 +42,13 @@ func (p *ParentChart) GetParentChartRecursive(chartStore *ChartStore) *Chart {
 	}
 	return parentChart
 }
+
+// GetTopChartRootURI returns the root of the top chart that contains the chart as an unpacked dependency
+// (e.g. a library chart in charts/) or the root of the chart itself if it is not a dependency
+func (c *Chart) GetTopChartRootURI() uri.URI {
+	rootURI := c.RootURI
+	for parent := newParentChart(rootURI); parent.HasParent; parent = newParentChart(rootURI) {
+		rootURI = parent.ParentChartURI
+	}
+	return rootURI
+}
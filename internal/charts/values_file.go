@@ -16,6 +16,8 @@ type ValuesFile struct {
 	ValueNode  yaml.Node
 	URI        uri.URI
 	rawContent []byte
+	// version is increased on every reload
+	version uint64
 }
 
 func NewValuesFileFromPath(filePath string) *ValuesFile {
@@ -44,6 +46,7 @@ func (v *ValuesFile) Reload() {
 	logger.Debug("Reloading values file", v.URI.Filename(), vals)
 	v.Values = vals
 	v.ValueNode = valueNodes
+	v.version++
 }
 
 func readInValuesFile(filePath string) (chartutil.Values, yaml.Node) {
@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"slices"
 
+	"github.com/mitchellh/copystructure"
 	"github.com/mrjosh/helm-ls/internal/util"
 	lsp "go.lsp.dev/protocol"
+	"helm.sh/helm/v3/pkg/chartutil"
 
 	"go.lsp.dev/uri"
 )
@@ -15,6 +17,17 @@ type ValuesFiles struct {
 	MainValuesFile        *ValuesFile
 	OverlayValuesFile     *ValuesFile
 	AdditionalValuesFiles []*ValuesFile
+	// ActiveProfile is an ordered list of values files that are coalesced like `helm -f a -f b`,
+	// if it is empty all values files are used for hover and completion
+	ActiveProfile []*ValuesFile
+	// Overrides are applied on top of the values files for lint and rendering
+	Overrides ValuesOverrides
+	// OverridesValuesFile contains only the values of the Overrides, it is used for hover and completion
+	OverridesValuesFile *ValuesFile
+	// rootDir is the directory of the chart, names of values files are relative to it
+	rootDir string
+	// version is increased when the active profile or the overrides change
+	version uint64
 }
 
 func NewValuesFiles(rootURI uri.URI, mainValuesFileName string, lintOverlayValuesFile string, additionalValuesFilesGlob string) *ValuesFiles {
@@ -26,6 +39,7 @@ func NewValuesFiles(rootURI uri.URI, mainValuesFileName string, lintOverlayValue
 		MainValuesFile:        NewValuesFileFromPath(filepath.Join(rootURI.Filename(), mainValuesFileName)),
 		OverlayValuesFile:     overlayValuesFile,
 		AdditionalValuesFiles: additionalValuesFiles,
+		rootDir:               rootURI.Filename(),
 	}
 }
 
@@ -87,3 +101,109 @@ func (v *ValuesFiles) GetPositionsForValue(query []string) []lsp.Location {
 
 	return result
 }
+
+// GetValuesFileByName returns the main, overlay or one of the additional values files
+// with the given path, relative paths are resolved against the chart root
+func (v *ValuesFiles) GetValuesFileByName(name string) (*ValuesFile, bool) {
+	path := filepath.Clean(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(v.rootDir, path)
+	}
+	for _, valuesFile := range append(v.AllValuesFiles(), v.OverlayValuesFile) {
+		if valuesFile == nil {
+			continue
+		}
+		if filepath.Clean(valuesFile.URI.Filename()) == path {
+			return valuesFile, true
+		}
+	}
+	return nil, false
+}
+
+// SetActiveProfile sets the active values profile to the values files with the given names,
+// an empty list removes the active profile
+func (v *ValuesFiles) SetActiveProfile(valuesFileNames []string) error {
+	profile := []*ValuesFile{}
+	for _, name := range valuesFileNames {
+		valuesFile, ok := v.GetValuesFileByName(name)
+		if !ok {
+			return fmt.Errorf("values file %s not found", name)
+		}
+		profile = append(profile, valuesFile)
+	}
+
+	if len(profile) == 0 {
+		profile = nil
+	}
+	v.ActiveProfile = profile
+	v.version++
+	return nil
+}
+
+// Version changes whenever the values of the files, the active profile or the overrides change,
+// it can be used to invalidate results that depend on the values
+func (v *ValuesFiles) Version() uint64 {
+	version := v.version
+	for _, valuesFile := range append(v.AllValuesFiles(), v.OverlayValuesFile) {
+		if valuesFile != nil {
+			version += valuesFile.version
+		}
+	}
+	return version
+}
+
+// HasActiveProfile returns true if a values profile is active
+func (v *ValuesFiles) HasActiveProfile() bool {
+	return len(v.ActiveProfile) > 0
+}
+
+// ActiveValuesFiles returns the values files of the active profile or all values files
+// if no profile is active, followed by the values file containing the overrides
+func (v *ValuesFiles) ActiveValuesFiles() []*ValuesFile {
+	result := v.AllValuesFiles()
+	if v.HasActiveProfile() {
+		result = slices.Clone(v.ActiveProfile)
+	}
+	if v.OverridesValuesFile != nil {
+		result = append(result, v.OverridesValuesFile)
+	}
+	return result
+}
+
+// GetRenderValues returns the coalesced values of the active profile or
+// the values of the main values file if no profile is active, the overrides are applied on top
+func (v *ValuesFiles) GetRenderValues() chartutil.Values {
+	if len(v.ActiveProfile) > 0 {
+		return v.CoalesceWithOverrides(v.ActiveProfile...)
+	}
+	return v.CoalesceWithOverrides(v.MainValuesFile)
+}
+
+// GetLintValues returns the coalesced values of the active profile or
+// the values of the main values file with the lint overlay values file if no profile is active,
+// the overrides are applied on top
+func (v *ValuesFiles) GetLintValues() chartutil.Values {
+	if len(v.ActiveProfile) > 0 {
+		return v.CoalesceWithOverrides(v.ActiveProfile...)
+	}
+	return v.CoalesceWithOverrides(v.MainValuesFile, v.OverlayValuesFile)
+}
+
+// CoalesceValuesFiles merges the values of the given files like helm does
+// when passing them with `-f`, values of later files take precedence.
+// The values of the files are not modified.
+func CoalesceValuesFiles(valuesFiles ...*ValuesFile) chartutil.Values {
+	result := chartutil.Values{}
+	for _, valuesFile := range valuesFiles {
+		if valuesFile == nil || valuesFile.Values == nil {
+			continue
+		}
+		vals, err := copystructure.Copy(valuesFile.Values.AsMap())
+		if err != nil {
+			logger.Error(fmt.Sprintf("Error copying values of %s", valuesFile.URI.Filename()), err)
+			continue
+		}
+		result = chartutil.CoalesceTables(vals.(map[string]interface{}), result)
+	}
+	return result
+}
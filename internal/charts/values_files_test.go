@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/util"
 	"github.com/stretchr/testify/assert"
 	lsp "go.lsp.dev/protocol"
 	"go.lsp.dev/uri"
@@ -93,3 +94,121 @@ func TestNewValuesFileForLintOverlayPicksFirst(t *testing.T) {
 
 	assert.Equal(t, "baz", valuesFiles.OverlayValuesFile.Values["bar"])
 }
+
+func TestCoalesceValuesFiles(t *testing.T) {
+	first := charts.NewValuesFileFromContent(uri.File("values.yaml"), []byte(`
+image:
+  repository: nginx
+  tag: "1.0"
+replicas: 1`))
+	second := charts.NewValuesFileFromContent(uri.File("values-prod.yaml"), []byte(`
+image:
+  tag: "2.0"
+replicas: 3`))
+
+	result := charts.CoalesceValuesFiles(first, second)
+
+	assert.Equal(t, map[string]interface{}{"repository": "nginx", "tag": "2.0"}, result["image"])
+	assert.Equal(t, 3.0, result["replicas"])
+	// the values of the files must not be modified
+	assert.Equal(t, "1.0", first.Values["image"].(map[string]interface{})["tag"])
+	assert.Nil(t, second.Values["image"].(map[string]interface{})["repository"])
+}
+
+func TestGetValuesFileByName(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(`foo: bar`), 0o644)
+	_ = os.WriteFile(filepath.Join(tempDir, "values-prod.yaml"), []byte(`foo: baz`), 0o644)
+
+	valuesFiles := charts.NewValuesFiles(uri.File(tempDir), "values.yaml", "", "values*.yaml")
+
+	valuesFile, ok := valuesFiles.GetValuesFileByName("values-prod.yaml")
+	assert.True(t, ok)
+	assert.Equal(t, "baz", valuesFile.Values["foo"])
+
+	_, ok = valuesFiles.GetValuesFileByName("values-missing.yaml")
+	assert.False(t, ok)
+}
+
+func TestGetValuesFileByNameWithSameBaseName(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_ = os.MkdirAll(filepath.Join(tempDir, "ci"), 0o755)
+	_ = os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(`foo: main`), 0o644)
+	_ = os.WriteFile(filepath.Join(tempDir, "ci", "values.yaml"), []byte(`foo: ci`), 0o644)
+
+	valuesFiles := charts.NewValuesFiles(uri.File(tempDir), "values.yaml", "", "ci/*.yaml")
+
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"values.yaml", "main"},
+		{"./values.yaml", "main"},
+		{"ci/values.yaml", "ci"},
+		{filepath.Join(tempDir, "ci", "values.yaml"), "ci"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			valuesFile, ok := valuesFiles.GetValuesFileByName(tt.name)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, valuesFile.Values["foo"])
+		})
+	}
+
+	_, ok := valuesFiles.GetValuesFileByName("env/prod/values.yaml")
+	assert.False(t, ok)
+}
+
+func TestActiveProfile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte("foo: main\nbar: main"), 0o644)
+	_ = os.WriteFile(filepath.Join(tempDir, "values-staging.yaml"), []byte(`foo: staging`), 0o644)
+	_ = os.WriteFile(filepath.Join(tempDir, "values-prod.yaml"), []byte(`foo: prod`), 0o644)
+	_ = os.WriteFile(filepath.Join(tempDir, "values.lint.yaml"), []byte(`bar: lint`), 0o644)
+
+	valuesFiles := charts.NewValuesFiles(uri.File(tempDir), "values.yaml", "values.lint.yaml", "values*.yaml")
+
+	assert.Len(t, valuesFiles.ActiveValuesFiles(), 4)
+	assert.Equal(t, "main", valuesFiles.GetRenderValues()["foo"])
+	assert.Equal(t, "lint", valuesFiles.GetLintValues()["bar"])
+
+	err := valuesFiles.SetActiveProfile([]string{"values.yaml", "values-prod.yaml"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []*charts.ValuesFile{valuesFiles.MainValuesFile, valuesFiles.ActiveProfile[1]}, valuesFiles.ActiveValuesFiles())
+	assert.Equal(t, uri.File(filepath.Join(tempDir, "values-prod.yaml")), valuesFiles.ActiveProfile[1].URI)
+	assert.Equal(t, "prod", valuesFiles.GetRenderValues()["foo"])
+	assert.Equal(t, "prod", valuesFiles.GetLintValues()["foo"])
+	assert.Equal(t, "main", valuesFiles.GetLintValues()["bar"])
+
+	err = valuesFiles.SetActiveProfile([]string{"values-missing.yaml"})
+	assert.Error(t, err)
+	assert.Len(t, valuesFiles.ActiveProfile, 2)
+
+	err = valuesFiles.SetActiveProfile([]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, valuesFiles.ActiveProfile)
+	assert.Len(t, valuesFiles.ActiveValuesFiles(), 4)
+}
+
+func TestValuesFilesVersion(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(`foo: main`), 0o644)
+	_ = os.WriteFile(filepath.Join(tempDir, "values-prod.yaml"), []byte(`foo: prod`), 0o644)
+
+	valuesFiles := charts.NewValuesFiles(uri.File(tempDir), "values.yaml", "", "values*.yaml")
+	versions := []uint64{valuesFiles.Version()}
+
+	valuesFiles.MainValuesFile.Reload()
+	versions = append(versions, valuesFiles.Version())
+	_ = valuesFiles.SetActiveProfile([]string{"values-prod.yaml"})
+	versions = append(versions, valuesFiles.Version())
+	valuesFiles.SetOverrides(uri.File(tempDir), charts.NewValuesOverrides(util.ValuesFilesConfig{SetValues: []string{"foo=bar"}}))
+	versions = append(versions, valuesFiles.Version())
+
+	assert.Equal(t, []uint64{0, 1, 2, 3}, versions)
+}
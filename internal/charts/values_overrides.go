@@ -0,0 +1,84 @@
+package charts
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/mrjosh/helm-ls/internal/util"
+	"go.lsp.dev/uri"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+// OverridesFileName is used as the name of the values file containing the overrides,
+// e.g. in hover results
+const OverridesFileName = "--set"
+
+// ValuesOverrides are values that are passed like `--set`, `--set-string` and `--set-json` to helm
+type ValuesOverrides struct {
+	JSONValues   []string
+	Values       []string
+	StringValues []string
+}
+
+func NewValuesOverrides(valuesFilesConfig util.ValuesFilesConfig) ValuesOverrides {
+	return ValuesOverrides{
+		JSONValues:   valuesFilesConfig.SetJSONValues,
+		Values:       valuesFilesConfig.SetValues,
+		StringValues: valuesFilesConfig.SetStringValues,
+	}
+}
+
+func (o ValuesOverrides) IsEmpty() bool {
+	return len(o.JSONValues) == 0 && len(o.Values) == 0 && len(o.StringValues) == 0
+}
+
+// ApplyTo parses the overrides into the given values in the same order as helm does
+// (--set-json, --set, --set-string)
+func (o ValuesOverrides) ApplyTo(vals map[string]interface{}) error {
+	for _, value := range o.JSONValues {
+		if err := strvals.ParseJSON(value, vals); err != nil {
+			return fmt.Errorf("failed parsing --set-json data %s: %w", value, err)
+		}
+	}
+	for _, value := range o.Values {
+		if err := strvals.ParseInto(value, vals); err != nil {
+			return fmt.Errorf("failed parsing --set data %s: %w", value, err)
+		}
+	}
+	for _, value := range o.StringValues {
+		if err := strvals.ParseIntoString(value, vals); err != nil {
+			return fmt.Errorf("failed parsing --set-string data %s: %w", value, err)
+		}
+	}
+	return nil
+}
+
+// SetOverrides sets the overrides that are applied on top of the values files,
+// the overrides are also available as a values file (named OverridesFileName) for hover and completion
+func (v *ValuesFiles) SetOverrides(rootURI uri.URI, overrides ValuesOverrides) {
+	v.Overrides = overrides
+	v.OverridesValuesFile = nil
+	v.version++
+	if overrides.IsEmpty() {
+		return
+	}
+
+	vals := chartutil.Values{}
+	if err := overrides.ApplyTo(vals); err != nil {
+		logger.Error("Error parsing values overrides", err)
+	}
+	v.OverridesValuesFile = &ValuesFile{
+		Values: vals,
+		URI:    uri.File(filepath.Join(rootURI.Filename(), OverridesFileName)),
+	}
+}
+
+// CoalesceWithOverrides coalesces the given values files and applies the overrides on top
+func (v *ValuesFiles) CoalesceWithOverrides(valuesFiles ...*ValuesFile) chartutil.Values {
+	vals := CoalesceValuesFiles(valuesFiles...)
+	if err := v.Overrides.ApplyTo(vals); err != nil {
+		logger.Error("Error applying values overrides", err)
+	}
+	return vals
+}
@@ -0,0 +1,61 @@
+package charts_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func TestValuesOverrides(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(`
+image:
+  repository: nginx
+  tag: "1.0"
+ports: [80, 443]
+replicas: 1`), 0o644)
+
+	valuesFilesConfig := util.DefaultConfig.ValuesFilesConfig
+	valuesFilesConfig.SetJSONValues = []string{`resources={"limits":{"cpu":"1"}}`}
+	valuesFilesConfig.SetValues = []string{"image.tag=2.0", "replicas=3", "ports[1]=8443"}
+	valuesFilesConfig.SetStringValues = []string{"version=1.10"}
+	chart := charts.NewChart(uri.File(tempDir), valuesFilesConfig)
+
+	vals := chart.ValuesFiles.GetRenderValues()
+	assert.Equal(t, map[string]interface{}{"repository": "nginx", "tag": "2.0"}, vals["image"])
+	assert.Equal(t, int64(3), vals["replicas"])
+	assert.Equal(t, "1.10", vals["version"])
+	assert.Equal(t, map[string]interface{}{"limits": map[string]interface{}{"cpu": "1"}}, vals["resources"])
+	assert.Equal(t, chart.ValuesFiles.GetLintValues(), vals)
+
+	// the values file is not modified
+	assert.Equal(t, "1.0", chart.ValuesFiles.MainValuesFile.Values["image"].(map[string]interface{})["tag"])
+
+	overridesValuesFile := chart.ValuesFiles.OverridesValuesFile
+	assert.NotNil(t, overridesValuesFile)
+	assert.Equal(t, uri.File(filepath.Join(tempDir, charts.OverridesFileName)), overridesValuesFile.URI)
+	assert.Equal(t, map[string]interface{}{"tag": "2.0"}, overridesValuesFile.Values["image"])
+	assert.Contains(t, chart.ValuesFiles.ActiveValuesFiles(), overridesValuesFile)
+	assert.NotContains(t, chart.ValuesFiles.AllValuesFiles(), overridesValuesFile)
+}
+
+func TestValuesOverridesEmpty(t *testing.T) {
+	chart := charts.NewChart(uri.File(t.TempDir()), util.DefaultConfig.ValuesFilesConfig)
+
+	assert.True(t, chart.ValuesFiles.Overrides.IsEmpty())
+	assert.Nil(t, chart.ValuesFiles.OverridesValuesFile)
+}
+
+func TestValuesOverridesInvalid(t *testing.T) {
+	overrides := charts.ValuesOverrides{Values: []string{"a[=b"}}
+
+	err := overrides.ApplyTo(map[string]interface{}{})
+
+	assert.ErrorContains(t, err, "failed parsing --set data")
+}
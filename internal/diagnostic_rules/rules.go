@@ -0,0 +1,242 @@
+package diagnosticrules
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	lsp "go.lsp.dev/protocol"
+)
+
+// DocumentationURL is the page that documents all rules, the rules link to their section
+const DocumentationURL = "https://github.com/mrjosh/helm-ls/blob/master/docs/diagnostics.md"
+
+// Rule describes a kind of diagnostic. The code of a rule is stable and set as the code of its diagnostics.
+// Rules can be referenced by their code (e.g. HLS001), their ID (e.g. type-check/field-access),
+// their name (e.g. field-access) or their group (e.g. type-check).
+type Rule struct {
+	// Code is HLS followed by a number for the checks of helm-ls, rules of other tools use their ID
+	Code        string
+	Group       string
+	Name        string
+	Summary     string
+	Description string
+}
+
+var (
+	TypeCheckFieldAccess = Rule{
+		Code: "HLS001", Group: "type-check", Name: "field-access",
+		Summary: "A field is accessed on a value that is not a map",
+		Description: "The values file sets the value to a scalar or a list, but the template accesses a field of it " +
+			"(e.g. `.Values.image.tag` while `image` is a string). Rendering fails with an error like `can't evaluate field tag in type string`.",
+	}
+	TypeCheckRange = Rule{
+		Code: "HLS002", Group: "type-check", Name: "range",
+		Summary:     "range is used on a value that can not be iterated",
+		Description: "range only iterates over lists and maps, the values file sets the value to a scalar.",
+	}
+	TypeCheckNumberArgument = Rule{
+		Code: "HLS003", Group: "type-check", Name: "number-argument",
+		Summary:     "A function that expects numbers gets a value of another type",
+		Description: "Arithmetic functions like add or max expect numbers, the values file sets the argument to a string.",
+	}
+	TypeCheckQuotedToYaml = Rule{
+		Code: "HLS004", Group: "type-check", Name: "quoted-toyaml",
+		Summary: "The output of toYaml is quoted",
+		Description: "quote or squote turn the output of toYaml into a single string instead of YAML. " +
+			"Use `toYaml . | nindent N` instead.",
+	}
+	RenderLintInvalidYaml = Rule{
+		Code: "HLS101", Group: "render-lint", Name: "invalid-yaml",
+		Summary:     "The rendered template is not valid YAML",
+		Description: "The template was rendered with the values of the chart and the output could not be parsed as YAML.",
+	}
+	RenderLintInvalidObject = Rule{
+		Code: "HLS102", Group: "render-lint", Name: "invalid-object",
+		Summary: "The rendered Kubernetes object does not match its schema",
+		Description: "The rendered object was validated against the schema of its apiVersion and kind (or the CRD of the chart). " +
+			"Other versions than the one of the bundled schemas are rejected for render.kubeVersion while the validation is enabled. " +
+			"Can be disabled with the render.validationEnabled setting.",
+	}
+	RenderLintDeprecatedAPI = Rule{
+		Code: "HLS103", Group: "render-lint", Name: "deprecated-api",
+		Summary: "The apiVersion of the rendered object is deprecated or removed",
+		Description: "The apiVersion is deprecated in the Kubernetes version of render.kubeVersion. " +
+			"It is reported as an error if the apiVersion was removed in that version.",
+	}
+	RenderLintIndent = Rule{
+		Code: "HLS104", Group: "render-lint", Name: "indent",
+		Summary:     "indent or nindent does not match the column of the action",
+		Description: "The number of spaces of indent or nindent results in a different indentation than the column of the action suggests.",
+	}
+	ValuesSyntax = Rule{
+		Code: "HLS201", Group: "values", Name: "values-syntax",
+		Summary:     "The values file is not valid YAML",
+		Description: "The values file could not be parsed.",
+	}
+	ValuesSchema = Rule{
+		Code: "HLS202", Group: "values", Name: "values-schema",
+		Summary: "The values do not match the values.schema.json of the chart",
+		Description: "The values file is validated against the values.schema.json of the chart. " +
+			"Additional values files are validated on top of the main values file like helm does it.",
+	}
+	ValuesEmbeddedTemplate = Rule{
+		Code: "HLS203", Group: "values", Name: "embedded-template",
+		Summary:     "A template in a string value has a syntax error",
+		Description: "Values that are rendered with tpl contain template actions that can not be parsed.",
+	}
+	UnusedSuppression = Rule{
+		Code: "HLS301", Group: "helm-ls", Name: "unused-suppression",
+		Summary: "A suppression comment does not disable any diagnostic",
+		Description: "A helm-ls:disable, helm-ls:disable-next-line or helm-ls-ignore comment does not match any diagnostic, " +
+			"because the problem was fixed or the rule was disabled otherwise. Remove the comment. " +
+			"It is only reported once the sources of its rules (e.g. helm lint or yaml-language-server) reported their diagnostics.",
+	}
+	HelmLintTemplateError = Rule{
+		Code: "helm-lint/template-error", Group: "helm-lint", Name: "template-error",
+		Summary:     "helm lint reported an error for a template",
+		Description: "The template could not be parsed or rendered by helm lint, e.g. because of a syntax error or a failing required or fail call.",
+	}
+	HelmLintTemplateWarning = Rule{
+		Code: "helm-lint/template-warning", Group: "helm-lint", Name: "template-warning",
+		Summary:     "helm lint reported a warning for a template",
+		Description: "helm lint reported a warning or an info for a template, e.g. a deprecated apiVersion.",
+	}
+	HelmLintChart = Rule{
+		Code: "helm-lint/chart", Group: "helm-lint", Name: "chart",
+		Summary:     "helm lint reported a problem of the chart metadata",
+		Description: "The Chart.yaml of the chart is invalid, e.g. because of a missing version.",
+	}
+	YamllsSchema = Rule{
+		Code: "yamlls/schema", Group: "yamlls", Name: "schema",
+		Summary: "The YAML of the template does not match its schema",
+		Description: "yaml-language-server (or the built-in YAML backend) validated the template without its actions against the schema of its kind. " +
+			"Can be filtered with the yamlls.diagnosticsFilters setting.",
+	}
+	YamllsSyntax = Rule{
+		Code: "yamlls/syntax", Group: "yamlls", Name: "syntax",
+		Summary:     "yaml-language-server reported a problem that is not related to a schema",
+		Description: "yaml-language-server reported e.g. a syntax error or duplicate keys in the template without its actions.",
+	}
+)
+
+// Rules are all rules in the order of the documentation
+var Rules = []Rule{
+	TypeCheckFieldAccess,
+	TypeCheckRange,
+	TypeCheckNumberArgument,
+	TypeCheckQuotedToYaml,
+	RenderLintInvalidYaml,
+	RenderLintInvalidObject,
+	RenderLintDeprecatedAPI,
+	RenderLintIndent,
+	ValuesSyntax,
+	ValuesSchema,
+	ValuesEmbeddedTemplate,
+	UnusedSuppression,
+	HelmLintTemplateError,
+	HelmLintTemplateWarning,
+	HelmLintChart,
+	YamllsSchema,
+	YamllsSyntax,
+}
+
+// ID returns the group and the name of the rule, e.g. type-check/field-access
+func (r Rule) ID() string {
+	return r.Group + "/" + r.Name
+}
+
+// Title returns the code and the ID of the rule, it is the heading of the rule in the documentation
+func (r Rule) Title() string {
+	if r.Code == r.ID() {
+		return r.Code
+	}
+	return r.Code + " " + r.ID()
+}
+
+// CodeDescription links to the documentation of the rule
+func (r Rule) CodeDescription() *lsp.CodeDescription {
+	return &lsp.CodeDescription{Href: lsp.URI(DocumentationURL + "#" + getAnchor(r.Title()))}
+}
+
+// References returns true if the identifier is the code, ID, name or group of the rule
+func (r Rule) References(identifier string) bool {
+	return identifier == r.Code || identifier == r.ID() || identifier == r.Name || identifier == r.Group
+}
+
+// getAnchor returns the anchor that GitHub generates for a heading
+func getAnchor(heading string) string {
+	anchor := strings.Builder{}
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			anchor.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			anchor.WriteRune(r)
+		}
+	}
+	return anchor.String()
+}
+
+// Find returns the rule with the code, ID or name
+func Find(identifier string) (Rule, bool) {
+	for _, rule := range Rules {
+		if identifier == rule.Code || identifier == rule.ID() || identifier == rule.Name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Matches returns true if the identifier references the rule of the diagnostic
+func Matches(identifier string, diagnostic lsp.Diagnostic) bool {
+	code, ok := diagnostic.Code.(string)
+	if !ok || identifier == "" {
+		return false
+	}
+	if rule, ok := Find(code); ok {
+		return rule.References(identifier)
+	}
+	return code == identifier || strings.HasPrefix(code, identifier+"/")
+}
+
+// ReferencesGroup returns true if the identifier references the group or one of its rules
+func ReferencesGroup(identifier string, group string) bool {
+	if identifier == group || strings.HasPrefix(identifier, group+"/") {
+		return true
+	}
+	for _, rule := range Rules {
+		if rule.Group == group && rule.References(identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveDisabled removes the diagnostics of the disabled rules
+func RemoveDisabled(diagnostics []lsp.Diagnostic, disabledRules []string) []lsp.Diagnostic {
+	if len(disabledRules) == 0 {
+		return diagnostics
+	}
+	result := []lsp.Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		if !isDisabled(diagnostic, disabledRules) {
+			result = append(result, diagnostic)
+		}
+	}
+	return result
+}
+
+func isDisabled(diagnostic lsp.Diagnostic, disabledRules []string) bool {
+	for _, identifier := range disabledRules {
+		if Matches(identifier, diagnostic) {
+			return true
+		}
+	}
+	return false
+}
+
+// Explain returns the documentation of the rule as text
+func (r Rule) Explain() string {
+	return fmt.Sprintf("%s\n\n%s.\n\n%s\n\nDocumentation: %s\n", r.Title(), r.Summary, r.Description, r.CodeDescription().Href)
+}
@@ -0,0 +1,104 @@
+package diagnosticrules
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+)
+
+func TestRulesAreUnique(t *testing.T) {
+	identifiers := map[string]bool{}
+	for _, rule := range Rules {
+		for _, identifier := range []string{rule.Code, rule.ID(), rule.Name} {
+			if identifier == rule.Code && identifier == rule.ID() {
+				continue
+			}
+			assert.False(t, identifiers[identifier], "duplicate identifier %s", identifier)
+			identifiers[identifier] = true
+		}
+	}
+}
+
+func TestRulesAreDocumented(t *testing.T) {
+	documentation, err := os.ReadFile("../../docs/diagnostics.md")
+	assert.NoError(t, err)
+
+	for _, rule := range Rules {
+		assert.Contains(t, string(documentation), "## "+rule.Title()+"\n\n"+rule.Summary+".\n\n"+rule.Description+"\n")
+	}
+}
+
+func TestCodeDescription(t *testing.T) {
+	assert.Equal(t, lsp.URI(DocumentationURL+"#hls001-type-checkfield-access"), TypeCheckFieldAccess.CodeDescription().Href)
+	assert.Equal(t, lsp.URI(DocumentationURL+"#helm-linttemplate-error"), HelmLintTemplateError.CodeDescription().Href)
+}
+
+func TestMatches(t *testing.T) {
+	fieldAccess := lsp.Diagnostic{Code: TypeCheckFieldAccess.Code}
+	testCases := []struct {
+		identifier string
+		diagnostic lsp.Diagnostic
+		expected   bool
+	}{
+		{"HLS001", fieldAccess, true},
+		{"type-check/field-access", fieldAccess, true},
+		{"field-access", fieldAccess, true},
+		{"type-check", fieldAccess, true},
+		{"HLS002", fieldAccess, false},
+		{"render-lint", fieldAccess, false},
+		{"", fieldAccess, false},
+		{"helm-lint", lsp.Diagnostic{Code: HelmLintTemplateError.Code}, true},
+		{"template-error", lsp.Diagnostic{Code: HelmLintTemplateError.Code}, true},
+		{"other", lsp.Diagnostic{Code: "other/rule"}, true},
+		{"other", lsp.Diagnostic{Code: 1}, false},
+		{"other", lsp.Diagnostic{}, false},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.identifier, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Matches(tt.identifier, tt.diagnostic))
+		})
+	}
+}
+
+func TestReferencesGroup(t *testing.T) {
+	testCases := []struct {
+		identifier string
+		group      string
+		expected   bool
+	}{
+		{"type-check", "type-check", true},
+		{"HLS001", "type-check", true},
+		{"field-access", "type-check", true},
+		{"type-check/field-access", "type-check", true},
+		{"HLS001", "render-lint", false},
+		{"helm-lint/other", "helm-lint", true},
+		{"yamlls", "helm-lint", false},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.identifier+" "+tt.group, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ReferencesGroup(tt.identifier, tt.group))
+		})
+	}
+}
+
+func TestRemoveDisabled(t *testing.T) {
+	diagnostics := []lsp.Diagnostic{
+		{Code: TypeCheckFieldAccess.Code},
+		{Code: RenderLintIndent.Code},
+		{Code: YamllsSchema.Code},
+	}
+
+	assert.Equal(t, diagnostics, RemoveDisabled(diagnostics, nil))
+	assert.Equal(t, diagnostics[1:2], RemoveDisabled(diagnostics, []string{"type-check", "yamlls"}))
+}
+
+func TestFind(t *testing.T) {
+	rule, ok := Find("HLS104")
+	assert.True(t, ok)
+	assert.Equal(t, RenderLintIndent, rule)
+
+	_, ok = Find("type-check")
+	assert.False(t, ok)
+}
@@ -0,0 +1,153 @@
+package helmdocs
+
+const (
+	ReturnTypeString = "string"
+	ReturnTypeInt    = "int"
+	ReturnTypeFloat  = "float64"
+	ReturnTypeBool   = "bool"
+	ReturnTypeList   = "list"
+	ReturnTypeDict   = "dict"
+	ReturnTypeTime   = "time.Time"
+)
+
+// FunctionReturnTypes contains the return types of the functions that always return the same type.
+// Functions returning one of their arguments (e.g. default, index, first) are not listed.
+var FunctionReturnTypes = map[string]string{
+	// builtin
+	"and":      ReturnTypeBool,
+	"html":     ReturnTypeString,
+	"js":       ReturnTypeString,
+	"len":      ReturnTypeInt,
+	"not":      ReturnTypeBool,
+	"print":    ReturnTypeString,
+	"printf":   ReturnTypeString,
+	"println":  ReturnTypeString,
+	"urlquery": ReturnTypeString,
+	"ne":       ReturnTypeBool,
+	"eq":       ReturnTypeBool,
+	"lt":       ReturnTypeBool,
+	"gt":       ReturnTypeBool,
+	"le":       ReturnTypeBool,
+	"ge":       ReturnTypeBool,
+
+	// strings
+	"snakecase":    ReturnTypeString,
+	"camelcase":    ReturnTypeString,
+	"shuffle":      ReturnTypeString,
+	"trim":         ReturnTypeString,
+	"trimAll":      ReturnTypeString,
+	"trimSuffix":   ReturnTypeString,
+	"trimPrefix":   ReturnTypeString,
+	"upper":        ReturnTypeString,
+	"lower":        ReturnTypeString,
+	"title":        ReturnTypeString,
+	"untitle":      ReturnTypeString,
+	"substr":       ReturnTypeString,
+	"repeat":       ReturnTypeString,
+	"nospace":      ReturnTypeString,
+	"trunc":        ReturnTypeString,
+	"abbrev":       ReturnTypeString,
+	"abbrevboth":   ReturnTypeString,
+	"initials":     ReturnTypeString,
+	"randAscii":    ReturnTypeString,
+	"randNumeric":  ReturnTypeString,
+	"randAlpha":    ReturnTypeString,
+	"randAlphaNum": ReturnTypeString,
+	"wrap":         ReturnTypeString,
+	"wrapWith":     ReturnTypeString,
+	"contains":     ReturnTypeBool,
+	"hasPrefix":    ReturnTypeBool,
+	"hasSuffix":    ReturnTypeBool,
+	"quote":        ReturnTypeString,
+	"squote":       ReturnTypeString,
+	"cat":          ReturnTypeString,
+	"indent":       ReturnTypeString,
+	"nindent":      ReturnTypeString,
+	"replace":      ReturnTypeString,
+	"plural":       ReturnTypeString,
+	"join":         ReturnTypeString,
+	"splitList":    ReturnTypeList,
+	"split":        ReturnTypeDict,
+	"sortAlpha":    ReturnTypeList,
+
+	// math
+	"add":        ReturnTypeInt,
+	"add1":       ReturnTypeInt,
+	"sub":        ReturnTypeInt,
+	"div":        ReturnTypeInt,
+	"mod":        ReturnTypeInt,
+	"mul":        ReturnTypeInt,
+	"max":        ReturnTypeInt,
+	"min":        ReturnTypeInt,
+	"until":      ReturnTypeList,
+	"untilStep":  ReturnTypeList,
+	"atoi":       ReturnTypeInt,
+	"float64":    ReturnTypeFloat,
+	"int":        ReturnTypeInt,
+	"int64":      ReturnTypeInt,
+	"toString":   ReturnTypeString,
+	"toStrings":  ReturnTypeList,
+	"empty":      ReturnTypeBool,
+	"hasKey":     ReturnTypeBool,
+	"has":        ReturnTypeBool,
+	"keys":       ReturnTypeList,
+	"list":       ReturnTypeList,
+	"dict":       ReturnTypeDict,
+	"pluck":      ReturnTypeList,
+	"pick":       ReturnTypeDict,
+	"omit":       ReturnTypeDict,
+	"merge":      ReturnTypeDict,
+	"set":        ReturnTypeDict,
+	"unset":      ReturnTypeDict,
+	"rest":       ReturnTypeList,
+	"initial":    ReturnTypeList,
+	"append":     ReturnTypeList,
+	"prepend":    ReturnTypeList,
+	"reverse":    ReturnTypeList,
+	"uniq":       ReturnTypeList,
+	"without":    ReturnTypeList,
+	"b64enc":     ReturnTypeString,
+	"b64dec":     ReturnTypeString,
+	"b32enc":     ReturnTypeString,
+	"b32dec":     ReturnTypeString,
+	"now":        ReturnTypeTime,
+	"date":       ReturnTypeString,
+	"dateInZone": ReturnTypeString,
+	"dateModify": ReturnTypeTime,
+	"htmlDate":   ReturnTypeString,
+
+	// misc
+	"htmlDateInZone":     ReturnTypeString,
+	"base":               ReturnTypeString,
+	"dir":                ReturnTypeString,
+	"clean":              ReturnTypeString,
+	"ext":                ReturnTypeString,
+	"isAbs":              ReturnTypeBool,
+	"uuidv4":             ReturnTypeString,
+	"env":                ReturnTypeString,
+	"expandenv":          ReturnTypeString,
+	"semverCompare":      ReturnTypeBool,
+	"kindOf":             ReturnTypeString,
+	"kindIs":             ReturnTypeBool,
+	"typeOf":             ReturnTypeString,
+	"typeIs":             ReturnTypeBool,
+	"typeIsLike":         ReturnTypeBool,
+	"sha1sum":            ReturnTypeString,
+	"sha256sum":          ReturnTypeString,
+	"derivePassword":     ReturnTypeString,
+	"generatePrivateKey": ReturnTypeString,
+	"include":            ReturnTypeString,
+	"toYaml":             ReturnTypeString,
+	"toJson":             ReturnTypeString,
+	"toToml":             ReturnTypeString,
+	"fromYaml":           ReturnTypeDict,
+	"fromJson":           ReturnTypeDict,
+	"tpl":                ReturnTypeString,
+	"lookup":             ReturnTypeDict,
+}
+
+// GetFunctionReturnType returns the return type of the function, if it is known
+func GetFunctionReturnType(name string) (string, bool) {
+	returnType, ok := FunctionReturnTypes[name]
+	return returnType, ok
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
+	lsp "go.lsp.dev/protocol"
+)
+
+const (
+	// RenderTemplateCommand renders a template, arguments are the document URI and an optional values file name
+	RenderTemplateCommand = "helm-ls.renderTemplate"
+	// RenderTemplateRequest is the custom request equivalent of RenderTemplateCommand
+	RenderTemplateRequest = "helm-ls/renderTemplate"
+	// SelectValuesProfileCommand sets the active values profile of a chart, arguments are
+	// the chart (or document) URI and the ordered list of values files
+	SelectValuesProfileCommand = "helm-ls.selectValuesProfile"
+)
+
+var supportedCommands = []string{RenderTemplateCommand, SelectValuesProfileCommand}
+
+// ExecuteCommand implements protocol.Server.
+func (h *ServerHandler) ExecuteCommand(ctx context.Context, params *lsp.ExecuteCommandParams) (result interface{}, err error) {
+	logger.Debug("Running execute command with params", params)
+
+	switch params.Command {
+	case RenderTemplateCommand:
+		renderParams, err := renderTemplateParamsFromArguments(params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return h.renderTemplate(renderParams)
+	case SelectValuesProfileCommand:
+		profileParams, err := selectValuesProfileParamsFromArguments(params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return h.selectValuesProfile(ctx, profileParams)
+	}
+
+	return nil, fmt.Errorf("unknown command %s", params.Command)
+}
+
+// Request implements protocol.Server.
+// It is called for all requests that are not part of the LSP specification.
+func (h *ServerHandler) Request(ctx context.Context, method string, params interface{}) (result interface{}, err error) {
+	logger.Debug("Running custom request", method, params)
+
+	switch method {
+	case RenderTemplateRequest:
+		renderParams := RenderTemplateParams{}
+		if err := unmarshalRequestParams(params, &renderParams); err != nil {
+			return nil, err
+		}
+		return h.renderTemplate(renderParams)
+	case yamlls.SelectionRangeMethod:
+		selectionRangeParams := lsp.SelectionRangeParams{}
+		if err := unmarshalRequestParams(params, &selectionRangeParams); err != nil {
+			return nil, err
+		}
+		return h.selectionRange(ctx, &selectionRangeParams)
+	}
+
+	logger.Error("Request unimplemented", method)
+	return nil, nil
+}
+
+// selectionRange handles textDocument/selectionRange, which is not part of the server interface of the protocol package
+func (h *ServerHandler) selectionRange(ctx context.Context, params *lsp.SelectionRangeParams) ([]lsp.SelectionRange, error) {
+	handler, err := h.selectLangHandler(ctx, params.TextDocument.URI)
+	if err != nil {
+		logger.Error("Error selecting lang handler", err)
+		return nil, err
+	}
+	return handler.SelectionRange(ctx, params)
+}
+
+func unmarshalRequestParams(params interface{}, result interface{}) error {
+	jsonParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonParams, result)
+}
@@ -3,9 +3,11 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	// "reflect"
 
+	renderlint "github.com/mrjosh/helm-ls/internal/render_lint"
 	"github.com/mrjosh/helm-ls/internal/util"
 	lsp "go.lsp.dev/protocol"
 )
@@ -30,7 +32,13 @@ func (h *ServerHandler) retrieveWorkspaceConfiguration(ctx context.Context) {
 	}
 
 	h.helmlsConfig = parseWorkspaceConfiguration(rawResult, h.helmlsConfig)
+	if err := rejectUnsupportedKubeVersion(&h.helmlsConfig); err != nil {
+		h.client.ShowMessage(ctx, &lsp.ShowMessageParams{
+			Type: lsp.MessageTypeError, Message: fmt.Sprintf("Helm-ls: %s", err.Error()),
+		})
+	}
 	h.helmlsConfig.YamllsConfiguration.CompileEnabledForFilesGlobObject()
+	h.helmlsConfig.YamllsConfiguration.CompileDiagnosticsFilters()
 	logger.Println("Workspace configuration:", h.helmlsConfig)
 	h.initializationWithConfig(ctx)
 }
@@ -50,10 +58,27 @@ func parseWorkspaceConfiguration(rawResult []interface{}, currentConfig util.Hel
 	}
 
 	result = currentConfig
+	// the decoder would merge the configured filters into the elements of the current ones
+	// and modify the default configuration, configured filters replace the current ones instead
+	result.YamllsConfiguration.DiagnosticsFilters = nil
 	err = json.Unmarshal(jsonResult, &result)
 	if err != nil {
 		logger.Println("Error unmarshalling workspace/configuration", err)
 		return currentConfig
 	}
+	if result.YamllsConfiguration.DiagnosticsFilters == nil {
+		result.YamllsConfiguration.DiagnosticsFilters = currentConfig.YamllsConfiguration.DiagnosticsFilters
+	}
 	return result
 }
+
+// rejectUnsupportedKubeVersion resets render.kubeVersion to the default if the rendered objects
+// can not be validated with the bundled schemas for it
+func rejectUnsupportedKubeVersion(config *util.HelmlsConfiguration) error {
+	err := renderlint.CheckKubeVersion(config.RenderConfig)
+	if err != nil {
+		logger.Error("Ignoring render.kubeVersion", err)
+		config.RenderConfig.KubeVersion = ""
+	}
+	return err
+}
@@ -127,3 +127,36 @@ func TestConfigurationWorksForYamllsGlob(t *testing.T) {
 	assert.True(t, handler.helmlsConfig.YamllsConfiguration.EnabledForFilesGlobObject.Match("file.ext"))
 	assert.False(t, handler.helmlsConfig.YamllsConfiguration.EnabledForFilesGlobObject.Match("file.yaml"))
 }
+
+func TestParseWorkspaceConfigurationReplacesDiagnosticsFilters(t *testing.T) {
+	defaultFilters := append([]util.YamllsDiagnosticsFilter{}, util.DefaultConfig.YamllsConfiguration.DiagnosticsFilters...)
+
+	testCases := []struct {
+		desc     string
+		yamlls   map[string]any
+		expected []util.YamllsDiagnosticsFilter
+	}{
+		{"defaults are kept without filters", map[string]any{"enabled": false}, defaultFilters},
+		{"configured filters replace the defaults", map[string]any{"diagnosticsFilters": []any{map[string]any{"message": "^Property"}}}, []util.YamllsDiagnosticsFilter{{Message: "^Property"}}},
+		{"an empty list removes the defaults", map[string]any{"diagnosticsFilters": []any{}}, []util.YamllsDiagnosticsFilter{}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result := parseWorkspaceConfiguration([]any{map[string]any{"yamlls": tt.yamlls}}, util.DefaultConfig)
+			assert.Equal(t, tt.expected, result.YamllsConfiguration.DiagnosticsFilters)
+			assert.Equal(t, defaultFilters, util.DefaultConfig.YamllsConfiguration.DiagnosticsFilters)
+		})
+	}
+}
+
+func TestRejectUnsupportedKubeVersion(t *testing.T) {
+	config := parseWorkspaceConfiguration([]any{map[string]any{"render": map[string]any{"kubeVersion": "v1.29.0"}}}, util.DefaultConfig)
+
+	assert.Error(t, rejectUnsupportedKubeVersion(&config))
+	assert.Equal(t, "", config.RenderConfig.KubeVersion)
+
+	config = parseWorkspaceConfiguration([]any{map[string]any{"render": map[string]any{"kubeVersion": "v1.29.0", "validationEnabled": false}}}, util.DefaultConfig)
+
+	assert.NoError(t, rejectUnsupportedKubeVersion(&config))
+	assert.Equal(t, "v1.29.0", config.RenderConfig.KubeVersion)
+}
@@ -81,8 +81,12 @@ func (h *ServerHandler) setClient(client protocol.Client) {
 
 // CodeAction implements protocol.Server.
 func (h *ServerHandler) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (result []lsp.CodeAction, err error) {
-	logger.Error("Code action unimplemented")
-	return nil, nil
+	handler, err := h.selectLangHandler(ctx, params.TextDocument.URI)
+	if err != nil {
+		logger.Error("Error selecting lang handler", err)
+		return nil, err
+	}
+	return handler.CodeAction(ctx, params)
 }
 
 // CodeLens implements protocol.Server.
@@ -141,8 +145,12 @@ func (h *ServerHandler) DocumentHighlight(ctx context.Context, params *lsp.Docum
 
 // DocumentLink implements protocol.Server.
 func (h *ServerHandler) DocumentLink(ctx context.Context, params *lsp.DocumentLinkParams) (result []lsp.DocumentLink, err error) {
-	logger.Error("Document link unimplemented")
-	return nil, nil
+	handler, err := h.selectLangHandler(ctx, params.TextDocument.URI)
+	if err != nil {
+		logger.Error("Error selecting lang handler", err)
+		return nil, err
+	}
+	return handler.DocumentLink(ctx, params)
 }
 
 // DocumentLinkResolve implements protocol.Server.
@@ -151,12 +159,6 @@ func (h *ServerHandler) DocumentLinkResolve(ctx context.Context, params *lsp.Doc
 	return nil, nil
 }
 
-// ExecuteCommand implements protocol.Server.
-func (h *ServerHandler) ExecuteCommand(ctx context.Context, params *lsp.ExecuteCommandParams) (result interface{}, err error) {
-	logger.Error("Execute command unimplemented")
-	return nil, nil
-}
-
 // Exit implements protocol.Server.
 func (h *ServerHandler) Exit(ctx context.Context) (err error) {
 	return nil
@@ -164,14 +166,22 @@ func (h *ServerHandler) Exit(ctx context.Context) (err error) {
 
 // FoldingRanges implements protocol.Server.
 func (h *ServerHandler) FoldingRanges(ctx context.Context, params *lsp.FoldingRangeParams) (result []lsp.FoldingRange, err error) {
-	logger.Error("Folding ranges unimplemented")
-	return nil, nil
+	handler, err := h.selectLangHandler(ctx, params.TextDocument.URI)
+	if err != nil {
+		logger.Error("Error selecting lang handler", err)
+		return nil, err
+	}
+	return handler.FoldingRanges(ctx, params)
 }
 
 // Formatting implements protocol.Server.
 func (h *ServerHandler) Formatting(ctx context.Context, params *lsp.DocumentFormattingParams) (result []lsp.TextEdit, err error) {
-	logger.Error("Formatting unimplemented")
-	return nil, nil
+	handler, err := h.selectLangHandler(ctx, params.TextDocument.URI)
+	if err != nil {
+		logger.Error("Error selecting lang handler", err)
+		return nil, err
+	}
+	return handler.Formatting(ctx, params)
 }
 
 // Implementation implements protocol.Server.
@@ -240,12 +250,6 @@ func (h *ServerHandler) Rename(ctx context.Context, params *lsp.RenameParams) (r
 	return nil, nil
 }
 
-// Request implements protocol.Server.
-func (h *ServerHandler) Request(ctx context.Context, method string, params interface{}) (result interface{}, err error) {
-	logger.Error("Request unimplemented")
-	return nil, nil
-}
-
 // SemanticTokensFull implements protocol.Server.
 func (h *ServerHandler) SemanticTokensFull(ctx context.Context, params *lsp.SemanticTokensParams) (result *lsp.SemanticTokens, err error) {
 	logger.Error("Semantic tokens full unimplemented")
@@ -284,6 +288,9 @@ func (h *ServerHandler) ShowDocument(ctx context.Context, params *lsp.ShowDocume
 
 // Shutdown implements protocol.Server.
 func (h *ServerHandler) Shutdown(ctx context.Context) (err error) {
+	for _, handler := range h.langHandlers {
+		handler.Stop()
+	}
 	return h.connPool.Close()
 }
 
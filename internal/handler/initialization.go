@@ -45,6 +45,15 @@ func (h *ServerHandler) Initialize(ctx context.Context, params *lsp.InitializePa
 			DefinitionProvider:     true,
 			ReferencesProvider:     true,
 			DocumentSymbolProvider: true,
+			CodeActionProvider:     true,
+			// the following features are provided by yamlls
+			FoldingRangeProvider:       true,
+			SelectionRangeProvider:     true,
+			DocumentLinkProvider:       &lsp.DocumentLinkOptions{},
+			DocumentFormattingProvider: true,
+			ExecuteCommandProvider: &lsp.ExecuteCommandOptions{
+				Commands: supportedCommands,
+			},
 		},
 	}, nil
 }
@@ -16,6 +16,11 @@ type LangHandler interface {
 	Hover(ctx context.Context, params *lsp.HoverParams) (result *lsp.Hover, err error)
 	Definition(ctx context.Context, params *lsp.DefinitionParams) (result []lsp.Location, err error)
 	DocumentSymbol(ctx context.Context, params *lsp.DocumentSymbolParams) (result []interface{}, err error)
+	CodeAction(ctx context.Context, params *lsp.CodeActionParams) (result []lsp.CodeAction, err error)
+	FoldingRanges(ctx context.Context, params *lsp.FoldingRangeParams) (result []lsp.FoldingRange, err error)
+	SelectionRange(ctx context.Context, params *lsp.SelectionRangeParams) (result []lsp.SelectionRange, err error)
+	DocumentLink(ctx context.Context, params *lsp.DocumentLinkParams) (result []lsp.DocumentLink, err error)
+	Formatting(ctx context.Context, params *lsp.DocumentFormattingParams) (result []lsp.TextEdit, err error)
 
 	// DidOpen is called when a document is opened. This function has to add the document to the document store
 	DidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentParams, helmlsConfig util.HelmlsConfiguration) (err error)
@@ -30,6 +35,9 @@ type LangHandler interface {
 
 	// SetClient is called once the client has been initialized
 	SetClient(client protocol.Client)
+
+	// Stop is called on shutdown, it stops the yaml-language-server so that it is not restarted
+	Stop()
 }
 
 func (h *ServerHandler) selectLangHandler(_ context.Context, uri uri.URI) (LangHandler, error) {
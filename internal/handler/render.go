@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"fmt"
+
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+// RenderedTemplateScheme is the URI scheme of the virtual documents containing rendered templates
+const RenderedTemplateScheme = "helm-ls-rendered"
+
+type RenderTemplateParams struct {
+	TextDocument lsp.TextDocumentIdentifier `json:"textDocument"`
+	// ValuesFile is the name of one of the values files of the chart (e.g. values-prod.yaml)
+	// which is used on top of the main values file, optional.
+	// The active values profile is used if it is empty
+	ValuesFile string `json:"valuesFile,omitempty"`
+}
+
+// RenderTemplateResult is a virtual document containing the rendered template
+type RenderTemplateResult struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Content string          `json:"content"`
+}
+
+func renderTemplateParamsFromArguments(arguments []interface{}) (RenderTemplateParams, error) {
+	result := RenderTemplateParams{}
+	if len(arguments) == 0 {
+		return result, fmt.Errorf("%s requires the document URI as first argument", RenderTemplateCommand)
+	}
+
+	docURI, ok := arguments[0].(string)
+	if !ok {
+		return result, fmt.Errorf("%s requires the document URI as first argument, got %v", RenderTemplateCommand, arguments[0])
+	}
+	result.TextDocument.URI = uri.URI(docURI)
+
+	if len(arguments) > 1 {
+		valuesFile, ok := arguments[1].(string)
+		if !ok {
+			return result, fmt.Errorf("%s requires the values file name as second argument, got %v", RenderTemplateCommand, arguments[1])
+		}
+		result.ValuesFile = valuesFile
+	}
+	return result, nil
+}
+
+func (h *ServerHandler) renderTemplate(params RenderTemplateParams) (*RenderTemplateResult, error) {
+	doc, ok := h.documents.GetTemplateDoc(params.TextDocument.URI)
+	if !ok {
+		return nil, fmt.Errorf("could not get template document %s", params.TextDocument.URI)
+	}
+
+	chart, err := h.chartStore.GetChartForDoc(doc.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := chart.ValuesFiles.GetRenderValues()
+	if params.ValuesFile != "" {
+		valuesFile, ok := chart.ValuesFiles.GetValuesFileByName(params.ValuesFile)
+		if !ok {
+			return nil, fmt.Errorf("values file %s not found for chart %s", params.ValuesFile, chart.RootURI.Filename())
+		}
+		vals = chart.ValuesFiles.CoalesceWithOverrides(chart.ValuesFiles.MainValuesFile, valuesFile)
+	}
+
+	content, err := helmrender.RenderTemplate(chart, doc.Path, doc.Content, vals, h.helmlsConfig.RenderConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RenderTemplateResult{
+		URI:     uri.URI(fmt.Sprintf("%s://%s", RenderedTemplateScheme, doc.Path)),
+		Content: content,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func setupRenderTest(t *testing.T) (*ServerHandler, uri.URI) {
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "templates")
+	assert.NoError(t, os.MkdirAll(templateDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte("replicas: 1"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values-prod.yaml"), []byte("replicas: 3"), 0o644))
+	templateFile := filepath.Join(templateDir, "deployment.yaml")
+	assert.NoError(t, os.WriteFile(templateFile, []byte("replicas: {{ .Values.replicas }}"), 0o644))
+
+	h := &ServerHandler{
+		documents:    document.NewDocumentStore(),
+		helmlsConfig: util.DefaultConfig,
+		chartStore:   charts.NewChartStore(uri.File(tempDir), charts.NewChart, addChartCallback),
+	}
+	_, err := h.documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{
+			URI:  uri.File(templateFile),
+			Text: "replicas: {{ .Values.replicas }}\nrelease: {{ .Release.Name }}",
+		},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+	return h, uri.File(templateFile)
+}
+
+func TestExecuteCommandRenderTemplate(t *testing.T) {
+	h, templateURI := setupRenderTest(t)
+
+	result, err := h.ExecuteCommand(context.Background(), &lsp.ExecuteCommandParams{
+		Command:   RenderTemplateCommand,
+		Arguments: []interface{}{string(templateURI)},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas: 1\nrelease: release-name", result.(*RenderTemplateResult).Content)
+	assert.Equal(t, "helm-ls-rendered://"+templateURI.Filename(), string(result.(*RenderTemplateResult).URI))
+}
+
+func TestExecuteCommandRenderTemplateWithValuesFile(t *testing.T) {
+	h, templateURI := setupRenderTest(t)
+
+	result, err := h.ExecuteCommand(context.Background(), &lsp.ExecuteCommandParams{
+		Command:   RenderTemplateCommand,
+		Arguments: []interface{}{string(templateURI), "values-prod.yaml"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas: 3\nrelease: release-name", result.(*RenderTemplateResult).Content)
+}
+
+func TestExecuteCommandRenderTemplateWithUnknownValuesFile(t *testing.T) {
+	h, templateURI := setupRenderTest(t)
+
+	_, err := h.ExecuteCommand(context.Background(), &lsp.ExecuteCommandParams{
+		Command:   RenderTemplateCommand,
+		Arguments: []interface{}{string(templateURI), "values-missing.yaml"},
+	})
+
+	assert.Error(t, err)
+}
+
+func TestRenderTemplateRequest(t *testing.T) {
+	h, templateURI := setupRenderTest(t)
+
+	result, err := h.Request(context.Background(), RenderTemplateRequest, map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": string(templateURI)},
+		"valuesFile":   "values-prod.yaml",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas: 3\nrelease: release-name", result.(*RenderTemplateResult).Content)
+}
+
+func TestExecuteCommandUnknownCommand(t *testing.T) {
+	h, _ := setupRenderTest(t)
+
+	_, err := h.ExecuteCommand(context.Background(), &lsp.ExecuteCommandParams{Command: "unknown"})
+
+	assert.Error(t, err)
+}
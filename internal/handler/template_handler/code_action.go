@@ -0,0 +1,60 @@
+package templatehandler
+
+import (
+	"context"
+	"encoding/json"
+
+	renderlint "github.com/mrjosh/helm-ls/internal/render_lint"
+	lsp "go.lsp.dev/protocol"
+)
+
+// CodeAction returns quick fixes for the diagnostics of the request that contain a replacement text
+// and the code actions of yamlls that do not change template actions
+func (h *TemplateHandler) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (result []lsp.CodeAction, err error) {
+	result = h.getQuickFixes(params)
+
+	yamllsCodeActions, err := h.yamllsConnector.CallCodeAction(ctx, params)
+	if err != nil {
+		logger.Error("Error getting code actions from yamlls", err)
+		return result, nil
+	}
+	return append(result, filterCodeActions(yamllsCodeActions, h.getTemplateRanges(params.TextDocument.URI))...), nil
+}
+
+func (h *TemplateHandler) getQuickFixes(params *lsp.CodeActionParams) []lsp.CodeAction {
+	result := []lsp.CodeAction{}
+	for _, diagnostic := range params.Context.Diagnostics {
+		if !renderlint.IsRenderLintDiagnostic(diagnostic) || diagnostic.Data == nil {
+			continue
+		}
+		quickFix, ok := getQuickFixData(diagnostic.Data)
+		if !ok {
+			continue
+		}
+		result = append(result, lsp.CodeAction{
+			Title:       quickFix.Title,
+			Kind:        lsp.QuickFix,
+			Diagnostics: []lsp.Diagnostic{diagnostic},
+			IsPreferred: true,
+			Edit: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					params.TextDocument.URI: {{Range: diagnostic.Range, NewText: quickFix.NewText}},
+				},
+			},
+		})
+	}
+	return result
+}
+
+// getQuickFixData converts the data of a diagnostic, which is a map after it was sent to the client and back
+func getQuickFixData(data interface{}) (renderlint.QuickFixData, bool) {
+	quickFix := renderlint.QuickFixData{}
+	marshalled, err := json.Marshal(data)
+	if err != nil {
+		return quickFix, false
+	}
+	if err := json.Unmarshal(marshalled, &quickFix); err != nil || quickFix.NewText == "" {
+		return quickFix, false
+	}
+	return quickFix, true
+}
@@ -0,0 +1,61 @@
+package templatehandler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func TestCodeActionQuickFix(t *testing.T) {
+	fileURI := uri.File("/tmp/chart/templates/pdb.yaml")
+	diagnosticRange := lsp.Range{
+		Start: lsp.Position{Line: 0, Character: 12},
+		End:   lsp.Position{Line: 0, Character: 26},
+	}
+	fixable := lsp.Diagnostic{
+		Range:   diagnosticRange,
+		Source:  "Helm-ls RenderLint",
+		Message: "policy/v1beta1 PodDisruptionBudget was removed in Kubernetes 1.25, use policy/v1 instead",
+		// the data is decoded as a map when it is sent back by the client
+		Data: map[string]interface{}{"title": "Use policy/v1", "newText": "policy/v1"},
+	}
+	notFixable := lsp.Diagnostic{
+		Range:   diagnosticRange,
+		Source:  "Helm-ls RenderLint",
+		Message: "extensions/v1beta1 Ingress was removed in Kubernetes 1.22, use networking.k8s.io/v1 instead",
+	}
+	otherSource := lsp.Diagnostic{
+		Range:  diagnosticRange,
+		Source: "Helm lint",
+		Data:   map[string]interface{}{"title": "Other", "newText": "other"},
+	}
+
+	result, err := (&TemplateHandler{
+		documents:       document.NewDocumentStore(),
+		yamllsConnector: &yamlls.Connector{},
+	}).CodeAction(context.Background(), &lsp.CodeActionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: fileURI},
+		Range:        diagnosticRange,
+		Context:      lsp.CodeActionContext{Diagnostics: []lsp.Diagnostic{fixable, notFixable, otherSource}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []lsp.CodeAction{
+		{
+			Title:       "Use policy/v1",
+			Kind:        lsp.QuickFix,
+			Diagnostics: []lsp.Diagnostic{fixable},
+			IsPreferred: true,
+			Edit: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					fileURI: {{Range: diagnosticRange, NewText: "policy/v1"}},
+				},
+			},
+		},
+	}, result)
+}
@@ -3,18 +3,35 @@ package templatehandler
 import (
 	"context"
 
+	nativeyaml "github.com/mrjosh/helm-ls/internal/adapter/native_yaml"
 	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
 	"github.com/mrjosh/helm-ls/internal/util"
 )
 
 func (h *TemplateHandler) Configure(ctx context.Context, helmlsConfig util.HelmlsConfiguration) {
+	h.helmlsConfig = helmlsConfig
+	h.invalidateRenderCaches()
 	h.configureYamlls(ctx, helmlsConfig.YamllsConfiguration)
 }
 
 func (h *TemplateHandler) configureYamlls(ctx context.Context, config util.YamllsConfiguration) {
 	if config.Enabled {
-		h.setYamllsConnector(yamlls.NewConnector(ctx, config, h.client, h.documents, &yamlls.DefaultCustomHandler))
-		err := h.yamllsConnector.CallInitialize(ctx, h.chartStore.RootURI)
+		connector := yamlls.NewConnector(ctx, config, h.client, h.documents,
+			yamlls.NewCustomSchemaHandler(
+				yamlls.NewCustomSchemaProviderHandler(h.CustomSchemaProvider),
+			),
+		)
+		connector.SetRestartCallback(func() {
+			connector.InitiallySyncOpenTemplateDocuments(h.documents.GetAllTemplateDocs())
+		})
+		if !connector.IsRunning() && config.NativeFallback {
+			logger.Println("yaml-language-server is not available, using the native YAML backend for templates")
+			h.setYamllsConnector(nativeyaml.NewBackend(config, h.helmlsConfig.RenderConfig.KubeVersion, h.crdSchemas, h.client, h.documents, h.chartStore))
+			h.yamllsConnector.InitiallySyncOpenTemplateDocuments(h.documents.GetAllTemplateDocs())
+			return
+		}
+		h.setYamllsConnector(connector)
+		err := connector.CallInitialize(ctx, h.chartStore.RootURI)
 		if err != nil {
 			logger.Error("Error initializing yamlls", err)
 		}
@@ -0,0 +1,43 @@
+package templatehandler
+
+import (
+	"context"
+	"testing"
+
+	nativeyaml "github.com/mrjosh/helm-ls/internal/adapter/native_yaml"
+	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func TestConfigureYamllsFallsBackToNativeBackend(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		nativeFallback bool
+		expectNative   bool
+	}{
+		{"native backend if yamlls is missing", true, true},
+		{"no fallback if disabled", false, false},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			h := &TemplateHandler{
+				documents:       document.NewDocumentStore(),
+				chartStore:      charts.NewChartStore(uri.File("."), charts.NewChart, func(chart *charts.Chart) {}),
+				yamllsConnector: &yamlls.Connector{},
+			}
+			config := util.DefaultConfig.YamllsConfiguration
+			config.Path = "yaml-language-server-does-not-exist"
+			config.MaxRestarts = 0
+			config.NativeFallback = tt.nativeFallback
+
+			h.configureYamlls(context.Background(), config)
+
+			_, isNative := h.yamllsConnector.(*nativeyaml.Backend)
+			assert.Equal(t, tt.expectNative, isNative)
+		})
+	}
+}
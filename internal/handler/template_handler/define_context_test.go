@@ -0,0 +1,296 @@
+package templatehandler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+var (
+	defineContextHelpers = `{{- define "test.host" -}}
+{{ .host }}
+{{- end }}
+{{- define "test.port" -}}
+{{ .port }}
+{{- end }}
+{{- define "test.nested" -}}
+{{ include "test.port" .service }}
+{{- end }}
+{{- define "test.root" -}}
+{{ .Values.ingress.host }} {{ .Values. }}
+{{- end }}
+{{- define "test.ingress" -}}
+{{ .h }}
+{{- end }}
+{{- define "test.dict" -}}
+{{ .svc.port }} {{ .ctx.Values.ingress.host }} {{ .s }}
+{{- end }}
+{{- define "test.list" -}}
+{{ $svc := index . 1 }}{{ $svc.port }} {{ index . 0 "Values" "ingress" "host" }}
+{{- end }}
+`
+	defineContextTemplate = `host: {{ include "test.host" .Values.ingress }}
+port: {{ include "test.port" .Values.service }}
+{{- $other := .Values.otherService }}
+other: {{ include "test.port" $other }}
+nested: {{ include "test.nested" .Values }}
+root: {{ include "test.root" . }}
+{{ template "test.ingress" .Values.ingress }}
+{{ include "test.dict" (dict "ctx" $ "svc" .Values.service) }}
+{{ include "test.list" (list $ .Values.service) }}
+`
+	defineContextValues = `ingress:
+  host: example.com
+service:
+  port: 80
+otherService:
+  port: 8080
+`
+)
+
+func setupDefineContextTest(t *testing.T) (*TemplateHandler, uri.URI, uri.URI) {
+	t.Helper()
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "templates")
+	assert.NoError(t, os.MkdirAll(templateDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(defineContextValues), 0o644))
+	helpersFile := filepath.Join(templateDir, "_helpers.tpl")
+	assert.NoError(t, os.WriteFile(helpersFile, []byte(defineContextHelpers), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(templateDir, "ingress.yaml"), []byte(defineContextTemplate), 0o644))
+
+	documents := document.NewDocumentStore()
+	chartStore := charts.NewChartStore(uri.File(tempDir), charts.NewChart, addChartCallback)
+	h := &TemplateHandler{
+		chartStore:      chartStore,
+		documents:       documents,
+		yamllsConnector: &yamlls.Connector{},
+	}
+	_, err := documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri.File(helpersFile), Text: defineContextHelpers},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+	chart, err := chartStore.GetChartForDoc(uri.File(helpersFile))
+	assert.NoError(t, err)
+	documents.LoadDocsOnNewChart(chart, util.DefaultConfig)
+
+	return h, uri.File(helpersFile), uri.File(filepath.Join(tempDir, "values.yaml"))
+}
+
+func TestHoverInDefineUsesContextOfCallSite(t *testing.T) {
+	h, helpersURI, _ := setupDefineContextTest(t)
+
+	result, err := h.Hover(context.Background(), &lsp.HoverParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+			Position:     lsp.Position{Line: 1, Character: 5},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "### values.yaml\n```yaml\nexample.com\n```\n", result.Contents.Value)
+}
+
+func TestHoverInDefineUsesUnionOfCallSites(t *testing.T) {
+	h, helpersURI, _ := setupDefineContextTest(t)
+
+	result, err := h.Hover(context.Background(), &lsp.HoverParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+			Position:     lsp.Position{Line: 4, Character: 5},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.Contents.Value, "```yaml\n80\n```")
+	assert.Contains(t, result.Contents.Value, "```yaml\n8080\n```")
+}
+
+func TestDefinitionInDefineUsesUnionOfCallSites(t *testing.T) {
+	h, helpersURI, valuesURI := setupDefineContextTest(t)
+
+	result, err := h.Definition(context.Background(), &lsp.DefinitionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+			Position:     lsp.Position{Line: 4, Character: 5},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []lsp.Location{
+		{URI: valuesURI, Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 2}, End: lsp.Position{Line: 3, Character: 2}}},
+		{URI: valuesURI, Range: lsp.Range{Start: lsp.Position{Line: 5, Character: 2}, End: lsp.Position{Line: 5, Character: 2}}},
+	}, result)
+}
+
+func TestCompletionInDefineCalledWithRootContext(t *testing.T) {
+	h, helpersURI, _ := setupDefineContextTest(t)
+
+	result, err := h.Completion(context.Background(), &lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+			Position:     lsp.Position{Line: 10, Character: 38},
+		},
+	})
+
+	assert.NoError(t, err)
+	labels := []string{}
+	for _, item := range result.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.Contains(t, labels, "ingress")
+	assert.Contains(t, labels, "otherService")
+}
+
+func TestCompletionInDefineUsesContextOfCallSite(t *testing.T) {
+	h, helpersURI, _ := setupDefineContextTest(t)
+
+	result, err := h.Completion(context.Background(), &lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+			Position:     lsp.Position{Line: 13, Character: 5},
+		},
+	})
+
+	assert.NoError(t, err)
+	labels := []string{}
+	for _, item := range result.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.Equal(t, []string{"host"}, labels)
+}
+
+func TestHoverInDefineCalledWithDict(t *testing.T) {
+	h, helpersURI, _ := setupDefineContextTest(t)
+
+	testCases := []struct {
+		position lsp.Position
+		expected string
+	}{
+		{position: lsp.Position{Line: 16, Character: 9}, expected: "### values.yaml\n```yaml\n80\n```\n"},
+		{position: lsp.Position{Line: 16, Character: 40}, expected: "### values.yaml\n```yaml\nexample.com\n```\n"},
+	}
+	for _, tt := range testCases {
+		result, err := h.Hover(context.Background(), &lsp.HoverParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+				Position:     tt.position,
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, result.Contents.Value)
+	}
+}
+
+func TestCompletionInDefineCalledWithDict(t *testing.T) {
+	h, helpersURI, _ := setupDefineContextTest(t)
+
+	result, err := h.Completion(context.Background(), &lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+			Position:     lsp.Position{Line: 16, Character: 52},
+		},
+	})
+
+	assert.NoError(t, err)
+	labels := []string{}
+	for _, item := range result.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.ElementsMatch(t, []string{"ctx", "svc"}, labels)
+}
+
+func TestHoverInDefineCalledWithList(t *testing.T) {
+	h, helpersURI, _ := setupDefineContextTest(t)
+
+	testCases := []struct {
+		position lsp.Position
+		expected string
+	}{
+		{position: lsp.Position{Line: 19, Character: 32}, expected: "### values.yaml\n```yaml\n80\n```\n"},
+		{position: lsp.Position{Line: 19, Character: 72}, expected: "### values.yaml\n```yaml\nexample.com\n```\n"},
+	}
+	for _, tt := range testCases {
+		result, err := h.Hover(context.Background(), &lsp.HoverParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+				Position:     tt.position,
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, result.Contents.Value, tt.position)
+	}
+}
+
+func TestHoverInDefineIgnoresCallSitesOfOtherCharts(t *testing.T) {
+	h, helpersURI, _ := setupDefineContextTest(t)
+
+	otherChartDir := filepath.Join(t.TempDir(), "other")
+	otherTemplate := filepath.Join(otherChartDir, "templates", "other.yaml")
+	_, err := h.documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri.File(otherTemplate), Text: `{{ include "test.port" (dict "port" .Values.ingress.host) }}`},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+
+	result, err := h.Hover(context.Background(), &lsp.HoverParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: helpersURI},
+			Position:     lsp.Position{Line: 4, Character: 5},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.Contents.Value, "```yaml\n80\n```")
+	assert.NotContains(t, result.Contents.Value, "example.com")
+}
+
+func TestHoverInDefineOfLibraryChartUsesCallSitesOfParentChart(t *testing.T) {
+	tempDir := t.TempDir()
+	libraryDir := filepath.Join(tempDir, "charts", "library")
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "templates"), 0o755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(libraryDir, "templates"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(defineContextValues), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(libraryDir, "Chart.yaml"), []byte("name: library\nversion: 0.1.0\napiVersion: v2\ntype: library"), 0o644))
+	libraryHelpers := "{{- define \"library.port\" -}}\n{{ .port }}\n{{- end }}\n"
+	helpersFile := filepath.Join(libraryDir, "templates", "_helpers.tpl")
+	assert.NoError(t, os.WriteFile(helpersFile, []byte(libraryHelpers), 0o644))
+	parentTemplate := `port: {{ include "library.port" .Values.service }}`
+	templateFile := filepath.Join(tempDir, "templates", "service.yaml")
+	assert.NoError(t, os.WriteFile(templateFile, []byte(parentTemplate), 0o644))
+
+	documents := document.NewDocumentStore()
+	chartStore := charts.NewChartStore(uri.File(tempDir), charts.NewChart, addChartCallback)
+	h := &TemplateHandler{
+		chartStore:      chartStore,
+		documents:       documents,
+		yamllsConnector: &yamlls.Connector{},
+	}
+	for file, content := range map[string]string{helpersFile: libraryHelpers, templateFile: parentTemplate} {
+		_, err := documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+			TextDocument: lsp.TextDocumentItem{URI: uri.File(file), Text: content},
+		}, util.DefaultConfig)
+		assert.NoError(t, err)
+	}
+
+	result, err := h.Hover(context.Background(), &lsp.HoverParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri.File(helpersFile)},
+			Position:     lsp.Position{Line: 1, Character: 5},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.Contents.Value, "```yaml\n80\n```")
+}
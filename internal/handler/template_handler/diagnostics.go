@@ -1,7 +1,12 @@
 package templatehandler
 
 import (
+	"context"
+
 	helmlint "github.com/mrjosh/helm-ls/internal/helm_lint"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	renderlint "github.com/mrjosh/helm-ls/internal/render_lint"
+	typecheck "github.com/mrjosh/helm-ls/internal/type_check"
 	lsp "go.lsp.dev/protocol"
 )
 
@@ -18,6 +23,29 @@ func (h *TemplateHandler) GetDiagnostics(uri lsp.DocumentURI) []lsp.PublishDiagn
 	if chart == nil {
 		return []lsp.PublishDiagnosticsParams{}
 	}
-	notifications := helmlint.GetDiagnosticsNotifications(chart, doc)
+	doc.DiagnosticsCache.SetTypeCheckDiagnostics(typecheck.GetDiagnostics(chart, h.chartStore, doc))
+	doc.DiagnosticsCache.SetRenderDiagnostics(renderlint.GetDiagnostics(chart, doc, chart.ValuesFiles.GetRenderValues(), h.helmlsConfig.RenderConfig, h.crdSchemas))
+	notifications := helmlint.GetDiagnosticsNotifications(chart, doc, h.helmlsConfig)
 	return notifications
 }
+
+// publishTypeCheckDiagnostics runs the type checks for the document and publishes them
+// together with the cached diagnostics, so that they are shown while typing
+func (h *TemplateHandler) publishTypeCheckDiagnostics(ctx context.Context, doc *document.TemplateDocument) {
+	chart, err := h.chartStore.GetChartOrParentForDoc(doc.URI)
+	if err != nil || chart == nil {
+		logger.Error("Error getting chart info for file", doc.URI, err)
+		return
+	}
+	doc.DiagnosticsCache.SetTypeCheckDiagnostics(typecheck.GetDiagnostics(chart, h.chartStore, doc))
+	if h.client == nil {
+		return
+	}
+	err = h.client.PublishDiagnostics(ctx, &lsp.PublishDiagnosticsParams{
+		URI:         doc.URI,
+		Diagnostics: doc.DiagnosticsCache.GetMergedDiagnostics(),
+	})
+	if err != nil {
+		logger.Error("Error publishing type check diagnostics", err)
+	}
+}
@@ -44,5 +44,6 @@ func (h *TemplateHandler) NewGenericDocumentUseCase(
 		NodeType:       nodeType,
 		ParentNode:     parentNode,
 		ParentNodeType: parentNodeType,
+		RenderConfig:   h.helmlsConfig.RenderConfig,
 	}, nil
 }
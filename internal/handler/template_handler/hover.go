@@ -24,11 +24,15 @@ func (h *TemplateHandler) Hover(ctx context.Context, params *lsp.HoverParams) (r
 		languagefeatures.NewTemplateContextFeature(genericDocumentUseCase),
 		languagefeatures.NewIncludesCallFeature(genericDocumentUseCase),
 		languagefeatures.NewFunctionCallFeature(genericDocumentUseCase),
+		languagefeatures.NewVariablesFeature(genericDocumentUseCase),
 	}
 
 	for _, usecase := range usecases {
 		if usecase.AppropriateForNode() {
 			result, err := usecase.Hover()
+			if err == nil {
+				result = appendHoverSection(result, h.getRenderedActionHover(genericDocumentUseCase))
+			}
 			return protocol.BuildHoverResponse(result, wordRange), err
 		}
 	}
@@ -41,5 +45,9 @@ func (h *TemplateHandler) Hover(ctx context.Context, params *lsp.HoverParams) (r
 		return response, err
 	}
 
+	if rendered := h.getRenderedActionHover(genericDocumentUseCase); rendered != "" {
+		return protocol.BuildHoverResponse(rendered, wordRange), nil
+	}
+
 	return nil, err
 }
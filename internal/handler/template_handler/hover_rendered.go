@@ -0,0 +1,58 @@
+package templatehandler
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	languagefeatures "github.com/mrjosh/helm-ls/internal/language_features"
+)
+
+// getRenderedActionHover renders the action at the hovered node with the helm engine
+// and formats the output (or the error) for the hover response
+func (h *TemplateHandler) getRenderedActionHover(genericDocumentUseCase *languagefeatures.GenericDocumentUseCase) string {
+	if !h.helmlsConfig.RenderConfig.HoverEnabled || genericDocumentUseCase.Chart == nil {
+		return ""
+	}
+
+	action := helmrender.GetActionForNode(genericDocumentUseCase.Node)
+	if action == nil {
+		return ""
+	}
+
+	chart := genericDocumentUseCase.Chart
+	doc := genericDocumentUseCase.Document
+	output, err := h.actionCache.RenderAction(chart, doc.Path, doc.Content, action,
+		func() map[string][]byte { return h.getPartials(chart) }, h.helmlsConfig.RenderConfig)
+	if err != nil {
+		return fmt.Sprintf("### Rendered\nError: %s\n", err.Error())
+	}
+	if output == "" {
+		return "### Rendered\n\"\"\n"
+	}
+	return fmt.Sprintf("### Rendered\n```yaml\n%s\n```\n", output)
+}
+
+// getPartials returns the current content of the partials of the chart by their path
+func (h *TemplateHandler) getPartials(chart *charts.Chart) map[string][]byte {
+	rootDir := chart.RootURI.Filename() + string(filepath.Separator)
+	partials := map[string][]byte{}
+	for _, doc := range h.documents.GetAllTemplateDocs() {
+		if strings.HasPrefix(doc.Path, rootDir) && strings.HasPrefix(filepath.Base(doc.Path), "_") {
+			partials[doc.Path] = doc.Content
+		}
+	}
+	return partials
+}
+
+func appendHoverSection(result string, section string) string {
+	if section == "" {
+		return result
+	}
+	if result == "" {
+		return section
+	}
+	return result + "\n" + section
+}
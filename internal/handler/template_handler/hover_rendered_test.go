@@ -0,0 +1,94 @@
+package templatehandler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
+	"github.com/mrjosh/helm-ls/internal/charts"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func TestHoverRenderedAction(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		templateWithMark string
+		expected         string
+	}{
+		{
+			desc:             "include",
+			templateWithMark: `name: {{ include "example.fullname" ^. }}`,
+			expected:         "### Rendered\n```yaml\nrelease-name-example\n```\n",
+		},
+		{
+			desc:             "pipeline",
+			templateWithMark: `{{ .Values.image | toY^aml }}`,
+			expected:         "### Rendered\n```yaml\npullPolicy: IfNotPresent\nrepository: nginx\ntag: \"\"\n```\n",
+		},
+		{
+			desc:             "inside range",
+			templateWithMark: `{{ range .Values.ingress.hosts }}{{ .ho^st }}{{ end }}`,
+			expected:         "### Rendered\n```yaml\nchart-example.local\n```\n",
+		},
+		{
+			desc:             "inside range with variables",
+			templateWithMark: `{{ $prefix := "host-" }}{{ range $i, $host := .Values.ingress.hosts }}{{ $suffix := "-suffix" }}{{ print $prefix $host.host $su^ffix }}{{ end }}`,
+			expected:         "### Rendered\n```yaml\nhost-chart-example.local-suffix\n```\n",
+		},
+		{
+			desc:             "inside with",
+			templateWithMark: `{{ with .Values.image }}{{ .reposi^tory }}{{ end }}`,
+			expected:         "### Rendered\n```yaml\nnginx\n```\n",
+		},
+		{
+			desc:             "inside with that is not executed",
+			templateWithMark: `{{ with .Values.missing }}{{ .f^oo }}{{ end }}`,
+			expected:         "### Rendered\nError: " + helmrender.ErrNotExecuted.Error() + "\n",
+		},
+		{
+			desc:             "template error",
+			templateWithMark: `{{ fail "b^oom" }}`,
+			expected:         "### Rendered\nError: execution error at (example/templates/hover-rendered.yaml:1:3): boom\n",
+		},
+		{
+			desc:             "empty output",
+			templateWithMark: `{{ .Values.image.t^ag }}`,
+			expected:         "### Rendered\n\"\"\n",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			pos, buf := getPositionForMarkedTestLine(tt.templateWithMark)
+			fileURI := uri.File("../../../testdata/example/templates/hover-rendered.yaml")
+
+			documents := document.NewDocumentStore()
+			_, err := documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+				TextDocument: lsp.TextDocumentItem{URI: fileURI, Text: buf},
+			}, util.DefaultConfig)
+			assert.NoError(t, err)
+
+			h := &TemplateHandler{
+				chartStore:      charts.NewChartStore(uri.File("."), charts.NewChart, addChartCallback),
+				documents:       documents,
+				yamllsConnector: &yamlls.Connector{},
+				helmlsConfig:    util.DefaultConfig,
+				actionCache:     helmrender.NewActionCache(),
+			}
+			result, err := h.Hover(context.Background(), &lsp.HoverParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: fileURI},
+					Position:     pos,
+				},
+			})
+
+			assert.NoError(t, err)
+			assert.NotNil(t, result)
+			assert.Contains(t, result.Contents.Value, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package templatehandler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+var (
+	indexContextTemplate = `{{ index .Values "my-key" "nested" }}
+{{ get .Values.labels "app" }}
+{{ index .Values "my-key" "" }}
+`
+	indexContextValues = `my-key:
+  nested: value
+  other: 1
+labels:
+  app: test
+`
+)
+
+func setupIndexContextTest(t *testing.T) (*TemplateHandler, uri.URI, uri.URI) {
+	t.Helper()
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "templates")
+	assert.NoError(t, os.MkdirAll(templateDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(indexContextValues), 0o644))
+	templateFile := filepath.Join(templateDir, "configmap.yaml")
+	assert.NoError(t, os.WriteFile(templateFile, []byte(indexContextTemplate), 0o644))
+
+	documents := document.NewDocumentStore()
+	h := &TemplateHandler{
+		chartStore:      charts.NewChartStore(uri.File(tempDir), charts.NewChart, addChartCallback),
+		documents:       documents,
+		yamllsConnector: &yamlls.Connector{},
+	}
+	_, err := documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri.File(templateFile), Text: indexContextTemplate},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+	return h, uri.File(templateFile), uri.File(filepath.Join(tempDir, "values.yaml"))
+}
+
+func TestHoverOnIndexKeys(t *testing.T) {
+	h, templateURI, _ := setupIndexContextTest(t)
+
+	testCases := []struct {
+		desc     string
+		position lsp.Position
+		expected string
+	}{
+		{"index key", lsp.Position{Line: 0, Character: 19}, "### values.yaml\n```yaml\nnested: value\nother: 1\n```\n"},
+		{"nested index key", lsp.Position{Line: 0, Character: 28}, "### values.yaml\n```yaml\nvalue\n```\n"},
+		{"get key", lsp.Position{Line: 1, Character: 24}, "### values.yaml\n```yaml\ntest\n```\n"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result, err := h.Hover(context.Background(), &lsp.HoverParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: templateURI},
+					Position:     tt.position,
+				},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Contents.Value)
+		})
+	}
+}
+
+func TestDefinitionOnIndexKeys(t *testing.T) {
+	h, templateURI, valuesURI := setupIndexContextTest(t)
+
+	result, err := h.Definition(context.Background(), &lsp.DefinitionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: templateURI},
+			Position:     lsp.Position{Line: 0, Character: 28},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []lsp.Location{
+		{URI: valuesURI, Range: lsp.Range{Start: lsp.Position{Line: 1, Character: 2}, End: lsp.Position{Line: 1, Character: 2}}},
+	}, result)
+}
+
+func TestCompletionInIndexKeys(t *testing.T) {
+	h, templateURI, _ := setupIndexContextTest(t)
+
+	result, err := h.Completion(context.Background(), &lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: templateURI},
+			Position:     lsp.Position{Line: 2, Character: 27},
+		},
+	})
+
+	assert.NoError(t, err)
+	labels := []string{}
+	for _, item := range result.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.ElementsMatch(t, []string{"nested", "other"}, labels)
+}
@@ -0,0 +1,43 @@
+package templatehandler
+
+import (
+	"context"
+
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
+	"go.lsp.dev/uri"
+)
+
+// CustomSchemaProvider selects the schema for a template by the apiVersion and kind of its objects.
+// They are read from the template with the actions removed or, if they are set by actions,
+// from the rendered template, which is cached until the template, the values or the config change.
+// If the kinds are unknown no schema is returned, so that yaml-language-server falls back
+// to the schemas from its settings.
+func (h *TemplateHandler) CustomSchemaProvider(ctx context.Context, URI uri.URI) (uri.URI, error) {
+	doc, ok := h.documents.GetTemplateDoc(URI)
+	if !ok || h.kubernetesSchemas == nil {
+		return "", nil
+	}
+
+	chart, err := h.chartStore.GetChartForDoc(URI)
+	if err != nil {
+		logger.Error("Could not get a chart for the document: ", err)
+	}
+
+	kinds, complete := kubernetesschema.GetGroupVersionKinds(lsplocal.TrimTemplate(doc.Ast.Copy(), doc.Content))
+	if !complete {
+		if chart == nil || chart.HelmChart == nil {
+			return "", nil
+		}
+		output, err := h.templateCache.RenderTemplate(chart, doc.Path, doc.Content, h.helmlsConfig.RenderConfig)
+		if err != nil {
+			logger.Debug("Could not render template to get the kinds", doc.Path, err)
+			return "", nil
+		}
+		if kinds, complete = kubernetesschema.GetGroupVersionKinds(output); !complete {
+			return "", nil
+		}
+	}
+
+	return h.kubernetesSchemas.GetSchemaForKinds(chart, kinds, h.helmlsConfig.RenderConfig.KubeVersion)
+}
@@ -0,0 +1,72 @@
+package templatehandler
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func TestCustomSchemaProvider(t *testing.T) {
+	deployment, err := os.ReadFile("../../../testdata/example/templates/deployment.yaml")
+	assert.NoError(t, err)
+	ingress, err := os.ReadFile("../../../testdata/example/templates/ingress.yaml")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		path     string
+		content  string
+		expected uri.URI
+	}{
+		{
+			desc:     "kind from the template",
+			path:     "../../../testdata/example/templates/deployment.yaml",
+			content:  string(deployment),
+			expected: uri.URI(kubernetesschema.GetKubernetesSchemaURL("") + "/deployment-apps-v1.json"),
+		},
+		{
+			desc:     "apiVersion set by an action is taken from the rendered template",
+			path:     "../../../testdata/example/templates/configmap-schema-test.yaml",
+			content:  "apiVersion: {{ .Values.configMapApiVersion | default \"v1\" }}\nkind: ConfigMap\n",
+			expected: uri.URI(kubernetesschema.GetKubernetesSchemaURL("") + "/configmap-v1.json"),
+		},
+		{
+			desc:     "no schema if the rendered template is empty",
+			path:     "../../../testdata/example/templates/ingress.yaml",
+			content:  string(ingress),
+			expected: "",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			documents := document.NewDocumentStore()
+			fileURI := uri.File(tt.path)
+			documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+				TextDocument: lsp.TextDocumentItem{URI: fileURI, Text: tt.content},
+			}, util.DefaultConfig)
+
+			kubernetesSchemas, err := kubernetesschema.NewSchemaProvider(kubernetesschema.NewCRDSchemaCache())
+			assert.NoError(t, err)
+			h := &TemplateHandler{
+				chartStore:        charts.NewChartStore(uri.File("."), charts.NewChart, addChartCallback),
+				documents:         documents,
+				helmlsConfig:      util.DefaultConfig,
+				kubernetesSchemas: kubernetesSchemas,
+				templateCache:     helmrender.NewTemplateCache(),
+			}
+
+			result, err := h.CustomSchemaProvider(context.Background(), fileURI)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
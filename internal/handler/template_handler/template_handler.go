@@ -3,26 +3,43 @@ package templatehandler
 import (
 	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
 	"github.com/mrjosh/helm-ls/internal/charts"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
 	"github.com/mrjosh/helm-ls/internal/log"
 	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
 	"go.lsp.dev/protocol"
 )
 
 var logger = log.GetLogger()
 
 type TemplateHandler struct {
-	client          protocol.Client
-	documents       *document.DocumentStore
-	chartStore      *charts.ChartStore
-	yamllsConnector *yamlls.Connector
+	client            protocol.Client
+	documents         *document.DocumentStore
+	chartStore        *charts.ChartStore
+	yamllsConnector   yamlBackend
+	helmlsConfig      util.HelmlsConfiguration
+	actionCache       *helmrender.ActionCache
+	templateCache     *helmrender.TemplateCache
+	kubernetesSchemas *kubernetesschema.SchemaProvider
+	crdSchemas        *kubernetesschema.CRDSchemaCache
 }
 
 func NewTemplateHandler(client protocol.Client, documents *document.DocumentStore, chartStore *charts.ChartStore) *TemplateHandler {
+	crdSchemas := kubernetesschema.NewCRDSchemaCache()
+	kubernetesSchemas, err := kubernetesschema.NewSchemaProvider(crdSchemas)
+	if err != nil {
+		logger.Error("Failed to create the kubernetes schema provider", err)
+	}
 	return &TemplateHandler{
-		client:          client,
-		documents:       documents,
-		chartStore:      chartStore,
-		yamllsConnector: &yamlls.Connector{},
+		client:            client,
+		documents:         documents,
+		chartStore:        chartStore,
+		yamllsConnector:   &yamlls.Connector{},
+		actionCache:       helmrender.NewActionCache(),
+		templateCache:     helmrender.NewTemplateCache(),
+		kubernetesSchemas: kubernetesSchemas,
+		crdSchemas:        crdSchemas,
 	}
 }
 
@@ -30,6 +47,11 @@ func (h *TemplateHandler) SetClient(client protocol.Client) {
 	h.client = client
 }
 
-func (h *TemplateHandler) setYamllsConnector(yamllsConnector *yamlls.Connector) {
+// Stop implements handler.LangHandler.
+func (h *TemplateHandler) Stop() {
+	h.yamllsConnector.Stop()
+}
+
+func (h *TemplateHandler) setYamllsConnector(yamllsConnector yamlBackend) {
 	h.yamllsConnector = yamllsConnector
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 
-	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
 	"github.com/mrjosh/helm-ls/internal/util"
 	lsp "go.lsp.dev/protocol"
 )
@@ -15,6 +14,7 @@ func (h *TemplateHandler) DidOpen(ctx context.Context, params *lsp.DidOpenTextDo
 		logger.Error(err)
 		return err
 	}
+	h.invalidateRenderCaches()
 
 	h.yamllsConnector.DocumentDidOpenTemplate(doc.Ast, *params)
 
@@ -27,6 +27,7 @@ func (h *TemplateHandler) DidSave(ctx context.Context, params *lsp.DidSaveTextDo
 		return errors.New("Could not get document: " + params.TextDocument.URI.Filename())
 	}
 
+	h.invalidateRenderCaches()
 	h.yamllsConnector.DocumentDidSaveTemplate(doc, *params)
 
 	return nil
@@ -42,19 +43,16 @@ func (h *TemplateHandler) PostDidChange(ctx context.Context, params *lsp.DidChan
 		return errors.New("Could not get document AST: " + params.TextDocument.URI.Filename())
 	}
 
-	shouldSendFullUpdateToYamlls := false
-	for _, change := range params.ContentChanges {
-		node := templateast.NodeAtPosition(doc.Ast, change.Range.Start)
-		if node != nil && node.Type() != "text" {
-			shouldSendFullUpdateToYamlls = true
-			break
-		}
-	}
-	if shouldSendFullUpdateToYamlls {
-		h.yamllsConnector.DocumentDidChangeFullSyncTemplate(doc, *params)
-	} else {
-		h.yamllsConnector.DocumentDidChangeTemplate(doc, *params)
-	}
+	h.invalidateRenderCaches()
+	h.yamllsConnector.DocumentDidChangeFullSyncTemplate(doc, *params)
+	h.publishTypeCheckDiagnostics(ctx, doc)
 
 	return nil
 }
+
+// invalidateRenderCaches drops the rendered templates and actions, because the changed template
+// may be a partial that is used by the others
+func (h *TemplateHandler) invalidateRenderCaches() {
+	h.actionCache.Invalidate()
+	h.templateCache.Invalidate()
+}
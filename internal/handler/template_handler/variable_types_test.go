@@ -0,0 +1,124 @@
+package templatehandler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+var (
+	variableTypesTemplate = `{{- $svc := .Values.service }}
+{{ $svc.port }} {{ $svc. }}
+{{- $d := dict "svc" .Values.service "name" "test" }}
+{{ $d. }} {{ $d.svc.port }} {{ $d.name }}
+{{- range $k, $v := .Values.env }}
+{{ $v.name }} {{ $k }}
+{{- range $i, $p := $v.ports }}
+{{ $p.port }}
+{{- end }}
+{{- end }}
+{{- $n := len .Values.env }}{{ $n }}
+`
+	variableTypesValues = `service:
+  port: 80
+  name: web
+env:
+  - name: first
+    ports:
+      - port: 8080
+`
+)
+
+func setupVariableTypesTest(t *testing.T) (*TemplateHandler, uri.URI) {
+	t.Helper()
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "templates")
+	assert.NoError(t, os.MkdirAll(templateDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(variableTypesValues), 0o644))
+	templateFile := filepath.Join(templateDir, "deployment.yaml")
+	assert.NoError(t, os.WriteFile(templateFile, []byte(variableTypesTemplate), 0o644))
+
+	documents := document.NewDocumentStore()
+	h := &TemplateHandler{
+		chartStore:      charts.NewChartStore(uri.File(tempDir), charts.NewChart, addChartCallback),
+		documents:       documents,
+		yamllsConnector: &yamlls.Connector{},
+	}
+	_, err := documents.DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri.File(templateFile), Text: variableTypesTemplate},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+	return h, uri.File(templateFile)
+}
+
+func TestHoverOnVariables(t *testing.T) {
+	h, templateURI := setupVariableTypesTest(t)
+
+	testCases := []struct {
+		desc     string
+		position lsp.Position
+		expected string
+	}{
+		{"values subtree", lsp.Position{Line: 1, Character: 5}, "### $svc\nType: `.Values.service`\n\n### values.yaml\n```yaml\nname: web\nport: 80\n```\n"},
+		{"field of values subtree", lsp.Position{Line: 1, Character: 9}, "### values.yaml\n```yaml\n80\n```\n"},
+		{"dict literal", lsp.Position{Line: 3, Character: 4}, "### $d\nType: `dict{name, svc}`\n"},
+		{"values in dict literal", lsp.Position{Line: 3, Character: 21}, "### values.yaml\n```yaml\n80\n```\n"},
+		{"scalar in dict literal", lsp.Position{Line: 3, Character: 36}, "Type: `string`\n"},
+		{"range key", lsp.Position{Line: 5, Character: 18}, "### $k\nType: `int | string`\n"},
+		{"nested range", lsp.Position{Line: 7, Character: 7}, "### values.yaml\n```yaml\n8080\n```\n"},
+		{"function return type", lsp.Position{Line: 10, Character: 32}, "### $n\nType: `int`\n"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result, err := h.Hover(context.Background(), &lsp.HoverParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: templateURI},
+					Position:     tt.position,
+				},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Contents.Value)
+		})
+	}
+}
+
+func TestCompletionOnVariables(t *testing.T) {
+	h, templateURI := setupVariableTypesTest(t)
+
+	testCases := []struct {
+		desc     string
+		position lsp.Position
+		expected []string
+	}{
+		{"values subtree", lsp.Position{Line: 1, Character: 24}, []string{"port", "name"}},
+		{"dict literal", lsp.Position{Line: 3, Character: 6}, []string{"svc", "name"}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result, err := h.Completion(context.Background(), &lsp.CompletionParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: templateURI},
+					Position:     tt.position,
+				},
+			})
+
+			assert.NoError(t, err)
+			labels := []string{}
+			for _, item := range result.Items {
+				labels = append(labels, item.Label)
+			}
+			assert.ElementsMatch(t, tt.expected, labels)
+		})
+	}
+}
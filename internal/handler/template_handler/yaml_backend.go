@@ -0,0 +1,27 @@
+package templatehandler
+
+import (
+	"context"
+
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+)
+
+// yamlBackend provides the YAML features of templates,
+// it is implemented by yamlls.Connector and the native fallback nativeyaml.Backend
+type yamlBackend interface {
+	InitiallySyncOpenTemplateDocuments(docs []*document.TemplateDocument)
+	DocumentDidOpenTemplate(ast *sitter.Tree, params lsp.DidOpenTextDocumentParams)
+	DocumentDidSaveTemplate(doc *document.TemplateDocument, params lsp.DidSaveTextDocumentParams)
+	DocumentDidChangeFullSyncTemplate(doc *document.TemplateDocument, params lsp.DidChangeTextDocumentParams)
+	CallCompletion(ctx context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error)
+	CallHoverOrComplete(ctx context.Context, params lsp.HoverParams, word string) (*lsp.Hover, error)
+	CallDocumentSymbol(ctx context.Context, params *lsp.DocumentSymbolParams) ([]interface{}, error)
+	CallCodeAction(ctx context.Context, params *lsp.CodeActionParams) ([]lsp.CodeAction, error)
+	CallFoldingRanges(ctx context.Context, params *lsp.FoldingRangeParams) ([]lsp.FoldingRange, error)
+	CallSelectionRange(ctx context.Context, params *lsp.SelectionRangeParams) ([]lsp.SelectionRange, error)
+	CallDocumentLink(ctx context.Context, params *lsp.DocumentLinkParams) ([]lsp.DocumentLink, error)
+	CallFormatting(ctx context.Context, params *lsp.DocumentFormattingParams) ([]lsp.TextEdit, error)
+	Stop()
+}
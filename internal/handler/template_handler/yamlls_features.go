@@ -0,0 +1,70 @@
+package templatehandler
+
+import (
+	"context"
+
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
+	lsp "go.lsp.dev/protocol"
+)
+
+// FoldingRanges returns the folding ranges of yamlls that do not cut through template actions
+func (h *TemplateHandler) FoldingRanges(ctx context.Context, params *lsp.FoldingRangeParams) (result []lsp.FoldingRange, err error) {
+	foldingRanges, err := h.yamllsConnector.CallFoldingRanges(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return filterFoldingRanges(foldingRanges, h.getTemplateRanges(params.TextDocument.URI)), nil
+}
+
+// SelectionRange returns the selection ranges of yamlls that do not cut through template actions
+func (h *TemplateHandler) SelectionRange(ctx context.Context, params *lsp.SelectionRangeParams) (result []lsp.SelectionRange, err error) {
+	selectionRanges, err := h.yamllsConnector.CallSelectionRange(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	templateRanges := h.getTemplateRanges(params.TextDocument.URI)
+	result = []lsp.SelectionRange{}
+	for i, selectionRange := range selectionRanges {
+		filtered := filterSelectionRange(&selectionRange, templateRanges)
+		if filtered == nil && i < len(params.Positions) {
+			// the result must contain a selection range for each position
+			filtered = &lsp.SelectionRange{Range: lsp.Range{Start: params.Positions[i], End: params.Positions[i]}}
+		}
+		if filtered != nil {
+			result = append(result, *filtered)
+		}
+	}
+	return result, nil
+}
+
+// DocumentLink returns the document links of yamlls that are not part of template actions
+func (h *TemplateHandler) DocumentLink(ctx context.Context, params *lsp.DocumentLinkParams) (result []lsp.DocumentLink, err error) {
+	documentLinks, err := h.yamllsConnector.CallDocumentLink(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	templateRanges := h.getTemplateRanges(params.TextDocument.URI)
+	result = []lsp.DocumentLink{}
+	for _, documentLink := range documentLinks {
+		if !touchesTemplate(documentLink.Range, templateRanges) {
+			result = append(result, documentLink)
+		}
+	}
+	return result, nil
+}
+
+// Formatting returns the formatting edits of yamlls for the lines without template actions.
+// yamlls usually replaces the whole document, so its edits are applied to the projection that yamlls
+// formatted and the result is compared line by line. Changed lines that contain actions are kept as they are.
+func (h *TemplateHandler) Formatting(ctx context.Context, params *lsp.DocumentFormattingParams) (result []lsp.TextEdit, err error) {
+	edits, err := h.yamllsConnector.CallFormatting(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := h.documents.GetTemplateDoc(params.TextDocument.URI)
+	if !ok || doc.Ast == nil || len(edits) == 0 {
+		return []lsp.TextEdit{}, nil
+	}
+	projection := lsplocal.ProjectTemplate(doc.Ast.Copy(), doc.Content).Text
+	return getLineEdits(projection, applyTextEdits(projection, edits), h.getTemplateRanges(params.TextDocument.URI)), nil
+}
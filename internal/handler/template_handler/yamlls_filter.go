@@ -0,0 +1,179 @@
+package templatehandler
+
+import (
+	"sort"
+	"strings"
+
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/pmezard/go-difflib/difflib"
+	lsp "go.lsp.dev/protocol"
+)
+
+// getTemplateRanges returns the ranges of the template actions of a document.
+// yamlls works on the trimmed template which has the same positions as the document,
+// so its results can be used directly as long as they do not touch these ranges.
+func (h *TemplateHandler) getTemplateRanges(uri lsp.DocumentURI) []lsp.Range {
+	doc, ok := h.documents.GetTemplateDoc(uri)
+	if !ok || doc.Ast == nil {
+		return []lsp.Range{}
+	}
+	result := []lsp.Range{}
+	for _, templateRange := range lsplocal.GetTemplateRanges(doc.Ast, doc.Content) {
+		result = append(result, lsp.Range{
+			Start: lsp.Position{Line: templateRange.StartPoint.Row, Character: templateRange.StartPoint.Column},
+			End:   lsp.Position{Line: templateRange.EndPoint.Row, Character: templateRange.EndPoint.Column},
+		})
+	}
+	return result
+}
+
+func isBefore(a, b lsp.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// touchesTemplate returns true if the range overlaps any of the template ranges
+func touchesTemplate(r lsp.Range, templateRanges []lsp.Range) bool {
+	for _, templateRange := range templateRanges {
+		if isBefore(r.Start, templateRange.End) && isBefore(templateRange.Start, r.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// cutsTemplate returns true if the range contains only a part of a template range
+func cutsTemplate(r lsp.Range, templateRanges []lsp.Range) bool {
+	for _, templateRange := range templateRanges {
+		contained := !isBefore(templateRange.Start, r.Start) && !isBefore(r.End, templateRange.End)
+		if touchesTemplate(r, []lsp.Range{templateRange}) && !contained {
+			return true
+		}
+	}
+	return false
+}
+
+func filterTextEdits(edits []lsp.TextEdit, templateRanges []lsp.Range) []lsp.TextEdit {
+	result := []lsp.TextEdit{}
+	for _, edit := range edits {
+		if !touchesTemplate(edit.Range, templateRanges) {
+			result = append(result, edit)
+		}
+	}
+	return result
+}
+
+// applyTextEdits returns the text with the edits applied, the ranges of the edits refer to the original text
+func applyTextEdits(text string, edits []lsp.TextEdit) string {
+	sorted := make([]lsp.TextEdit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return isBefore(sorted[j].Range.Start, sorted[i].Range.Start)
+	})
+	content := []byte(text)
+	for _, edit := range sorted {
+		start := min(util.PositionToIndex(edit.Range.Start, content), len(content))
+		end := max(min(util.PositionToIndex(edit.Range.End, content), len(content)), start)
+		content = append(content[:start:start], append([]byte(edit.NewText), content[end:]...)...)
+	}
+	return string(content)
+}
+
+// getLineEdits compares the texts line by line and returns the edits of the changed lines that do not
+// touch the template ranges. Trailing whitespace is ignored to match the lines, so that the lines of
+// control structures, which are blank in the projection, do not merge the changes around them.
+func getLineEdits(text, formatted string, templateRanges []lsp.Range) []lsp.TextEdit {
+	lines, formattedLines := strings.SplitAfter(text, "\n"), strings.SplitAfter(formatted, "\n")
+	result := []lsp.TextEdit{}
+	addEdit := func(i1, i2, j1, j2 int) {
+		edit := lsp.TextEdit{
+			Range:   lsp.Range{Start: getLineStart(lines, i1), End: getLineStart(lines, i2)},
+			NewText: strings.Join(formattedLines[j1:j2], ""),
+		}
+		if !touchesTemplate(edit.Range, templateRanges) {
+			result = append(result, edit)
+		}
+	}
+	matcher := difflib.NewMatcher(trimLines(lines), trimLines(formattedLines))
+	for _, opCode := range matcher.GetOpCodes() {
+		if opCode.Tag != 'e' && (opCode.Tag != 'r' || opCode.I2-opCode.I1 != opCode.J2-opCode.J1) {
+			addEdit(opCode.I1, opCode.I2, opCode.J1, opCode.J2)
+			continue
+		}
+		for k := 0; k < opCode.I2-opCode.I1; k++ {
+			if lines[opCode.I1+k] != formattedLines[opCode.J1+k] {
+				addEdit(opCode.I1+k, opCode.I1+k+1, opCode.J1+k, opCode.J1+k+1)
+			}
+		}
+	}
+	return result
+}
+
+func trimLines(lines []string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = strings.TrimRight(line, " \t\r\n")
+	}
+	return result
+}
+
+// getLineStart returns the start of the line, the end of the text for the line after the last line
+func getLineStart(lines []string, line int) lsp.Position {
+	if line < len(lines) {
+		return lsp.Position{Line: uint32(line)}
+	}
+	// the last element of the lines has no line break
+	return lsp.Position{Line: uint32(len(lines) - 1), Character: uint32(len(lines[len(lines)-1]))}
+}
+
+// filterCodeActions removes code actions with edits that would change template actions
+func filterCodeActions(codeActions []lsp.CodeAction, templateRanges []lsp.Range) []lsp.CodeAction {
+	result := []lsp.CodeAction{}
+	for _, codeAction := range codeActions {
+		if codeAction.Edit == nil || !workspaceEditTouchesTemplate(codeAction.Edit, templateRanges) {
+			result = append(result, codeAction)
+		}
+	}
+	return result
+}
+
+func workspaceEditTouchesTemplate(edit *lsp.WorkspaceEdit, templateRanges []lsp.Range) bool {
+	for _, edits := range edit.Changes {
+		if len(filterTextEdits(edits, templateRanges)) != len(edits) {
+			return true
+		}
+	}
+	for _, documentEdit := range edit.DocumentChanges {
+		if len(filterTextEdits(documentEdit.Edits, templateRanges)) != len(documentEdit.Edits) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSelectionRange removes the ranges of the chain that would select only a part of a template action
+func filterSelectionRange(selectionRange *lsp.SelectionRange, templateRanges []lsp.Range) *lsp.SelectionRange {
+	if selectionRange == nil {
+		return nil
+	}
+	parent := filterSelectionRange(selectionRange.Parent, templateRanges)
+	if cutsTemplate(selectionRange.Range, templateRanges) {
+		return parent
+	}
+	return &lsp.SelectionRange{Range: selectionRange.Range, Parent: parent}
+}
+
+// filterFoldingRanges removes folding ranges that start or end within a template action
+func filterFoldingRanges(foldingRanges []lsp.FoldingRange, templateRanges []lsp.Range) []lsp.FoldingRange {
+	result := []lsp.FoldingRange{}
+	for _, foldingRange := range foldingRanges {
+		lines := lsp.Range{
+			Start: lsp.Position{Line: foldingRange.StartLine},
+			End:   lsp.Position{Line: foldingRange.EndLine + 1},
+		}
+		if !cutsTemplate(lines, templateRanges) {
+			result = append(result, foldingRange)
+		}
+	}
+	return result
+}
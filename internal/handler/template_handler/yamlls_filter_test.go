@@ -0,0 +1,105 @@
+package templatehandler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func lspRange(startLine, startCharacter, endLine, endCharacter uint32) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: startLine, Character: startCharacter},
+		End:   lsp.Position{Line: endLine, Character: endCharacter},
+	}
+}
+
+// template ranges of:
+//
+//	a: {{ .Values.a }}
+//	{{- if .Values.b }}
+//	b:
+//	  c: d
+//	{{- end }}
+var filterTemplateRanges = []lsp.Range{lspRange(0, 3, 0, 18), lspRange(1, 0, 1, 19), lspRange(4, 0, 4, 10)}
+
+func TestFilterTextEdits(t *testing.T) {
+	edits := []lsp.TextEdit{
+		{Range: lspRange(0, 0, 0, 2), NewText: "x"},
+		{Range: lspRange(0, 2, 0, 3), NewText: ""},
+		{Range: lspRange(0, 4, 0, 5), NewText: "x"},
+		{Range: lspRange(0, 0, 5, 0), NewText: "formatted"},
+		{Range: lspRange(3, 2, 3, 6), NewText: "c: e"},
+	}
+	assert.Equal(t, []lsp.TextEdit{edits[0], edits[1], edits[4]}, filterTextEdits(edits, filterTemplateRanges))
+}
+
+func TestGetLineEdits(t *testing.T) {
+	// projection of the template of filterTemplateRanges, yamlls removed the spaces after the keys
+	// and fixed the indentation of c
+	text := "a:               \n                   \nb:   \n    c: d\n          \nd:  e"
+	formatted := "a: _h0\n\nb:\n  c: d\n\nd: e"
+
+	edits := getLineEdits(text, applyTextEdits(text, []lsp.TextEdit{
+		{Range: lspRange(0, 0, 5, 5), NewText: formatted},
+	}), filterTemplateRanges)
+
+	assert.Equal(t, []lsp.TextEdit{
+		{Range: lspRange(2, 0, 3, 0), NewText: "b:\n"},
+		{Range: lspRange(3, 0, 4, 0), NewText: "  c: d\n"},
+		{Range: lspRange(5, 0, 5, 5), NewText: "d: e"},
+	}, edits)
+}
+
+func TestApplyTextEdits(t *testing.T) {
+	edits := []lsp.TextEdit{
+		{Range: lspRange(1, 0, 1, 1), NewText: "c"},
+		{Range: lspRange(0, 0, 0, 1), NewText: "b"},
+		{Range: lspRange(2, 0, 9, 0), NewText: "d"},
+	}
+	assert.Equal(t, "b\nc\nd", applyTextEdits("a\na\na\n", edits))
+}
+
+func TestFilterCodeActions(t *testing.T) {
+	fileURI := uri.File("/tmp/templates/deployment.yaml")
+	allowed := lsp.CodeAction{Title: "allowed", Edit: &lsp.WorkspaceEdit{
+		Changes: map[lsp.DocumentURI][]lsp.TextEdit{fileURI: {{Range: lspRange(3, 2, 3, 6)}}},
+	}}
+	touchesAction := lsp.CodeAction{Title: "touches action", Edit: &lsp.WorkspaceEdit{
+		DocumentChanges: []lsp.TextDocumentEdit{{Edits: []lsp.TextEdit{{Range: lspRange(1, 4, 1, 6)}}}},
+	}}
+	command := lsp.CodeAction{Title: "command", Command: &lsp.Command{Command: "open"}}
+
+	assert.Equal(t, []lsp.CodeAction{allowed, command},
+		filterCodeActions([]lsp.CodeAction{allowed, touchesAction, command}, filterTemplateRanges))
+}
+
+func TestFilterSelectionRange(t *testing.T) {
+	selectionRange := &lsp.SelectionRange{
+		Range: lspRange(3, 2, 3, 3),
+		Parent: &lsp.SelectionRange{
+			// cuts through the if action
+			Range: lspRange(1, 10, 3, 6),
+			Parent: &lsp.SelectionRange{
+				Range: lspRange(0, 0, 4, 10),
+			},
+		},
+	}
+	assert.Equal(t, &lsp.SelectionRange{
+		Range:  lspRange(3, 2, 3, 3),
+		Parent: &lsp.SelectionRange{Range: lspRange(0, 0, 4, 10)},
+	}, filterSelectionRange(selectionRange, filterTemplateRanges))
+}
+
+func TestFilterFoldingRanges(t *testing.T) {
+	// a multi line action, e.g. a comment, from line 5 to line 7
+	templateRanges := append([]lsp.Range{lspRange(5, 0, 7, 5)}, filterTemplateRanges...)
+	foldingRanges := []lsp.FoldingRange{
+		{StartLine: 2, EndLine: 3},
+		{StartLine: 0, EndLine: 4},
+		{StartLine: 2, EndLine: 8},
+		{StartLine: 6, EndLine: 8},
+	}
+	assert.Equal(t, foldingRanges[:3], filterFoldingRanges(foldingRanges, templateRanges))
+}
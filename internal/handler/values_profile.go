@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+// DidChangeValuesProfileNotification is sent to the client after the active values profile of a chart
+// was changed, the client can persist the activeProfiles in its settings
+const DidChangeValuesProfileNotification = "helm-ls/didChangeValuesProfile"
+
+type SelectValuesProfileParams struct {
+	// URI of the chart directory or any file of the chart
+	URI lsp.DocumentURI `json:"uri"`
+	// ValuesFiles is the ordered list of values files, an empty list removes the active profile
+	ValuesFiles []string `json:"valuesFiles"`
+}
+
+type DidChangeValuesProfileParams struct {
+	Chart       string   `json:"chart"`
+	ValuesFiles []string `json:"valuesFiles"`
+	// ActiveProfiles is the new value of the valuesFiles.activeProfiles setting
+	ActiveProfiles map[string][]string `json:"activeProfiles"`
+}
+
+func selectValuesProfileParamsFromArguments(arguments []interface{}) (SelectValuesProfileParams, error) {
+	result := SelectValuesProfileParams{}
+	if len(arguments) == 0 {
+		return result, fmt.Errorf("%s requires the chart or document URI as first argument", SelectValuesProfileCommand)
+	}
+
+	fileURI, ok := arguments[0].(string)
+	if !ok {
+		return result, fmt.Errorf("%s requires the chart or document URI as first argument, got %v", SelectValuesProfileCommand, arguments[0])
+	}
+	result.URI = uri.URI(fileURI)
+
+	if len(arguments) < 2 || arguments[1] == nil {
+		return result, nil
+	}
+	valuesFiles, ok := arguments[1].([]interface{})
+	if !ok {
+		return result, fmt.Errorf("%s requires a list of values files as second argument, got %v", SelectValuesProfileCommand, arguments[1])
+	}
+	for _, valuesFile := range valuesFiles {
+		name, ok := valuesFile.(string)
+		if !ok {
+			return result, fmt.Errorf("%s requires a list of values files as second argument, got %v", SelectValuesProfileCommand, valuesFile)
+		}
+		result.ValuesFiles = append(result.ValuesFiles, name)
+	}
+	return result, nil
+}
+
+// selectValuesProfile sets the active values profile of a chart, updates the diagnostics
+// of the open documents of the chart and notifies the client about the new settings
+func (h *ServerHandler) selectValuesProfile(ctx context.Context, params SelectValuesProfileParams) (*DidChangeValuesProfileParams, error) {
+	chart, err := h.chartStore.GetChartForURI(params.URI)
+	if err != nil {
+		chart, err = h.chartStore.GetChartForDoc(params.URI)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := chart.ValuesFiles.SetActiveProfile(params.ValuesFiles); err != nil {
+		return nil, err
+	}
+
+	activeProfiles := maps.Clone(h.helmlsConfig.ValuesFilesConfig.ActiveProfiles)
+	if activeProfiles == nil {
+		activeProfiles = map[string][]string{}
+	}
+	if len(params.ValuesFiles) == 0 {
+		delete(activeProfiles, chart.Name())
+	} else {
+		activeProfiles[chart.Name()] = params.ValuesFiles
+	}
+	h.helmlsConfig.ValuesFilesConfig.ActiveProfiles = activeProfiles
+	h.chartStore.SetValuesFilesConfig(h.helmlsConfig.ValuesFilesConfig)
+
+	result := &DidChangeValuesProfileParams{
+		Chart:          chart.Name(),
+		ValuesFiles:    params.ValuesFiles,
+		ActiveProfiles: activeProfiles,
+	}
+	if h.connPool != nil {
+		if err := h.connPool.Notify(ctx, DidChangeValuesProfileNotification, result); err != nil {
+			logger.Error("Error sending values profile notification", err)
+		}
+	}
+
+	if templateHandler, ok := h.langHandlers[document.TemplateDocumentType]; ok {
+		for _, doc := range h.documents.GetAllTemplateDocs() {
+			if doc.IsOpen && isInDirectory(doc.Path, chart.RootURI.Filename()) {
+				h.publishDiagnostics(ctx, templateHandler.GetDiagnostics(doc.URI))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// isInDirectory returns true if the path is inside of the directory, /charts/app-two is not inside of /charts/app
+func isInDirectory(path string, directory string) bool {
+	return strings.HasPrefix(path, directory+string(filepath.Separator))
+}
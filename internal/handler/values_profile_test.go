@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+)
+
+func TestExecuteCommandSelectValuesProfile(t *testing.T) {
+	h, templateURI := setupRenderTest(t)
+
+	result, err := h.ExecuteCommand(context.Background(), &lsp.ExecuteCommandParams{
+		Command:   SelectValuesProfileCommand,
+		Arguments: []interface{}{string(templateURI), []interface{}{"values.yaml", "values-prod.yaml"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &DidChangeValuesProfileParams{
+		Chart:          "test",
+		ValuesFiles:    []string{"values.yaml", "values-prod.yaml"},
+		ActiveProfiles: map[string][]string{"test": {"values.yaml", "values-prod.yaml"}},
+	}, result)
+	assert.Equal(t, map[string][]string{"test": {"values.yaml", "values-prod.yaml"}}, h.helmlsConfig.ValuesFilesConfig.ActiveProfiles)
+
+	rendered, err := h.renderTemplate(RenderTemplateParams{TextDocument: lsp.TextDocumentIdentifier{URI: templateURI}})
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas: 3\nrelease: release-name", rendered.Content)
+
+	_, err = h.ExecuteCommand(context.Background(), &lsp.ExecuteCommandParams{
+		Command:   SelectValuesProfileCommand,
+		Arguments: []interface{}{string(templateURI), []interface{}{}},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, h.helmlsConfig.ValuesFilesConfig.ActiveProfiles)
+
+	rendered, err = h.renderTemplate(RenderTemplateParams{TextDocument: lsp.TextDocumentIdentifier{URI: templateURI}})
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas: 1\nrelease: release-name", rendered.Content)
+}
+
+func TestExecuteCommandSelectValuesProfileWithUnknownFile(t *testing.T) {
+	h, templateURI := setupRenderTest(t)
+
+	_, err := h.ExecuteCommand(context.Background(), &lsp.ExecuteCommandParams{
+		Command:   SelectValuesProfileCommand,
+		Arguments: []interface{}{string(templateURI), []interface{}{"values-missing.yaml"}},
+	})
+
+	assert.Error(t, err)
+	assert.Empty(t, h.helmlsConfig.ValuesFilesConfig.ActiveProfiles)
+}
+
+func TestIsInDirectory(t *testing.T) {
+	assert.True(t, isInDirectory(filepath.Join("charts", "app", "templates", "a.yaml"), filepath.Join("charts", "app")))
+	assert.False(t, isInDirectory(filepath.Join("charts", "app-two", "templates", "a.yaml"), filepath.Join("charts", "app")))
+}
@@ -2,10 +2,10 @@ package handler
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/mrjosh/helm-ls/internal/charts"
 	lsp "go.lsp.dev/protocol"
-	"go.lsp.dev/uri"
 
 	"go.lsp.dev/jsonrpc2"
 )
@@ -13,23 +13,24 @@ import (
 func (h *ServerHandler) NewChartWithWatchedFiles(chart *charts.Chart) {
 	logger.Debug("NewChartWithWatchedFiles ", chart.RootURI)
 
-	uris := make([]uri.URI, 0)
+	globPatterns := make([]string, 0)
 	for _, valuesFile := range chart.ValuesFiles.AllValuesFiles() {
-		uris = append(uris, valuesFile.URI)
+		globPatterns = append(globPatterns, valuesFile.URI.Filename())
 	}
+	globPatterns = append(globPatterns, filepath.Join(chart.RootURI.Filename(), charts.CRDsDirName, "**"))
 
-	go h.RegisterWatchedFiles(context.Background(), h.connPool, uris)
+	go h.RegisterWatchedFiles(context.Background(), h.connPool, globPatterns)
 }
 
-func (h *ServerHandler) RegisterWatchedFiles(ctx context.Context, conn jsonrpc2.Conn, files []uri.URI) {
+func (h *ServerHandler) RegisterWatchedFiles(ctx context.Context, conn jsonrpc2.Conn, globPatterns []string) {
 	if conn == nil {
 		return
 	}
 	watchers := make([]lsp.FileSystemWatcher, 0)
 
-	for _, file := range files {
+	for _, globPattern := range globPatterns {
 		watchers = append(watchers, lsp.FileSystemWatcher{
-			GlobPattern: file.Filename(),
+			GlobPattern: globPattern,
 		})
 	}
 
@@ -51,6 +52,10 @@ func (h *ServerHandler) RegisterWatchedFiles(ctx context.Context, conn jsonrpc2.
 
 func (h *ServerHandler) DidChangeWatchedFiles(ctx context.Context, params *lsp.DidChangeWatchedFilesParams) (err error) {
 	for _, change := range params.Changes {
+		if _, ok := charts.GetChartDirForCRDFile(change.URI); ok {
+			h.chartStore.ReloadCRDFile(change.URI)
+			continue
+		}
 		h.chartStore.ReloadValuesFile(change.URI)
 	}
 
@@ -8,5 +8,8 @@ import (
 
 // Completion implements handler.LangHandler.
 func (h *YamlHandler) Completion(ctx context.Context, params *protocol.CompletionParams) (result *protocol.CompletionList, err error) {
+	if result, ok, err := h.embeddedTemplateCompletion(params); ok {
+		return result, err
+	}
 	return h.yamllsConnector.CallCompletion(ctx, params)
 }
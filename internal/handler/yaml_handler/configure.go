@@ -8,6 +8,7 @@ import (
 )
 
 func (h *YamlHandler) Configure(ctx context.Context, helmlsConfig util.HelmlsConfiguration) {
+	h.helmlsConfig = helmlsConfig
 	h.configureYamlls(ctx, helmlsConfig.YamllsConfiguration)
 }
 
@@ -16,6 +17,7 @@ func (h *YamlHandler) configureYamlls(ctx context.Context, config util.YamllsCon
 	// to also run yaml-language-server separately for diagnostics
 	// and the schemas we are generating do not enforce strict validation
 	config.DiagnosticsEnabled = false
+	config.YamllsSettings = withoutSchemaAssociations(config.YamllsSettings)
 
 	connector := yamlls.NewConnector(ctx,
 		config,
@@ -25,6 +27,9 @@ func (h *YamlHandler) configureYamlls(ctx context.Context, config util.YamllsCon
 			yamlls.NewCustomSchemaProviderHandler(h.CustomSchemaProvider),
 		),
 	)
+	connector.SetRestartCallback(func() {
+		connector.InitiallySyncOpenYamlDocuments(h.documents.GetAllYamlDocs())
+	})
 
 	h.setYamllsConnector(connector)
 
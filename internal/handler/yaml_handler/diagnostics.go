@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
 	"go.lsp.dev/protocol"
 	"go.lsp.dev/uri"
 )
@@ -24,7 +25,7 @@ func (h *YamlHandler) GetDiagnostics(uri uri.URI) []protocol.PublishDiagnosticsP
 		logger.Debug("YamlHandler:  No parse error")
 		return []protocol.PublishDiagnosticsParams{{
 			URI:         uri,
-			Diagnostics: []protocol.Diagnostic{},
+			Diagnostics: diagnosticrules.RemoveDisabled(append(h.getValuesSchemaDiagnostics(doc), h.getEmbeddedTemplateDiagnostics(doc)...), h.helmlsConfig.DisabledRules),
 		}}
 	}
 
@@ -55,7 +56,7 @@ func (h *YamlHandler) GetDiagnostics(uri uri.URI) []protocol.PublishDiagnosticsP
 	return []protocol.PublishDiagnosticsParams{
 		{
 			URI: uri,
-			Diagnostics: []protocol.Diagnostic{
+			Diagnostics: diagnosticrules.RemoveDisabled([]protocol.Diagnostic{
 				{
 					Range: protocol.Range{
 						Start: protocol.Position{
@@ -67,13 +68,15 @@ func (h *YamlHandler) GetDiagnostics(uri uri.URI) []protocol.PublishDiagnosticsP
 							Character: 0,
 						},
 					},
+					Code:               diagnosticrules.ValuesSyntax.Code,
+					CodeDescription:    diagnosticrules.ValuesSyntax.CodeDescription(),
 					Source:             "Helm-ls YamlHandler",
 					Message:            matches[2],
 					Tags:               []protocol.DiagnosticTag{},
 					RelatedInformation: []protocol.DiagnosticRelatedInformation{},
 					Data:               nil,
 				},
-			},
+			}, h.helmlsConfig.DisabledRules),
 		},
 	}
 }
@@ -0,0 +1,143 @@
+package yamlhandler
+
+import (
+	"fmt"
+
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	helmdocs "github.com/mrjosh/helm-ls/internal/documentation/helm"
+	languagefeatures "github.com/mrjosh/helm-ls/internal/language_features"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/protocol"
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+)
+
+// newEmbeddedTemplateUseCase returns the use case for a position inside of a template action
+// in a string value of a values file, false if the position is not inside of a template action
+func (h *YamlHandler) newEmbeddedTemplateUseCase(
+	params lsp.TextDocumentPositionParams,
+	nodeSelection func(ast *sitter.Tree, position lsp.Position) (node *sitter.Node),
+) (*languagefeatures.GenericDocumentUseCase, bool) {
+	doc, ok := h.documents.GetYamlDoc(params.TextDocument.URI)
+	if !ok || doc.EmbeddedTemplates == nil {
+		return nil, false
+	}
+	node := nodeSelection(doc.EmbeddedTemplates.Ast, params.Position)
+	if node == nil || node.Type() == gotemplate.NodeTypeText || node.Type() == gotemplate.NodeTypeTemplate {
+		return nil, false
+	}
+
+	chart, err := h.chartStore.GetChartForDoc(params.TextDocument.URI)
+	if err != nil {
+		logger.Error("Error getting chart info for file", params.TextDocument.URI, err)
+	}
+	parentNodeType := ""
+	if node.Parent() != nil {
+		parentNodeType = node.Parent().Type()
+	}
+	return &languagefeatures.GenericDocumentUseCase{
+		Document:       doc.EmbeddedTemplates,
+		DocumentStore:  h.documents,
+		Chart:          chart,
+		ChartStore:     h.chartStore,
+		Node:           node,
+		NodeType:       node.Type(),
+		ParentNode:     node.Parent(),
+		ParentNodeType: parentNodeType,
+		RenderConfig:   h.helmlsConfig.RenderConfig,
+	}, true
+}
+
+// embeddedTemplateHover returns the hover for templates in string values
+func (h *YamlHandler) embeddedTemplateHover(params *lsp.HoverParams) (*lsp.Hover, bool, error) {
+	genericDocumentUseCase, ok := h.newEmbeddedTemplateUseCase(params.TextDocumentPositionParams, templateast.NodeAtPosition)
+	if !ok {
+		return nil, false, nil
+	}
+
+	usecases := []languagefeatures.HoverUseCase{
+		languagefeatures.NewBuiltInObjectsFeature(genericDocumentUseCase), // has to be before template context
+		languagefeatures.NewTemplateContextFeature(genericDocumentUseCase),
+		languagefeatures.NewIncludesCallFeature(genericDocumentUseCase),
+		languagefeatures.NewFunctionCallFeature(genericDocumentUseCase),
+		languagefeatures.NewVariablesFeature(genericDocumentUseCase),
+	}
+	for _, usecase := range usecases {
+		if usecase.AppropriateForNode() {
+			result, err := usecase.Hover()
+			return protocol.BuildHoverResponse(result, templateast.GetLspRangeForNode(genericDocumentUseCase.Node)), true, err
+		}
+	}
+	return nil, true, nil
+}
+
+// embeddedTemplateCompletion returns the completion for templates in string values
+func (h *YamlHandler) embeddedTemplateCompletion(params *lsp.CompletionParams) (*lsp.CompletionList, bool, error) {
+	genericDocumentUseCase, ok := h.newEmbeddedTemplateUseCase(params.TextDocumentPositionParams, templateast.NestedNodeAtPositionForCompletion)
+	if !ok {
+		return nil, false, nil
+	}
+
+	usecases := []languagefeatures.CompletionUseCase{
+		languagefeatures.NewTemplateContextFeature(genericDocumentUseCase),
+		languagefeatures.NewFunctionCallFeature(genericDocumentUseCase),
+		languagefeatures.NewIncludesCallFeature(genericDocumentUseCase),
+		languagefeatures.NewVariablesFeature(genericDocumentUseCase),
+	}
+	for _, usecase := range usecases {
+		if usecase.AppropriateForNode() {
+			result, err := usecase.Completion()
+			return result, true, err
+		}
+	}
+
+	items := []helmdocs.HelmDocumentation{}
+	for _, v := range helmdocs.BuiltInObjects {
+		v.Name = "." + v.Name
+		items = append(items, v)
+	}
+	return protocol.CompletionResults{}.
+		WithDocs(items, lsp.CompletionItemKindConstant).
+		WithDocs(helmdocs.AllFuncs, lsp.CompletionItemKindFunction).ToList(), true, nil
+}
+
+// getEmbeddedTemplateDiagnostics returns the syntax errors of templates in string values
+func (h *YamlHandler) getEmbeddedTemplateDiagnostics(doc *document.YamlDocument) []lsp.Diagnostic {
+	diagnostics := []lsp.Diagnostic{}
+	if doc.EmbeddedTemplates == nil {
+		return diagnostics
+	}
+
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if node.IsMissing() {
+			diagnostics = append(diagnostics, embeddedTemplateDiagnostic(node, fmt.Sprintf("Template: missing %s", node.Type())))
+			return
+		}
+		if node.IsError() {
+			diagnostics = append(diagnostics, embeddedTemplateDiagnostic(node, "Template: syntax error"))
+			return
+		}
+		if !node.HasError() {
+			return
+		}
+		for i := 0; i < int(node.ChildCount()); i++ {
+			walk(node.Child(i))
+		}
+	}
+	walk(doc.EmbeddedTemplates.Ast.RootNode())
+	return diagnostics
+}
+
+func embeddedTemplateDiagnostic(node *sitter.Node, message string) lsp.Diagnostic {
+	return lsp.Diagnostic{
+		Range:           templateast.GetLspRangeForNode(node),
+		Severity:        lsp.DiagnosticSeverityError,
+		Code:            diagnosticrules.ValuesEmbeddedTemplate.Code,
+		CodeDescription: diagnosticrules.ValuesEmbeddedTemplate.CodeDescription(),
+		Source:          "Helm-ls YamlHandler",
+		Message:         message,
+	}
+}
@@ -0,0 +1,96 @@
+package yamlhandler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+const embeddedTemplatesValues = `name: test
+annotation: '{{ .Release.Name }}-{{ .Values.name }}'
+completion: '{{ .Values. }}'
+`
+
+func setupEmbeddedTemplatesTest(t *testing.T, content string) (*YamlHandler, uri.URI) {
+	t.Helper()
+	tempDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	valuesFile := filepath.Join(tempDir, "values.yaml")
+	assert.NoError(t, os.WriteFile(valuesFile, []byte(content), 0o644))
+
+	h := &YamlHandler{
+		documents:       document.NewDocumentStore(),
+		chartStore:      charts.NewChartStore(uri.File(tempDir), charts.NewChart, func(chart *charts.Chart) {}),
+		yamllsConnector: &yamlls.Connector{},
+	}
+	_, err := h.documents.DidOpenYamlDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri.File(valuesFile), Text: content},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+	return h, uri.File(valuesFile)
+}
+
+func TestHoverInEmbeddedTemplates(t *testing.T) {
+	h, valuesURI := setupEmbeddedTemplatesTest(t, embeddedTemplatesValues)
+
+	testCases := []struct {
+		desc     string
+		position lsp.Position
+		expected string
+	}{
+		{"release", lsp.Position{Line: 1, Character: 25}, "Name of the release"},
+		{"values", lsp.Position{Line: 1, Character: 47}, "### values.yaml\n```yaml\ntest\n```\n"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result, err := h.Hover(context.Background(), &lsp.HoverParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: valuesURI},
+					Position:     tt.position,
+				},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Contents.Value)
+		})
+	}
+}
+
+func TestCompletionInEmbeddedTemplates(t *testing.T) {
+	h, valuesURI := setupEmbeddedTemplatesTest(t, embeddedTemplatesValues)
+
+	result, err := h.Completion(context.Background(), &lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: valuesURI},
+			Position:     lsp.Position{Line: 2, Character: 24},
+		},
+	})
+
+	assert.NoError(t, err)
+	labels := []string{}
+	for _, item := range result.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.ElementsMatch(t, []string{"name", "annotation", "completion"}, labels)
+}
+
+func TestDiagnosticsForEmbeddedTemplates(t *testing.T) {
+	content := "name: test\nannotation: '{{ .Release.Name '\n"
+	h, valuesURI := setupEmbeddedTemplatesTest(t, content)
+
+	params := h.GetDiagnostics(valuesURI)
+
+	assert.Len(t, params, 1)
+	assert.Len(t, params[0].Diagnostics, 1)
+	assert.Equal(t, "Template: missing }}", params[0].Diagnostics[0].Message)
+	assert.Equal(t, uint32(1), params[0].Diagnostics[0].Range.Start.Line)
+}
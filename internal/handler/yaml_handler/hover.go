@@ -9,6 +9,9 @@ import (
 // Hover implements handler.LangHandler.
 func (h *YamlHandler) Hover(ctx context.Context, params *lsp.HoverParams) (result *lsp.Hover, err error) {
 	logger.Debug("YamlHandler Hover", params)
+	if result, ok, err := h.embeddedTemplateHover(params); ok {
+		return result, err
+	}
 	return h.yamllsConnector.CallHover(ctx, *params)
 	// IDEA: return the json path of the current node
 	// doc, ok := h.documents.GetYamlDoc(params.TextDocument.URI)
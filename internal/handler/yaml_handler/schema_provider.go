@@ -2,8 +2,10 @@ package yamlhandler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/mrjosh/helm-ls/internal/jsonschema"
 	"go.lsp.dev/uri"
 )
 
@@ -24,5 +26,30 @@ func (h *YamlHandler) CustomSchemaProvider(ctx context.Context, URI uri.URI) (ur
 		logger.Error(err)
 		return uri.New(""), err
 	}
-	return uri.File(schemaFilePath), nil
+
+	userSchemas := jsonschema.GetUserSchemasForFile(h.helmlsConfig.YamllsConfiguration.YamllsSettings, h.chartStore.RootURI, URI)
+	combinedSchemaFilePath, err := h.jsonSchemas.GetCombinedJSONSchema(chart, userSchemas, schemaFilePath)
+	if err != nil {
+		logger.Error(err)
+		return uri.File(schemaFilePath), nil
+	}
+	return uri.File(combinedSchemaFilePath), nil
+}
+
+// withoutSchemaAssociations removes the schemas from the yamlls settings,
+// they are resolved by the CustomSchemaProvider and would conflict with the schema returned by it
+func withoutSchemaAssociations(yamllsSettings any) any {
+	bytes, err := json.Marshal(yamllsSettings)
+	if err != nil {
+		logger.Error("Failed to read yamlls settings", err)
+		return yamllsSettings
+	}
+	settings := map[string]any{}
+	if err := json.Unmarshal(bytes, &settings); err != nil {
+		logger.Error("Failed to read yamlls settings", err)
+		return yamllsSettings
+	}
+
+	settings["schemas"] = map[string]any{}
+	return settings
 }
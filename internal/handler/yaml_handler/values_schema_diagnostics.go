@@ -0,0 +1,114 @@
+package yamlhandler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	"github.com/mrjosh/helm-ls/internal/jsonschema"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"go.lsp.dev/protocol"
+	"gopkg.in/yaml.v3"
+)
+
+// getValuesSchemaDiagnostics validates a values file against the values.schema.json of its chart.
+// Additional values files are validated on top of the main values file, like helm does it,
+// but only the errors that can be located in the additional values file are reported.
+func (h *YamlHandler) getValuesSchemaDiagnostics(doc *document.YamlDocument) []protocol.Diagnostic {
+	diagnostics := []protocol.Diagnostic{}
+	if h.chartStore == nil {
+		return diagnostics
+	}
+	chart, err := h.chartStore.GetChartForDoc(doc.URI)
+	if err != nil || chart.HelmChart == nil || chart.HelmChart.Schema == nil || chart.ValuesFiles == nil {
+		return diagnostics
+	}
+
+	isMainValuesFile := chart.ValuesFiles.MainValuesFile != nil && chart.ValuesFiles.MainValuesFile.URI == doc.URI
+	values := doc.ParsedYaml
+	if !isMainValuesFile {
+		if !isValuesFileOfChart(chart, doc) {
+			return diagnostics
+		}
+		values = charts.CoalesceValuesFiles(chart.ValuesFiles.MainValuesFile, &charts.ValuesFile{Values: doc.ParsedYaml})
+	}
+
+	schemaErrors, err := jsonschema.ValidateValues(chart.HelmChart.Schema, values)
+	if err != nil {
+		logger.Error("YamlHandler: Error validating values against the values schema", err)
+		return diagnostics
+	}
+
+	for _, schemaError := range schemaErrors {
+		errorRange, found := getRangeForSchemaError(&doc.Node, schemaError)
+		if !found && !isMainValuesFile {
+			continue
+		}
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:           errorRange,
+			Severity:        protocol.DiagnosticSeverityError,
+			Code:            diagnosticrules.ValuesSchema.Code,
+			CodeDescription: diagnosticrules.ValuesSchema.CodeDescription(),
+			Source:          "Helm-ls YamlHandler",
+			Message:         fmt.Sprintf("%s: %s", jsonschema.ValuesSchemaFileName, schemaError.Message),
+		})
+	}
+	return diagnostics
+}
+
+func isValuesFileOfChart(chart *charts.Chart, doc *document.YamlDocument) bool {
+	for _, valuesFile := range chart.ValuesFiles.AllValuesFiles() {
+		if valuesFile.URI == doc.URI {
+			return true
+		}
+	}
+	return false
+}
+
+// getRangeForSchemaError returns the range of the value that caused the error.
+// Returns false if the value is not part of the document, the range is set to the first line in that case.
+func getRangeForSchemaError(root *yaml.Node, schemaError jsonschema.ValuesSchemaError) (protocol.Range, bool) {
+	firstLine := protocol.Range{End: protocol.Position{Line: 1}}
+
+	path := schemaError.Path
+	// errors about properties (e.g. additional properties) are reported at the key of the property,
+	// missing required properties are reported at the key of the parent object
+	isKeyError := schemaError.Type == "required"
+	if schemaError.Property != "" && schemaError.Type != "required" {
+		path = append(path[:len(path):len(path)], schemaError.Property)
+		isKeyError = true
+	}
+	if len(path) == 0 {
+		return firstLine, false
+	}
+
+	key, value := util.GetYamlNodesForPath(root, path)
+	if value == nil {
+		return firstLine, false
+	}
+	if key != nil && (isKeyError || !isSingleLineScalar(value) || value.Value == "") {
+		return getRangeOfNode(key), true
+	}
+	return getRangeOfNode(value), true
+}
+
+func isSingleLineScalar(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 && !strings.Contains(node.Value, "\n")
+}
+
+func getRangeOfNode(node *yaml.Node) protocol.Range {
+	start := protocol.Position{Line: uint32(node.Line - 1), Character: uint32(node.Column - 1)}
+	length := 1
+	if isSingleLineScalar(node) {
+		length = len(node.Value)
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+			length += 2
+		}
+	}
+	return protocol.Range{
+		Start: start,
+		End:   protocol.Position{Line: start.Line, Character: start.Character + uint32(length)},
+	}
+}
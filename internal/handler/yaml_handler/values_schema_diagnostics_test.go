@@ -0,0 +1,121 @@
+package yamlhandler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+const testValuesSchema = `{
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "image": {
+      "type": "object",
+      "required": ["repository"],
+      "properties": {
+        "repository": {"type": "string"},
+        "pullPolicy": {"type": "string", "enum": ["Always", "IfNotPresent"]}
+      }
+    },
+    "replicas": {"type": "integer"},
+    "labels": {"type": "object", "additionalProperties": false, "properties": {"app": {"type": "string"}}}
+  }
+}`
+
+func setupValuesSchemaTest(t *testing.T, valuesContent string, prodValuesContent string) (*YamlHandler, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(valuesContent), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values-prod.yaml"), []byte(prodValuesContent), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.schema.json"), []byte(testValuesSchema), 0o644))
+
+	h := &YamlHandler{
+		documents:  document.NewDocumentStore(),
+		chartStore: charts.NewChartStore(uri.File(tempDir), charts.NewChart, func(chart *charts.Chart) {}),
+	}
+	return h, tempDir
+}
+
+func getDiagnosticsForFile(t *testing.T, h *YamlHandler, file string, content string) []protocol.Diagnostic {
+	t.Helper()
+	_, err := h.documents.DidOpenYamlDocument(&protocol.DidOpenTextDocumentParams{
+		TextDocument: protocol.TextDocumentItem{URI: uri.File(file), Text: content},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+
+	params := h.GetDiagnostics(uri.File(file))
+	assert.Len(t, params, 1)
+	return params[0].Diagnostics
+}
+
+func TestValuesSchemaDiagnostics(t *testing.T) {
+	values := "image:\n  repository: nginx\n  pullPolicy: Never\nreplicas: \"three\"\nlabels:\n  app: test\n  other: test\n"
+	h, tempDir := setupValuesSchemaTest(t, values, "")
+
+	diagnostics := getDiagnosticsForFile(t, h, filepath.Join(tempDir, "values.yaml"), values)
+
+	ranges := map[string]protocol.Range{}
+	for _, diagnostic := range diagnostics {
+		assert.Equal(t, protocol.DiagnosticSeverityError, diagnostic.Severity)
+		ranges[diagnostic.Message] = diagnostic.Range
+	}
+	assert.Equal(t, map[string]protocol.Range{
+		`values.schema.json: image.pullPolicy must be one of the following: "Always", "IfNotPresent"`: {
+			Start: protocol.Position{Line: 2, Character: 14},
+			End:   protocol.Position{Line: 2, Character: 19},
+		},
+		"values.schema.json: Invalid type. Expected: integer, given: string": {
+			Start: protocol.Position{Line: 3, Character: 10},
+			End:   protocol.Position{Line: 3, Character: 17},
+		},
+		"values.schema.json: Additional property other is not allowed": {
+			Start: protocol.Position{Line: 6, Character: 2},
+			End:   protocol.Position{Line: 6, Character: 7},
+		},
+	}, ranges)
+}
+
+func TestValuesSchemaDiagnosticsRequired(t *testing.T) {
+	values := "image:\n  pullPolicy: Always\n"
+	h, tempDir := setupValuesSchemaTest(t, values, "")
+
+	diagnostics := getDiagnosticsForFile(t, h, filepath.Join(tempDir, "values.yaml"), values)
+
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, "values.schema.json: repository is required", diagnostics[0].Message)
+	assert.Equal(t, protocol.Range{
+		Start: protocol.Position{Line: 0, Character: 0},
+		End:   protocol.Position{Line: 0, Character: 5},
+	}, diagnostics[0].Range)
+}
+
+func TestValuesSchemaDiagnosticsAdditionalValuesFile(t *testing.T) {
+	// the main values file contains an error, which is not reported for the additional values file
+	values := "image:\n  repository: nginx\nreplicas: one\n"
+	prodValues := "image:\n  pullPolicy: Never\n"
+	h, tempDir := setupValuesSchemaTest(t, values, prodValues)
+
+	diagnostics := getDiagnosticsForFile(t, h, filepath.Join(tempDir, "values-prod.yaml"), prodValues)
+
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, `values.schema.json: image.pullPolicy must be one of the following: "Always", "IfNotPresent"`, diagnostics[0].Message)
+	assert.Equal(t, uint32(1), diagnostics[0].Range.Start.Line)
+}
+
+func TestValuesSchemaDiagnosticsWithoutSchemaErrors(t *testing.T) {
+	values := "image:\n  repository: nginx\nreplicas: 1\n"
+	h, tempDir := setupValuesSchemaTest(t, values, "")
+
+	diagnostics := getDiagnosticsForFile(t, h, filepath.Join(tempDir, "values.yaml"), values)
+
+	assert.Empty(t, diagnostics)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/mrjosh/helm-ls/internal/jsonschema"
 	"github.com/mrjosh/helm-ls/internal/log"
 	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
 	"go.lsp.dev/protocol"
 )
 
@@ -20,6 +21,7 @@ type YamlHandler struct {
 	client          protocol.Client
 	yamllsConnector *yamlls.Connector
 	jsonSchemas     *jsonschema.JSONSchemaCache
+	helmlsConfig    util.HelmlsConfiguration
 }
 
 // SetClient implements handler.LangHandler.
@@ -42,6 +44,11 @@ func NewYamlHandler(client protocol.Client, documents *document.DocumentStore, c
 	}
 }
 
+// Stop implements handler.LangHandler.
+func (h *YamlHandler) Stop() {
+	h.yamllsConnector.Stop()
+}
+
 func (h *YamlHandler) setYamllsConnector(yamllsConnector *yamlls.Connector) {
 	h.yamllsConnector = yamllsConnector
 }
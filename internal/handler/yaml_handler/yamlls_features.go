@@ -0,0 +1,32 @@
+package yamlhandler
+
+import (
+	"context"
+
+	lsp "go.lsp.dev/protocol"
+)
+
+// CodeAction implements handler.LangHandler.
+func (h *YamlHandler) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (result []lsp.CodeAction, err error) {
+	return h.yamllsConnector.CallCodeAction(ctx, params)
+}
+
+// FoldingRanges implements handler.LangHandler.
+func (h *YamlHandler) FoldingRanges(ctx context.Context, params *lsp.FoldingRangeParams) (result []lsp.FoldingRange, err error) {
+	return h.yamllsConnector.CallFoldingRanges(ctx, params)
+}
+
+// SelectionRange implements handler.LangHandler.
+func (h *YamlHandler) SelectionRange(ctx context.Context, params *lsp.SelectionRangeParams) (result []lsp.SelectionRange, err error) {
+	return h.yamllsConnector.CallSelectionRange(ctx, params)
+}
+
+// DocumentLink implements handler.LangHandler.
+func (h *YamlHandler) DocumentLink(ctx context.Context, params *lsp.DocumentLinkParams) (result []lsp.DocumentLink, err error) {
+	return h.yamllsConnector.CallDocumentLink(ctx, params)
+}
+
+// Formatting implements handler.LangHandler.
+func (h *YamlHandler) Formatting(ctx context.Context, params *lsp.DocumentFormattingParams) (result []lsp.TextEdit, err error) {
+	return h.yamllsConnector.CallFormatting(ctx, params)
+}
@@ -2,13 +2,17 @@ package helmlint
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/mrjosh/helm-ls/internal/charts"
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
 	"github.com/mrjosh/helm-ls/internal/log"
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
 	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
 	"github.com/mrjosh/helm-ls/internal/util"
 	lsp "go.lsp.dev/protocol"
 	"go.lsp.dev/uri"
@@ -19,25 +23,23 @@ import (
 
 var logger = log.GetLogger()
 
-func GetDiagnosticsNotifications(chart *charts.Chart, doc *document.TemplateDocument) []lsp.PublishDiagnosticsParams {
-	vals := chart.ValuesFiles.MainValuesFile.Values
-	if chart.ValuesFiles.OverlayValuesFile != nil {
-		vals = chartutil.CoalesceTables(chart.ValuesFiles.OverlayValuesFile.Values, chart.ValuesFiles.MainValuesFile.Values)
-	}
-
-	diagnostics := GetDiagnostics(chart.RootURI, vals)
+func GetDiagnosticsNotifications(chart *charts.Chart, doc *document.TemplateDocument, config util.HelmlsConfiguration) []lsp.PublishDiagnosticsParams {
+	diagnostics := GetDiagnostics(chart.RootURI, chart.ValuesFiles.GetLintValues(), config.RenderConfig)
 
 	// Update the diagnostics cache only for the currently opened document
 	// as it will also get diagnostics from yamlls
 	// if currentDocDiagnostics is empty it means that all issues in that file have been fixed
 	// we need to send this to the client
 	currentDocDiagnostics := diagnostics[string(doc.URI.Filename())]
-	doc.DiagnosticsCache.HelmDiagnostics = currentDocDiagnostics
+	doc.DiagnosticsCache.SetHelmDiagnostics(currentDocDiagnostics)
 	diagnostics[string(doc.URI.Filename())] = doc.DiagnosticsCache.GetMergedDiagnostics()
 
 	result := []lsp.PublishDiagnosticsParams{}
 
 	for diagnosticsURI, diagnostics := range diagnostics {
+		if diagnosticsURI != doc.URI.Filename() {
+			diagnostics = SuppressDiagnostics(diagnosticsURI, diagnostics, config.DisabledRules)
+		}
 		result = append(result,
 			lsp.PublishDiagnosticsParams{
 				URI:         uri.File(diagnosticsURI),
@@ -50,11 +52,13 @@ func GetDiagnosticsNotifications(chart *charts.Chart, doc *document.TemplateDocu
 }
 
 // GetDiagnostics will run helm linter against the chart root URI using the given values
-// and converts the helm.support.Message to lsp.Diagnostics
-func GetDiagnostics(rootURI uri.URI, vals chartutil.Values) map[string][]lsp.Diagnostic {
+// and converts the helm.support.Message to lsp.Diagnostics.
+// The linter of helm only supports the namespace and the kubeVersion of the config, it renders the templates
+// with its own release name and without the additional API versions and the upgrade flag.
+func GetDiagnostics(rootURI uri.URI, vals chartutil.Values, config util.RenderConfig) map[string][]lsp.Diagnostic {
 	diagnostics := map[string][]lsp.Diagnostic{}
 
-	client := action.NewLint()
+	client := newLintClient(config)
 
 	result := client.Run([]string{rootURI.Filename()}, vals)
 
@@ -70,6 +74,34 @@ func GetDiagnostics(rootURI uri.URI, vals chartutil.Values) map[string][]lsp.Dia
 	return diagnostics
 }
 
+// SuppressDiagnostics removes the diagnostics of the disabled rules and the ones that are disabled by suppression comments
+// in the file, the diagnostics of the open documents are suppressed by their DiagnosticsCache
+func SuppressDiagnostics(filePath string, diagnostics []lsp.Diagnostic, disabledRules []string) []lsp.Diagnostic {
+	diagnostics = diagnosticrules.RemoveDisabled(diagnostics, disabledRules)
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Debug("Could not read file to suppress diagnostics", filePath, err)
+		return diagnostics
+	}
+	suppressions := lsplocal.GetSuppressions(templateast.ParseAst(nil, content).RootNode(), content)
+	return lsplocal.ApplySuppressions(diagnostics, suppressions, make([]bool, len(suppressions)))
+}
+
+func newLintClient(config util.RenderConfig) *action.Lint {
+	client := action.NewLint()
+	client.Namespace = config.Namespace
+	if config.KubeVersion == "" {
+		return client
+	}
+	kubeVersion, err := chartutil.ParseKubeVersion(config.KubeVersion)
+	if err != nil {
+		logger.Error("Invalid kubeVersion, using the default of helm", config.KubeVersion, err)
+		return client
+	}
+	client.KubeVersion = kubeVersion
+	return client
+}
+
 func GetDiagnosticFromLinterErr(supMsg support.Message) (*lsp.Diagnostic, string, error) {
 	severity := parseSeverity(supMsg)
 
@@ -87,9 +119,11 @@ func GetDiagnosticFromLinterErr(supMsg support.Message) (*lsp.Diagnostic, string
 	// because the lsp is not active for that file
 	if supMsg.Path == "Chart.yaml" || strings.Contains(message, "chart metadata") {
 		return &lsp.Diagnostic{
-			Severity: severity,
-			Source:   "Helm lint",
-			Message:  message,
+			Severity:        severity,
+			Code:            diagnosticrules.HelmLintChart.Code,
+			CodeDescription: diagnosticrules.HelmLintChart.CodeDescription(),
+			Source:          "Helm lint",
+			Message:         message,
 		}, "Chart.yaml", nil
 	}
 
@@ -114,14 +148,21 @@ func parseTemplatesMessage(supMsg support.Message, severity lsp.DiagnosticSeveri
 	msgStr := util.AfterStrings(supMsg.Error(), "):")
 	msg := strings.TrimSpace(msgStr)
 
+	rule := diagnosticrules.HelmLintTemplateWarning
+	if severity == lsp.DiagnosticSeverityError {
+		rule = diagnosticrules.HelmLintTemplateError
+	}
+
 	return lsp.Diagnostic{
 		Range: lsp.Range{
 			Start: lsp.Position{Line: uint32(line - 1)},
 			End:   lsp.Position{Line: uint32(line - 1)},
 		},
-		Severity: severity,
-		Source:   "Helm lint",
-		Message:  msg,
+		Severity:        severity,
+		Code:            rule.Code,
+		CodeDescription: rule.CodeDescription(),
+		Source:          "Helm lint",
+		Message:         msg,
 	}, nil
 }
 
@@ -1,17 +1,22 @@
 package helmlint
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/mrjosh/helm-ls/internal/charts"
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
 	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
 	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
 	"go.lsp.dev/uri"
 	"helm.sh/helm/v3/pkg/chartutil"
 )
 
 func TestLint(t *testing.T) {
-	diagnostics := GetDiagnostics(uri.File("../../testdata/example"), chartutil.Values{})
+	diagnostics := GetDiagnostics(uri.File("../../testdata/example"), chartutil.Values{}, util.DefaultConfig.RenderConfig)
 	assert.NotEmpty(t, diagnostics)
 	assert.Len(t, diagnostics, 2)
 	assert.Len(t, diagnostics[uri.File("../../testdata/example/Chart.yaml").Filename()], 1)
@@ -30,7 +35,7 @@ func TestLintNotifications(t *testing.T) {
 		Document: document.Document{
 			URI: uri.File("../../testdata/example/templates/deployment-no-templates.yaml"),
 		},
-	})
+	}, util.DefaultConfig)
 	assert.NotEmpty(t, diagnostics)
 	assert.Len(t, diagnostics, 3)
 
@@ -57,7 +62,7 @@ func TestLintNotificationsIncludesEmptyDiagnosticsForFixedIssues(t *testing.T) {
 	}
 	diagnostics := GetDiagnosticsNotifications(&chart, &document.TemplateDocument{
 		Document: document.Document{URI: uri.File("../../testdata/example/templates/deployment-no-templates.yaml")},
-	},
+	}, util.DefaultConfig,
 	)
 
 	uris := []string{}
@@ -71,3 +76,35 @@ func TestLintNotificationsIncludesEmptyDiagnosticsForFixedIssues(t *testing.T) {
 		}
 	}
 }
+
+func TestNewLintClientUsesConfig(t *testing.T) {
+	client := newLintClient(util.DefaultConfig.RenderConfig)
+	assert.Equal(t, "default", client.Namespace)
+	assert.Nil(t, client.KubeVersion)
+
+	config := util.DefaultConfig.RenderConfig
+	config.Namespace = "test"
+	config.KubeVersion = "v1.30.0"
+	client = newLintClient(config)
+	assert.Equal(t, "test", client.Namespace)
+	assert.Equal(t, "1", client.KubeVersion.Major)
+	assert.Equal(t, "30", client.KubeVersion.Minor)
+
+	config.KubeVersion = "invalid"
+	assert.Nil(t, newLintClient(config).KubeVersion)
+}
+
+func TestSuppressDiagnostics(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "deployment.yaml")
+	content := "{{/* helm-ls:disable-next-line helm-lint */}}\na: {{ .Values.a }}\nb: {{ .Values.b }}\n"
+	assert.NoError(t, os.WriteFile(filePath, []byte(content), 0o644))
+	diagnostics := []lsp.Diagnostic{
+		{Range: lsp.Range{Start: lsp.Position{Line: 1}}, Code: diagnosticrules.HelmLintTemplateError.Code, Message: "a"},
+		{Range: lsp.Range{Start: lsp.Position{Line: 2}}, Code: diagnosticrules.HelmLintTemplateError.Code, Message: "b"},
+	}
+
+	result := SuppressDiagnostics(filePath, diagnostics, nil)
+
+	assert.Equal(t, diagnostics[1:], result)
+	assert.Equal(t, diagnostics, SuppressDiagnostics(filepath.Join(t.TempDir(), "missing.yaml"), diagnostics, nil))
+}
@@ -0,0 +1,191 @@
+package helmrender
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	"github.com/mrjosh/helm-ls/internal/util"
+	sitter "github.com/smacker/go-tree-sitter"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// notExecutedMarker is rendered if a with or range block surrounding the action is not executed
+const notExecutedMarker = "\x00helm-ls-not-executed\x00"
+
+// actionTemplateName is the name of the template that is used to evaluate actions of partials
+const actionTemplateName = "helm-ls-action.yaml"
+
+var (
+	blockNodeTypes = []string{
+		gotemplate.NodeTypeTemplate,
+		gotemplate.NodeTypeIfAction,
+		gotemplate.NodeTypeWithAction,
+		gotemplate.NodeTypeRangeAction,
+		gotemplate.NodeTypeDefineAction,
+		gotemplate.NodeTypeBlockAction,
+	}
+	bodyFieldNames = []string{
+		"consequence",
+		"body",
+		gotemplate.FieldNameOption,
+		gotemplate.FieldNameAlternative,
+	}
+	noOutputNodeTypes = []string{
+		gotemplate.NodeTypeText,
+		gotemplate.NodeTypeComment,
+		gotemplate.NodeTypeVariableDefinition,
+		gotemplate.NodeTypeAssignment,
+		gotemplate.NodeTypeError,
+	}
+
+	ErrNotExecuted = errors.New("the action is not executed with the current values, because a surrounding with or range block is skipped")
+)
+
+// GetActionForNode returns the action (e.g. {{ .Values.foo | toYaml }}) containing the node
+// or nil if the node is not part of an action that produces output
+func GetActionForNode(node *sitter.Node) *sitter.Node {
+	action := node
+	for action != nil && action.Parent() != nil && !slices.Contains(blockNodeTypes, action.Parent().Type()) {
+		action = action.Parent()
+	}
+	if action == nil || action.Parent() == nil {
+		return nil
+	}
+	if slices.Contains(blockNodeTypes, action.Type()) || slices.Contains(noOutputNodeTypes, action.Type()) {
+		return nil
+	}
+	if action.Parent().Type() != gotemplate.NodeTypeTemplate && !slices.Contains(bodyFieldNames, fieldNameOfChild(action.Parent(), action)) {
+		return nil
+	}
+	return action
+}
+
+// buildActionTemplate creates a template that only renders the given action.
+// Variable definitions as well as with and range blocks surrounding the action
+// are included so that variables and the dot resolve to the same values as in the
+// original template. Range blocks are only evaluated for their first element.
+func buildActionTemplate(action *sitter.Node, content []byte) string {
+	path := []*sitter.Node{action}
+	for node := action; node.Parent() != nil; node = node.Parent() {
+		path = append(path, node.Parent())
+		// the dot of a define is unknown, the root context is used instead
+		if node.Parent().Type() == gotemplate.NodeTypeDefineAction || node.Parent().Type() == gotemplate.NodeTypeBlockAction {
+			break
+		}
+	}
+
+	prefix, suffix := strings.Builder{}, []string{}
+	for i := len(path) - 1; i > 0; i-- {
+		block, child := path[i], path[i-1]
+		prefix.WriteString(variableDefinitionsBefore(block, child, content))
+
+		if i-1 == 0 {
+			break
+		}
+		grandChild := path[i-2]
+		switch child.Type() {
+		case gotemplate.NodeTypeWithAction:
+			if fieldNameOfChild(child, grandChild) != "consequence" {
+				continue
+			}
+			prefix.WriteString(blockHeader(child, content))
+			suffix = append(suffix, fmt.Sprintf("{{ else }}%s{{ end }}", notExecutedMarker))
+		case gotemplate.NodeTypeRangeAction:
+			if fieldNameOfChild(child, grandChild) != "body" {
+				continue
+			}
+			prefix.WriteString(blockHeader(child, content))
+			suffix = append(suffix, fmt.Sprintf("{{ break }}{{ else }}%s{{ end }}", notExecutedMarker))
+		}
+	}
+
+	result := prefix.String() + "{{ " + action.Content(content) + " }}"
+	for i := len(suffix) - 1; i >= 0; i-- {
+		result += suffix[i]
+	}
+	return result
+}
+
+// RenderAction evaluates a single action of a template with the helm engine.
+// The partials (content by path) replace the partials that were loaded with the chart,
+// so unsaved changes of defines are rendered as well.
+func RenderAction(c *charts.Chart, templatePath string, content []byte, action *sitter.Node, partials map[string][]byte,
+	vals chartutil.Values, config util.RenderConfig,
+) (string, error) {
+	templateName, err := getTemplateName(c, templatePath)
+	if err != nil {
+		return "", err
+	}
+	actionTemplate := []byte(buildActionTemplate(action, content))
+
+	templates := map[string][]byte{}
+	for partialPath, partialContent := range partials {
+		// partials of dependencies are part of the dependency charts, they are rendered as loaded
+		if partialName, err := getTemplateName(c, partialPath); err == nil && isPartial(partialName) && !strings.HasPrefix(partialName, "charts/") {
+			templates[partialName] = partialContent
+		}
+	}
+
+	templates[templateName] = actionTemplate
+	renderedTemplate := templateName
+	if isPartial(templateName) {
+		// partials are not rendered by helm, the action is rendered in an additional template
+		// which has access to the defines of the partial
+		renderedTemplate = path.Join(path.Dir(templateName), actionTemplateName)
+		templates[templateName] = content
+		templates[renderedTemplate] = actionTemplate
+	}
+
+	result, err := renderTemplates(c.HelmChart, templates, renderedTemplate, vals, config)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(result, notExecutedMarker) {
+		return "", ErrNotExecuted
+	}
+	return result, nil
+}
+
+func variableDefinitionsBefore(block *sitter.Node, child *sitter.Node, content []byte) string {
+	result := ""
+	for i := 0; i < int(block.NamedChildCount()); i++ {
+		sibling := block.NamedChild(i)
+		if sibling.StartByte() >= child.StartByte() {
+			break
+		}
+		// variables are scoped to the branch they are defined in
+		if fieldNameOfChild(block, sibling) != fieldNameOfChild(block, child) {
+			continue
+		}
+		if sibling.Type() == gotemplate.NodeTypeVariableDefinition || sibling.Type() == gotemplate.NodeTypeAssignment {
+			result += "{{ " + sibling.Content(content) + " }}"
+		}
+	}
+	return result
+}
+
+// blockHeader returns the first action of a block, e.g. {{ range $i, $e := .Values.list }}
+func blockHeader(block *sitter.Node, content []byte) string {
+	for i := 0; i < int(block.ChildCount()); i++ {
+		child := block.Child(i)
+		if child.Type() == gotemplate.NodeTypeCloseBraces || child.Type() == gotemplate.NodeTypeCloseBracesDash {
+			return string(content[block.StartByte():child.EndByte()])
+		}
+	}
+	return ""
+}
+
+func fieldNameOfChild(parent *sitter.Node, child *sitter.Node) string {
+	for i := 0; i < int(parent.ChildCount()); i++ {
+		candidate := parent.Child(i)
+		if candidate.StartByte() == child.StartByte() && candidate.EndByte() == child.EndByte() && candidate.Type() == child.Type() {
+			return parent.FieldNameForChild(i)
+		}
+	}
+	return ""
+}
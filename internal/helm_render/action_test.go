@@ -0,0 +1,130 @@
+package helmrender
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func TestBuildActionTemplate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		template string
+		position lsp.Position
+		expected string
+	}{
+		{
+			desc:     "simple action",
+			template: `a: {{ .Values.a | quote }}`,
+			position: lsp.Position{Line: 0, Character: 8},
+			expected: `{{ .Values.a | quote }}`,
+		},
+		{
+			desc:     "with and range blocks",
+			template: `{{ $a := 1 }}{{- with .Values.a }}{{ range $i, $e := .list }}{{ $e.name }}{{ end }}{{ end }}`,
+			position: lsp.Position{Line: 0, Character: 66},
+			expected: `{{ $a := 1 }}{{- with .Values.a }}{{ range $i, $e := .list }}{{ $e.name }}{{ break }}{{ else }}` + notExecutedMarker +
+				`{{ end }}{{ else }}` + notExecutedMarker + `{{ end }}`,
+		},
+		{
+			desc:     "variables of other branches are ignored",
+			template: `{{ if .Values.a }}{{ $a := 1 }}{{ else }}{{ $b := 2 }}{{ .Values.b }}{{ end }}`,
+			position: lsp.Position{Line: 0, Character: 58},
+			expected: `{{ $b := 2 }}{{ .Values.b }}`,
+		},
+		{
+			desc:     "else branch of with does not change the dot",
+			template: `{{ with .Values.a }}{{ .b }}{{ else }}{{ .Values.c }}{{ end }}`,
+			position: lsp.Position{Line: 0, Character: 45},
+			expected: `{{ .Values.c }}`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			content := []byte(tt.template)
+			ast := templateast.ParseAst(nil, content)
+			action := GetActionForNode(templateast.NodeAtPosition(ast, tt.position))
+			assert.NotNil(t, action)
+			assert.Equal(t, tt.expected, buildActionTemplate(action, content))
+		})
+	}
+}
+
+func TestGetActionForNodeReturnsNilForNonOutputActions(t *testing.T) {
+	content := []byte(`text {{ $a := .Values.a }}{{ if .Values.b }}{{ end }}`)
+	ast := templateast.ParseAst(nil, content)
+
+	for _, position := range []lsp.Position{
+		{Line: 0, Character: 1},  // text
+		{Line: 0, Character: 18}, // variable definition
+		{Line: 0, Character: 38}, // if condition
+	} {
+		assert.Nil(t, GetActionForNode(templateast.NodeAtPosition(ast, position)), position)
+	}
+}
+
+// setTestValues sets the values of the chart with overrides, which changes the version of its values
+func setTestValues(chart *charts.Chart, values ...string) {
+	chart.ValuesFiles.SetOverrides(chart.RootURI, charts.NewValuesOverrides(util.ValuesFilesConfig{SetValues: values}))
+}
+
+func TestActionCache(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "_helpers.tpl")
+	content := []byte(`{{ define "test" }}{{ .Values.a }}{{ end }}`)
+	ast := templateast.ParseAst(nil, content)
+	action := GetActionForNode(templateast.NodeAtPosition(ast, lsp.Position{Line: 0, Character: 30}))
+	noPartials := func() map[string][]byte { return nil }
+
+	cache := NewActionCache()
+	setTestValues(chart, "a=first")
+	result, err := cache.RenderAction(chart, templatePath, content, action, noPartials, util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", result)
+
+	// the cached result is returned as long as the values did not change
+	cache.charts[chart.RootURI.Filename()].results[templatePath+"\x00"+buildActionTemplate(action, content)] = renderResult{output: "cached"}
+	result, err = cache.RenderAction(chart, templatePath, content, action, noPartials, util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", result)
+
+	setTestValues(chart, "a=second")
+	result, err = cache.RenderAction(chart, templatePath, content, action, noPartials, util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", result)
+	assert.Len(t, cache.charts, 1)
+}
+
+func TestActionCacheUsesChangedPartialsAfterInvalidate(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "deployment.yaml")
+	partialPath := filepath.Join(chart.RootURI.Filename(), "templates", "_test_helpers.tpl")
+	content := []byte(`{{ include "test.partial" . }}`)
+	ast := templateast.ParseAst(nil, content)
+	action := GetActionForNode(templateast.NodeAtPosition(ast, lsp.Position{Line: 0, Character: 5}))
+	partials := func(output string) func() map[string][]byte {
+		return func() map[string][]byte {
+			return map[string][]byte{partialPath: []byte(`{{ define "test.partial" }}` + output + `{{ end }}`)}
+		}
+	}
+
+	cache := NewActionCache()
+	result, err := cache.RenderAction(chart, templatePath, content, action, partials("first"), util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", result)
+
+	result, err = cache.RenderAction(chart, templatePath, content, action, partials("second"), util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", result)
+
+	cache.Invalidate()
+	result, err = cache.RenderAction(chart, templatePath, content, action, partials("second"), util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", result)
+}
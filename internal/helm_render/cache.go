@@ -0,0 +1,125 @@
+package helmrender
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/util"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// maxCachedActions limits the number of results kept per chart
+const maxCachedActions = 100
+
+type renderResult struct {
+	output string
+	err    error
+}
+
+// ActionCache caches the results of RenderAction per chart. The results of a chart are dropped once
+// the version of its values changes, Invalidate drops all results (e.g. after a template changed).
+type ActionCache struct {
+	mutex  sync.Mutex
+	charts map[string]*cachedActions
+}
+
+type cachedActions struct {
+	valuesVersion uint64
+	results       map[string]renderResult
+}
+
+func NewActionCache() *ActionCache {
+	return &ActionCache{
+		charts: map[string]*cachedActions{},
+	}
+}
+
+// Invalidate drops all cached results
+func (c *ActionCache) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.charts = map[string]*cachedActions{}
+}
+
+// RenderAction returns the cached result of RenderAction or renders the action with the render values
+// of the chart, getPartials is only called if the action is rendered
+func (c *ActionCache) RenderAction(chart *charts.Chart, templatePath string, content []byte, action *sitter.Node,
+	getPartials func() map[string][]byte, config util.RenderConfig,
+) (string, error) {
+	rootDir := chart.RootURI.Filename()
+	valuesVersion := chart.ValuesFiles.Version()
+	key := templatePath + "\x00" + buildActionTemplate(action, content)
+
+	c.mutex.Lock()
+	if cached, ok := c.charts[rootDir]; ok && cached.valuesVersion == valuesVersion {
+		if result, ok := cached.results[key]; ok {
+			c.mutex.Unlock()
+			return result.output, result.err
+		}
+	}
+	c.mutex.Unlock()
+
+	output, err := RenderAction(chart, templatePath, content, action, getPartials(), chart.ValuesFiles.GetRenderValues(), config)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cached, ok := c.charts[rootDir]
+	if !ok || cached.valuesVersion != valuesVersion || len(cached.results) >= maxCachedActions {
+		cached = &cachedActions{valuesVersion: valuesVersion, results: map[string]renderResult{}}
+		c.charts[rootDir] = cached
+	}
+	cached.results[key] = renderResult{output: output, err: err}
+
+	return output, err
+}
+
+// TemplateCache caches the last result of RenderTemplate per template. The template is rendered
+// again once its content or the version of the values changed, Invalidate drops all results.
+type TemplateCache struct {
+	mutex   sync.Mutex
+	results map[string]cachedTemplate
+}
+
+type cachedTemplate struct {
+	content       []byte
+	valuesVersion uint64
+	result        renderResult
+}
+
+func NewTemplateCache() *TemplateCache {
+	return &TemplateCache{
+		results: map[string]cachedTemplate{},
+	}
+}
+
+// Invalidate drops all cached results
+func (c *TemplateCache) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.results = map[string]cachedTemplate{}
+}
+
+// RenderTemplate returns the cached result of RenderTemplate or renders the template with the render values of the chart
+func (c *TemplateCache) RenderTemplate(chart *charts.Chart, templatePath string, content []byte, config util.RenderConfig) (string, error) {
+	valuesVersion := chart.ValuesFiles.Version()
+
+	c.mutex.Lock()
+	if cached, ok := c.results[templatePath]; ok && cached.valuesVersion == valuesVersion && bytes.Equal(cached.content, content) {
+		c.mutex.Unlock()
+		return cached.result.output, cached.result.err
+	}
+	c.mutex.Unlock()
+
+	output, err := RenderTemplate(chart, templatePath, content, chart.ValuesFiles.GetRenderValues(), config)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.results[templatePath] = cachedTemplate{
+		content:       bytes.Clone(content),
+		valuesVersion: valuesVersion,
+		result:        renderResult{output: output, err: err},
+	}
+
+	return output, err
+}
@@ -0,0 +1,166 @@
+package helmrender
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/log"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+var logger = log.GetLogger()
+
+// RenderTemplate renders a single template of the chart with the helm engine.
+// The content is used instead of the content of the template on disk, so unsaved
+// changes are rendered as well. Only the partials of the chart and its dependencies
+// are rendered next to the template, therefore errors in other templates are ignored.
+func RenderTemplate(c *charts.Chart, templatePath string, content []byte, vals chartutil.Values, config util.RenderConfig) (string, error) {
+	templateName, err := getTemplateName(c, templatePath)
+	if err != nil {
+		return "", err
+	}
+
+	return renderTemplates(c.HelmChart, map[string][]byte{templateName: content}, templateName, vals, config)
+}
+
+// renderTemplates renders the chart with the given templates added (or replaced) and
+// returns the output of the renderedTemplate
+func renderTemplates(helmChart *chart.Chart, templates map[string][]byte, renderedTemplate string, vals chartutil.Values, config util.RenderConfig) (string, error) {
+	renderChart := copyChartWithTemplates(helmChart, templates)
+
+	renderValues, err := ToRenderValues(renderChart, vals, config)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := engine.Render(renderChart, renderValues)
+	if err != nil {
+		return "", err
+	}
+
+	result, ok := rendered[path.Join(renderChart.ChartFullPath(), renderedTemplate)]
+	if !ok {
+		return "", fmt.Errorf("template %s was not rendered", renderedTemplate)
+	}
+	return result, nil
+}
+
+// ToRenderValues builds the values that are passed to the templates (.Values, .Release, .Capabilities, ...)
+func ToRenderValues(c *chart.Chart, vals chartutil.Values, config util.RenderConfig) (chartutil.Values, error) {
+	caps, err := getCapabilities(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return chartutil.ToRenderValues(c, vals, getReleaseOptions(config), caps)
+}
+
+// GetBuiltInObjectValue returns the value of a field of .Release or .Capabilities (e.g. Capabilities.KubeVersion.Minor)
+// that is used when rendering with the config
+func GetBuiltInObjectValue(config util.RenderConfig, field string) (string, error) {
+	caps, err := getCapabilities(config)
+	if err != nil {
+		return "", err
+	}
+	options := getReleaseOptions(config)
+	builtInObjects := map[string]interface{}{
+		"Capabilities": caps,
+		"Release": map[string]interface{}{
+			"Name":      options.Name,
+			"Namespace": options.Namespace,
+			"IsUpgrade": options.IsUpgrade,
+			"IsInstall": options.IsInstall,
+			"Revision":  options.Revision,
+			"Service":   "Helm",
+		},
+	}
+
+	tmpl, err := template.New("value").Option("missingkey=error").Parse(fmt.Sprintf("{{ .%s }}", field))
+	if err != nil {
+		return "", err
+	}
+	result := strings.Builder{}
+	if err := tmpl.Execute(&result, builtInObjects); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+func getReleaseOptions(config util.RenderConfig) chartutil.ReleaseOptions {
+	revision := 1
+	if config.IsUpgrade {
+		revision = 2
+	}
+	return chartutil.ReleaseOptions{
+		Name:      config.ReleaseName,
+		Namespace: config.Namespace,
+		Revision:  revision,
+		IsInstall: !config.IsUpgrade,
+		IsUpgrade: config.IsUpgrade,
+	}
+}
+
+func getCapabilities(config util.RenderConfig) (*chartutil.Capabilities, error) {
+	caps := chartutil.DefaultCapabilities.Copy()
+	// the copy shares the api versions with the default capabilities, they must not be modified
+	caps.APIVersions = slices.Concat(caps.APIVersions, config.APIVersions)
+	if config.KubeVersion == "" {
+		return caps, nil
+	}
+
+	kubeVersion, err := chartutil.ParseKubeVersion(config.KubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeVersion %s: %w", config.KubeVersion, err)
+	}
+	caps.KubeVersion = *kubeVersion
+	return caps, nil
+}
+
+func getTemplateName(c *charts.Chart, templatePath string) (string, error) {
+	relativePath, err := filepath.Rel(c.RootURI.Filename(), templatePath)
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		return "", fmt.Errorf("template %s is not part of the chart %s", templatePath, c.RootURI.Filename())
+	}
+	return filepath.ToSlash(relativePath), nil
+}
+
+// copyChartWithTemplates returns a shallow copy of the chart that only contains the partials
+// of the chart (and its dependencies) and the given templates
+func copyChartWithTemplates(c *chart.Chart, templates map[string][]byte) *chart.Chart {
+	result := copyChartWithPartials(c)
+	for name, content := range templates {
+		result.Templates = slices.DeleteFunc(result.Templates, func(file *chart.File) bool { return file.Name == name })
+		result.Templates = append(result.Templates, &chart.File{Name: name, Data: content})
+	}
+	return result
+}
+
+func copyChartWithPartials(c *chart.Chart) *chart.Chart {
+	result := *c
+	result.Templates = []*chart.File{}
+	for _, template := range c.Templates {
+		if template != nil && isPartial(template.Name) {
+			result.Templates = append(result.Templates, template)
+		}
+	}
+
+	dependencies := []*chart.Chart{}
+	for _, dependency := range c.Dependencies() {
+		dependencies = append(dependencies, copyChartWithPartials(dependency))
+	}
+	result.SetDependencies(dependencies...)
+
+	return &result
+}
+
+func isPartial(templateName string) bool {
+	return strings.HasPrefix(path.Base(templateName), "_")
+}
@@ -0,0 +1,132 @@
+package helmrender
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "service.yaml")
+	content, err := os.ReadFile(templatePath)
+	assert.NoError(t, err)
+
+	result, err := RenderTemplate(chart, templatePath, content,
+		charts.CoalesceValuesFiles(chart.ValuesFiles.MainValuesFile), util.DefaultConfig.RenderConfig)
+
+	assert.NoError(t, err)
+	assert.Contains(t, result, "name: release-name-example")
+	assert.Contains(t, result, "type: ClusterIP")
+	assert.Contains(t, result, "app.kubernetes.io/instance: release-name")
+}
+
+func TestRenderTemplateUsesGivenContent(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "service.yaml")
+
+	result, err := RenderTemplate(chart, templatePath,
+		[]byte(`name: {{ .Release.Name }}-{{ .Release.Namespace }}-{{ .Values.foo }}-{{ .Capabilities.KubeVersion.Minor }}`),
+		map[string]interface{}{"foo": "bar"},
+		util.RenderConfig{ReleaseName: "my-release", Namespace: "my-namespace", KubeVersion: "v1.29.0"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "name: my-release-my-namespace-bar-29", result)
+}
+
+func TestTemplateCache(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "service.yaml")
+
+	cache := NewTemplateCache()
+	setTestValues(chart, "a=first")
+	result, err := cache.RenderTemplate(chart, templatePath, []byte(`a: {{ .Values.a }}`), util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "a: first", result)
+
+	// the cached result is returned as long as nothing changed
+	cached := cache.results[templatePath]
+	cached.result = renderResult{output: "cached"}
+	cache.results[templatePath] = cached
+	result, err = cache.RenderTemplate(chart, templatePath, []byte(`a: {{ .Values.a }}`), util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", result)
+
+	result, err = cache.RenderTemplate(chart, templatePath, []byte(`b: {{ .Values.a }}`), util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "b: first", result)
+
+	setTestValues(chart, "a=second")
+	result, err = cache.RenderTemplate(chart, templatePath, []byte(`b: {{ .Values.a }}`), util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "b: second", result)
+	assert.Len(t, cache.results, 1)
+}
+
+func TestRenderTemplateUsesConfiguredCapabilitiesAndRelease(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "service.yaml")
+	template := []byte(`{{ .Capabilities.APIVersions.Has "monitoring.coreos.com/v1/ServiceMonitor" }}-{{ .Release.IsUpgrade }}-{{ .Release.IsInstall }}`)
+
+	result, err := RenderTemplate(chart, templatePath, template, map[string]interface{}{}, util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "false-false-true", result)
+
+	config := util.DefaultConfig.RenderConfig
+	config.APIVersions = []string{"monitoring.coreos.com/v1/ServiceMonitor"}
+	config.IsUpgrade = true
+	result, err = RenderTemplate(chart, templatePath, template, map[string]interface{}{}, config)
+	assert.NoError(t, err)
+	assert.Equal(t, "true-true-false", result)
+	assert.NotContains(t, chartutil.DefaultCapabilities.APIVersions, "monitoring.coreos.com/v1/ServiceMonitor")
+}
+
+func TestGetBuiltInObjectValue(t *testing.T) {
+	config := util.RenderConfig{ReleaseName: "my-release", Namespace: "my-namespace", KubeVersion: "v1.29.3", IsUpgrade: true}
+	testCases := []struct {
+		field    string
+		expected string
+	}{
+		{"Capabilities.KubeVersion.Minor", "29"},
+		{"Capabilities.KubeVersion.Version", "v1.29.3"},
+		{"Release.Name", "my-release"},
+		{"Release.Namespace", "my-namespace"},
+		{"Release.IsUpgrade", "true"},
+		{"Release.Revision", "2"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.field, func(t *testing.T) {
+			result, err := GetBuiltInObjectValue(config, tt.field)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := GetBuiltInObjectValue(config, "Release.Unknown")
+	assert.Error(t, err)
+}
+
+func TestRenderTemplateReturnsTemplateErrors(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "service.yaml")
+
+	_, err := RenderTemplate(chart, templatePath, []byte(`{{ fail "something is wrong" }}`),
+		map[string]interface{}{}, util.DefaultConfig.RenderConfig)
+
+	assert.ErrorContains(t, err, "something is wrong")
+}
+
+func TestRenderTemplateOutsideOfChart(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+
+	_, err := RenderTemplate(chart, "/tmp/other/templates/service.yaml", []byte(``),
+		map[string]interface{}{}, util.DefaultConfig.RenderConfig)
+
+	assert.Error(t, err)
+}
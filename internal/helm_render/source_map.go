@@ -0,0 +1,229 @@
+package helmrender
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	"github.com/mrjosh/helm-ls/internal/util"
+	sitter "github.com/smacker/go-tree-sitter"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	markerActionStart = "S"
+	markerActionEnd   = "E"
+	markerPoint       = "P"
+)
+
+var (
+	markerRegex = regexp.MustCompile("\x00([SEP])([0-9]+)\x00")
+
+	ErrSyntaxError = errors.New("the template contains syntax errors")
+)
+
+// ActionOutput is the part of the rendered output that was produced by an action
+type ActionOutput struct {
+	// Action is the node of the action in the template, e.g. the pipeline of {{ .Values.foo | toYaml }}
+	Action      *sitter.Node
+	OutputStart int
+	OutputEnd   int
+}
+
+type sourcePoint struct {
+	outputOffset int
+	sourceOffset int
+}
+
+// SourceMap maps byte offsets of the rendered output of a template back to the template
+type SourceMap struct {
+	Output  string
+	Actions []ActionOutput
+	// points are offsets after the closing braces of actions, the text following them
+	// in the output is the text following them in the template
+	points []sourcePoint
+}
+
+// GetActionAt returns the action that produced the output at the offset
+func (m *SourceMap) GetActionAt(outputOffset int) (ActionOutput, bool) {
+	for _, action := range m.Actions {
+		if outputOffset >= action.OutputStart && outputOffset < action.OutputEnd {
+			return action, true
+		}
+	}
+	return ActionOutput{}, false
+}
+
+// GetFirstActionInRange returns the first action whose output overlaps with the range of the output
+func (m *SourceMap) GetFirstActionInRange(outputStart, outputEnd int) (ActionOutput, bool) {
+	for _, action := range m.Actions {
+		if action.OutputStart < outputEnd && action.OutputEnd > outputStart {
+			return action, true
+		}
+	}
+	return ActionOutput{}, false
+}
+
+// GetSourceOffset returns the offset in the template for an offset of the output.
+// Offsets inside of the output of an action are mapped to the start of the action.
+func (m *SourceMap) GetSourceOffset(outputOffset int) int {
+	if action, ok := m.GetActionAt(outputOffset); ok {
+		return int(action.Action.StartByte())
+	}
+	i := sort.Search(len(m.points), func(i int) bool { return m.points[i].outputOffset > outputOffset }) - 1
+	if i < 0 {
+		return outputOffset
+	}
+	return m.points[i].sourceOffset + outputOffset - m.points[i].outputOffset
+}
+
+// RenderTemplateWithSourceMap renders the template like RenderTemplate and returns a source map
+// for the output. Markers are added next to the actions of the template before rendering,
+// they are placed directly next to the braces to keep the whitespace trimming of the actions.
+func RenderTemplateWithSourceMap(c *charts.Chart, templatePath string, content []byte, ast *sitter.Tree,
+	vals chartutil.Values, config util.RenderConfig,
+) (*SourceMap, error) {
+	if ast == nil || ast.RootNode().HasError() {
+		return nil, ErrSyntaxError
+	}
+	markedContent, markedNodes := addMarkers(ast.RootNode(), content)
+	output, err := RenderTemplate(c, templatePath, markedContent, vals, config)
+	if err != nil {
+		return nil, err
+	}
+	return buildSourceMap(output, markedNodes)
+}
+
+type insertion struct {
+	offset int
+	text   string
+}
+
+// addMarkers inserts markers around all actions that produce output and after all other
+// closing braces. The returned nodes are referenced by the index in the markers.
+func addMarkers(root *sitter.Node, content []byte) ([]byte, []*sitter.Node) {
+	insertions := []insertion{}
+	markedNodes := []*sitter.Node{}
+	markedCloseBraces := map[uint32]bool{}
+
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if node.Type() == gotemplate.NodeTypeDefineAction || node.Type() == gotemplate.NodeTypeBlockAction {
+			// the content of defines is rendered where they are included
+			return
+		}
+		for i := 0; i < int(node.ChildCount()); i++ {
+			child := node.Child(i)
+			open, close, ok := getOutputActionBraces(node, i)
+			if ok {
+				id := len(markedNodes)
+				markedNodes = append(markedNodes, child)
+				insertions = append(insertions,
+					insertion{int(open.EndByte()), fmt.Sprintf(` "\x00%s%d\x00" }}{{ `, markerActionStart, id)},
+					insertion{int(close.StartByte()), fmt.Sprintf(` }}{{ "\x00%s%d\x00" `, markerActionEnd, id)},
+				)
+				markedCloseBraces[close.StartByte()] = true
+				continue
+			}
+			if isCloseBraces(child) && !markedCloseBraces[child.StartByte()] && !isAfterComment(child) {
+				id := len(markedNodes)
+				markedNodes = append(markedNodes, child)
+				insertions = append(insertions, insertion{int(child.StartByte()), fmt.Sprintf(` }}{{ "\x00%s%d\x00" `, markerPoint, id)})
+			}
+			walk(child)
+		}
+	}
+	walk(root)
+
+	slices.SortStableFunc(insertions, func(a, b insertion) int { return a.offset - b.offset })
+	result := strings.Builder{}
+	last := 0
+	for _, insertion := range insertions {
+		result.Write(content[last:insertion.offset])
+		result.WriteString(insertion.text)
+		last = insertion.offset
+	}
+	result.Write(content[last:])
+	return []byte(result.String()), markedNodes
+}
+
+// getOutputActionBraces returns the braces surrounding the i-th child of the node if the child
+// is an action that produces output
+func getOutputActionBraces(node *sitter.Node, i int) (open, close *sitter.Node, ok bool) {
+	child := node.Child(i)
+	if !child.IsNamed() || slices.Contains(blockNodeTypes, child.Type()) || slices.Contains(noOutputNodeTypes, child.Type()) {
+		return nil, nil, false
+	}
+	if child.Type() == gotemplate.NodeTypeTemplateAction {
+		if child.ChildCount() < 2 {
+			return nil, nil, false
+		}
+		open, close = child.Child(0), child.Child(int(child.ChildCount())-1)
+		return open, close, isOpenBraces(open) && isCloseBraces(close)
+	}
+	if i == 0 || i+1 >= int(node.ChildCount()) {
+		return nil, nil, false
+	}
+	open, close = node.Child(i-1), node.Child(i+1)
+	return open, close, isOpenBraces(open) && isCloseBraces(close)
+}
+
+func isOpenBraces(node *sitter.Node) bool {
+	return node.Type() == gotemplate.NodeTypeOpenBraces || node.Type() == gotemplate.NodeTypeOpenBracesDash
+}
+
+func isCloseBraces(node *sitter.Node) bool {
+	return node.Type() == gotemplate.NodeTypeCloseBraces || node.Type() == gotemplate.NodeTypeCloseBracesDash
+}
+
+// isAfterComment returns true for the closing braces of comments, which must directly follow the comment
+func isAfterComment(node *sitter.Node) bool {
+	previous := node.PrevSibling()
+	return previous != nil && previous.Type() == gotemplate.NodeTypeComment
+}
+
+// getActionEndOffset returns the offset after the closing braces of the action
+func getActionEndOffset(action *sitter.Node) int {
+	if next := action.NextSibling(); action.Type() != gotemplate.NodeTypeTemplateAction && next != nil {
+		return int(next.EndByte())
+	}
+	return int(action.EndByte())
+}
+
+// buildSourceMap removes the markers from the output and records their positions
+func buildSourceMap(markedOutput string, markedNodes []*sitter.Node) (*SourceMap, error) {
+	result := &SourceMap{}
+	output := strings.Builder{}
+	openActions := map[int]int{}
+
+	last := 0
+	for _, match := range markerRegex.FindAllStringSubmatchIndex(markedOutput, -1) {
+		output.WriteString(markedOutput[last:match[0]])
+		last = match[1]
+
+		kind := markedOutput[match[2]:match[3]]
+		id, err := strconv.Atoi(markedOutput[match[4]:match[5]])
+		if err != nil || id >= len(markedNodes) {
+			return nil, fmt.Errorf("invalid marker in rendered output")
+		}
+		node := markedNodes[id]
+		switch kind {
+		case markerActionStart:
+			openActions[id] = output.Len()
+		case markerActionEnd:
+			result.Actions = append(result.Actions, ActionOutput{Action: node, OutputStart: openActions[id], OutputEnd: output.Len()})
+			result.points = append(result.points, sourcePoint{output.Len(), getActionEndOffset(node)})
+		case markerPoint:
+			result.points = append(result.points, sourcePoint{output.Len(), int(node.EndByte())})
+		}
+	}
+	output.WriteString(markedOutput[last:])
+	result.Output = output.String()
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package helmrender
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func TestRenderTemplateWithSourceMap(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "service.yaml")
+	content := `metadata:
+  name: {{ .Values.name }}
+  labels:
+    {{- toYaml .Values.labels | nindent 4 }}
+  {{- if .Values.enabled }}
+  enabled: true
+  {{- end }}
+  {{- /* a comment */}}
+  {{- range .Values.list }}
+  - {{ . }}
+  {{- end }}
+spec: {}
+`
+	vals := map[string]interface{}{
+		"name":    "test",
+		"labels":  map[string]interface{}{"a": "b"},
+		"enabled": true,
+		"list":    []interface{}{"x", "y"},
+	}
+
+	expected, err := RenderTemplate(chart, templatePath, []byte(content), vals, util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+
+	result, err := RenderTemplateWithSourceMap(chart, templatePath, []byte(content), templateast.ParseAst(nil, []byte(content)), vals, util.DefaultConfig.RenderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result.Output)
+
+	// the output of actions is mapped to the action
+	action, ok := result.GetActionAt(strings.Index(result.Output, "test"))
+	assert.True(t, ok)
+	assert.Equal(t, ".Values.name", action.Action.Content([]byte(content)))
+	action, ok = result.GetActionAt(strings.Index(result.Output, "a: b"))
+	assert.True(t, ok)
+	assert.Equal(t, "toYaml .Values.labels | nindent 4", strings.TrimSpace(action.Action.Content([]byte(content))))
+	assert.Equal(t, strings.Index(content, "toYaml"), result.GetSourceOffset(strings.Index(result.Output, "a: b")))
+
+	// text is mapped to the text in the template
+	assert.Equal(t, strings.Index(content, "enabled: true"), result.GetSourceOffset(strings.Index(result.Output, "enabled: true")))
+	assert.Equal(t, strings.Index(content, "spec:"), result.GetSourceOffset(strings.Index(result.Output, "spec:")))
+	assert.Equal(t, strings.Index(content, "metadata"), result.GetSourceOffset(0))
+}
+
+func TestRenderTemplateWithSourceMapSyntaxError(t *testing.T) {
+	chart := charts.NewChart(uri.File("../../testdata/example"), util.DefaultConfig.ValuesFilesConfig)
+	templatePath := filepath.Join(chart.RootURI.Filename(), "templates", "service.yaml")
+	content := []byte(`name: {{ .Values.name `)
+
+	_, err := RenderTemplateWithSourceMap(chart, templatePath, content, templateast.ParseAst(nil, content), map[string]interface{}{}, util.DefaultConfig.RenderConfig)
+
+	assert.ErrorIs(t, err, ErrSyntaxError)
+}
@@ -130,6 +130,9 @@ func getChecksum(chart *charts.Chart) uint32 {
 		}
 		totalContent = append(totalContent, content...)
 	}
+	if chart.HelmChart != nil {
+		totalContent = append(totalContent, chart.HelmChart.Schema...)
+	}
 
 	return adler32.Checksum(totalContent)
 }
@@ -141,6 +144,14 @@ func (c *JSONSchemaCache) GetSchemaPathForChart(chart *charts.Chart) string {
 }
 
 func (c *JSONSchemaCache) writeSchemaToFile(schema *Schema, chart *charts.Chart) (string, error) {
+	path := c.GetSchemaPathForChart(chart)
+	if err := c.writeSchemaToPath(schema, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (c *JSONSchemaCache) writeSchemaToPath(schema *Schema, path string) error {
 	var err error
 	var bytes []byte
 	if c.config.prettyPrint {
@@ -150,19 +161,18 @@ func (c *JSONSchemaCache) writeSchemaToFile(schema *Schema, chart *charts.Chart)
 	}
 
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal schema: %w", err)
+		return fmt.Errorf("failed to marshal schema: %w", err)
 	}
 
-	path := c.GetSchemaPathForChart(chart)
 	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
+		return fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer file.Close()
 
 	if _, err := file.Write(bytes); err != nil {
-		return "", fmt.Errorf("failed to write schema to file: %w", err)
+		return fmt.Errorf("failed to write schema to file: %w", err)
 	}
 
-	return file.Name(), nil
+	return nil
 }
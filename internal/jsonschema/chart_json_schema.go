@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -212,20 +213,28 @@ func (g *SchemaGenerator) addNestedDef(name string, schema *Schema, nesting []st
 	)
 }
 
-// Gets the schema from the values.schema.json file if the chart has one
+// Gets the schema from the values.schema.json file if the chart has one.
+// The file is referenced instead of being embedded, so that definitions and
+// references within the file keep working and no keywords get lost.
+// Charts without the file on disk (e.g. packaged dependencies) get the schema embedded.
 func (g *SchemaGenerator) getSchemaFileSchema(chart *charts.Chart) *Schema {
-	if chart.HelmChart.Schema != nil {
-		schemaFileSchema := &Schema{}
-		err := json.Unmarshal(chart.HelmChart.Schema, schemaFileSchema)
-		if err != nil {
-			logger.Error("Failed to unmarshal schema from helm chart "+chart.RootURI, err)
-			g.errors = append(g.errors, fmt.Errorf("failed to unmarshal schema from helm chart %s: %w", chart.RootURI, err))
-		} else {
-			return schemaFileSchema
-		}
+	if chart.HelmChart == nil || chart.HelmChart.Schema == nil {
+		return nil
 	}
 
-	return nil
+	schemaFilePath := filepath.Join(chart.RootURI.Filename(), ValuesSchemaFileName)
+	if _, err := os.Stat(schemaFilePath); err == nil {
+		return &Schema{Ref: string(uri.File(schemaFilePath))}
+	}
+
+	schemaFileSchema := &Schema{}
+	err := json.Unmarshal(chart.HelmChart.Schema, schemaFileSchema)
+	if err != nil {
+		logger.Error("Failed to unmarshal schema from helm chart "+chart.RootURI, err)
+		g.errors = append(g.errors, fmt.Errorf("failed to unmarshal schema from helm chart %s: %w", chart.RootURI, err))
+		return nil
+	}
+	return schemaFileSchema
 }
 
 func nestSchemaInScopes(schema *Schema, scopes []string) *Schema {
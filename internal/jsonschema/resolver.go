@@ -0,0 +1,116 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"go.lsp.dev/uri"
+)
+
+// kubernetesSchemaKey is the keyword yaml-language-server uses for its built-in kubernetes schema,
+// it can not be referenced from a JSON schema
+const kubernetesSchemaKey = "kubernetes"
+
+type yamllsSchemaSettings struct {
+	Schemas map[string]any `json:"schemas"`
+}
+
+// GetUserSchemasForFile returns the schemas of the yaml-language-server settings (yaml.schemas)
+// that are associated with the given file, sorted by their URI.
+// Relative schema paths and globs are resolved against the workspace root, like yaml-language-server does.
+func GetUserSchemasForFile(yamllsSettings any, rootURI uri.URI, fileURI uri.URI) []uri.URI {
+	settings := yamllsSchemaSettings{}
+	bytes, err := json.Marshal(yamllsSettings)
+	if err != nil {
+		logger.Error("Failed to read yamlls schema settings", err)
+		return nil
+	}
+	if err := json.Unmarshal(bytes, &settings); err != nil {
+		logger.Error("Failed to read yamlls schema settings", err)
+		return nil
+	}
+
+	result := []uri.URI{}
+	for schema, globs := range settings.Schemas {
+		if schema == kubernetesSchemaKey {
+			continue
+		}
+		if slices.ContainsFunc(toStringSlice(globs), func(pattern string) bool {
+			return matchesSchemaGlob(pattern, rootURI, fileURI)
+		}) {
+			result = append(result, resolveSchemaURI(schema, rootURI))
+		}
+	}
+
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// matchesSchemaGlob matches the file against the glob relative to the workspace root.
+// Patterns that are not anchored with a leading slash match in any directory.
+func matchesSchemaGlob(pattern string, rootURI uri.URI, fileURI uri.URI) bool {
+	path := filepath.ToSlash(fileURI.Filename())
+	if relativePath, err := filepath.Rel(rootURI.Filename(), fileURI.Filename()); err == nil && !strings.HasPrefix(relativePath, "..") {
+		path = filepath.ToSlash(relativePath)
+	}
+
+	patterns := []string{strings.TrimPrefix(pattern, "/")}
+	if !strings.HasPrefix(pattern, "/") {
+		patterns = append(patterns, "**/"+pattern)
+	}
+
+	for _, p := range patterns {
+		g, err := glob.Compile(p, '/')
+		if err != nil {
+			logger.Error("Invalid glob in yamlls schema settings", pattern, err)
+			return false
+		}
+		if g.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveSchemaURI(schema string, rootURI uri.URI) uri.URI {
+	if strings.Contains(schema, "://") {
+		// uri.New would treat the URL as a file path
+		return uri.URI(schema)
+	}
+	if filepath.IsAbs(schema) {
+		return uri.File(schema)
+	}
+	return uri.File(filepath.Join(rootURI.Filename(), schema))
+}
+
+// GetCombinedJSONSchema returns the path of a schema that combines the user configured schemas
+// and the generated schema through allOf, a value has to match all of them. The generated schema
+// already references the values.schema.json of the chart. If there are no user configured schemas
+// the generated schema is returned.
+func (c *JSONSchemaCache) GetCombinedJSONSchema(chart *charts.Chart, userSchemas []uri.URI, generatedSchemaPath string) (string, error) {
+	if len(userSchemas) == 0 {
+		return generatedSchemaPath, nil
+	}
+	refs := append(slices.Clone(userSchemas), uri.File(generatedSchemaPath))
+
+	schema := &Schema{
+		Version: Version,
+		AllOf:   make([]*Schema, 0, len(refs)),
+	}
+	refStrings := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		schema.AllOf = append(schema.AllOf, &Schema{Ref: string(ref)})
+		refStrings = append(refStrings, string(ref))
+	}
+
+	id := adler32.Checksum([]byte(strings.Join(refStrings, "\n")))
+	path := filepath.Join(c.schemaFilesDir, fmt.Sprintf("%d-%s-combined.json", id, chart.Name()))
+
+	return path, c.writeSchemaToPath(schema, path)
+}
@@ -0,0 +1,109 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestGetUserSchemasForFile(t *testing.T) {
+	rootURI := uri.File("/workspace")
+
+	testCases := []struct {
+		desc     string
+		settings any
+		file     string
+		expected []uri.URI
+	}{
+		{
+			desc:     "default settings only associate the kubernetes schema",
+			settings: util.DefaultYamllsSettings,
+			file:     "/workspace/chart/values.yaml",
+			expected: []uri.URI{},
+		},
+		{
+			desc: "settings from the client config",
+			settings: map[string]any{
+				"schemas": map[string]any{
+					"https://example.com/b.json": "values.yaml",
+					"schemas/a.json":             []any{"other.yaml", "chart/values*.yaml"},
+					"/abs/c.json":                "/chart/values.yaml",
+					"https://example.com/d.json": "templates/**",
+					"kubernetes":                 "values.yaml",
+				},
+			},
+			file: "/workspace/chart/values.yaml",
+			expected: []uri.URI{
+				uri.File("/abs/c.json"),
+				uri.File("/workspace/schemas/a.json"),
+				uri.URI("https://example.com/b.json"),
+			},
+		},
+		{
+			desc: "anchored globs only match relative to the workspace root",
+			settings: map[string]any{
+				"schemas": map[string]any{
+					"https://example.com/b.json": "/values.yaml",
+				},
+			},
+			file:     "/workspace/chart/values.yaml",
+			expected: []uri.URI{},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result := GetUserSchemasForFile(tt.settings, rootURI, uri.File(tt.file))
+			assert.ElementsMatch(t, tt.expected, result)
+			assert.IsNonDecreasing(t, result)
+		})
+	}
+}
+
+func TestGetCombinedJSONSchema(t *testing.T) {
+	tempDir := t.TempDir()
+	chartDir := filepath.Join(tempDir, "chart")
+	assert.NoError(t, os.MkdirAll(chartDir, 0o755))
+
+	sut := &JSONSchemaCache{schemaFilesDir: tempDir}
+	testChart := &charts.Chart{
+		HelmChart:     &chart.Chart{},
+		ChartMetadata: &charts.ChartMetadata{},
+		RootURI:       uri.File(chartDir),
+	}
+	generated := filepath.Join(tempDir, "generated.json")
+
+	result, err := sut.GetCombinedJSONSchema(testChart, []uri.URI{}, generated)
+	assert.NoError(t, err)
+	assert.Equal(t, generated, result)
+
+	// the values.schema.json is referenced by the generated schema
+	schemaFile := filepath.Join(chartDir, ValuesSchemaFileName)
+	assert.NoError(t, os.WriteFile(schemaFile, []byte(`{}`), 0o600))
+	result, err = sut.GetCombinedJSONSchema(testChart, []uri.URI{}, generated)
+	assert.NoError(t, err)
+	assert.Equal(t, generated, result)
+
+	userSchema := uri.URI("https://example.com/schema.json")
+
+	result, err = sut.GetCombinedJSONSchema(testChart, []uri.URI{userSchema}, generated)
+	assert.NoError(t, err)
+	assert.NotEqual(t, generated, result)
+
+	content, err := os.ReadFile(result)
+	assert.NoError(t, err)
+	schema := &Schema{}
+	assert.NoError(t, json.Unmarshal(content, schema))
+
+	refs := []string{}
+	for _, s := range schema.AllOf {
+		refs = append(refs, s.Ref)
+	}
+	assert.Equal(t, []string{string(userSchema), string(uri.File(generated))}, refs)
+}
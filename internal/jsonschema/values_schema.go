@@ -0,0 +1,303 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ValuesSchemaFileName is the name of the JSON schema file helm uses to validate the values of a chart
+const ValuesSchemaFileName = "values.schema.json"
+
+// ValuesSchemaInfo contains the documentation of a single value found in a values.schema.json file
+type ValuesSchemaInfo struct {
+	Title       string
+	Description string
+	Types       []string
+	Enum        []any
+	Default     any
+	Required    bool
+}
+
+// ValuesSchemaError is a violation of the values.schema.json found in a values file
+type ValuesSchemaError struct {
+	// Path of the value that caused the error, array indices are formatted as numbers
+	Path []string
+	// Property is set if the error is about a property of the object at Path
+	// (e.g. a missing required property or an additional property)
+	Property string
+	Message  string
+	Type     string
+}
+
+// GetValuesSchemaInfo looks up the schema of the value at the given path.
+// Local references ($ref starting with #) as well as allOf, anyOf and oneOf are followed.
+// Returns false if the schema does not describe the value.
+func GetValuesSchemaInfo(rawSchema []byte, path []string) (ValuesSchemaInfo, bool) {
+	var root map[string]any
+	if err := json.Unmarshal(rawSchema, &root); err != nil {
+		logger.Debug("Failed to unmarshal values schema", err)
+		return ValuesSchemaInfo{}, false
+	}
+
+	lookup := valuesSchemaLookup{root: root}
+	schemas, required := lookup.schemasForPath([]map[string]any{root}, path)
+	if len(schemas) == 0 {
+		return ValuesSchemaInfo{}, false
+	}
+
+	info := ValuesSchemaInfo{Required: required}
+	for _, schema := range schemas {
+		info.merge(schema)
+	}
+	return info, true
+}
+
+// GetSchemaProperties returns the documentation of the properties of the object at the given path.
+// Local references as well as allOf, anyOf and oneOf are followed like in GetValuesSchemaInfo.
+func GetSchemaProperties(rawSchema []byte, path []string) map[string]ValuesSchemaInfo {
+	var root map[string]any
+	if err := json.Unmarshal(rawSchema, &root); err != nil {
+		logger.Debug("Failed to unmarshal schema", err)
+		return map[string]ValuesSchemaInfo{}
+	}
+
+	lookup := valuesSchemaLookup{root: root}
+	schemas, _ := lookup.schemasForPath([]map[string]any{root}, path)
+
+	result := map[string]ValuesSchemaInfo{}
+	for _, schema := range schemas {
+		properties, _ := schema["properties"].(map[string]any)
+		required := toStringSlice(schema["required"])
+		for name, property := range properties {
+			propertySchema, ok := property.(map[string]any)
+			if !ok {
+				continue
+			}
+			info := result[name]
+			for _, subSchema := range lookup.expand([]map[string]any{propertySchema}) {
+				info.merge(subSchema)
+			}
+			info.Required = info.Required || slices.Contains(required, name)
+			result[name] = info
+		}
+	}
+	return result
+}
+
+// Markdown formats the documentation of the value
+func (i ValuesSchemaInfo) Markdown() string {
+	lines := []string{}
+	if i.Title != "" {
+		lines = append(lines, fmt.Sprintf("**%s**", i.Title))
+	}
+	if i.Description != "" {
+		lines = append(lines, i.Description)
+	}
+	if len(i.Types) > 0 {
+		lines = append(lines, fmt.Sprintf("Type: `%s`", strings.Join(i.Types, " | ")))
+	}
+	if len(i.Enum) > 0 {
+		enum := []string{}
+		for _, value := range i.Enum {
+			enum = append(enum, fmt.Sprintf("`%v`", value))
+		}
+		lines = append(lines, fmt.Sprintf("Enum: %s", strings.Join(enum, ", ")))
+	}
+	if i.Required {
+		lines = append(lines, "Required")
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// ValidateValues validates the values against the values.schema.json of a chart
+func ValidateValues(rawSchema []byte, values map[string]any) ([]ValuesSchemaError, error) {
+	return Validate(rawSchema, values, ValuesSchemaFileName)
+}
+
+// Validate validates a document against a JSON schema, the schema name is only used for error messages
+func Validate(rawSchema []byte, document map[string]any, schemaName string) ([]ValuesSchemaError, error) {
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert document to json: %w", err)
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(rawSchema), gojsonschema.NewBytesLoader(documentJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate against %s: %w", schemaName, err)
+	}
+
+	errors := []ValuesSchemaError{}
+	for _, resultError := range result.Errors() {
+		valuesSchemaError := ValuesSchemaError{
+			Path:    contextToPath(resultError.Context()),
+			Message: resultError.Description(),
+			Type:    resultError.Type(),
+		}
+		if property, ok := resultError.Details()["property"].(string); ok {
+			valuesSchemaError.Property = property
+		}
+		errors = append(errors, valuesSchemaError)
+	}
+	return errors, nil
+}
+
+func contextToPath(context *gojsonschema.JsonContext) []string {
+	if context == nil {
+		return []string{}
+	}
+	// a NUL byte can not be part of a yaml key, it is therefore safe to use as delimiter
+	parts := strings.Split(context.String("\x00"), "\x00")
+	if len(parts) > 0 && parts[0] == gojsonschema.STRING_CONTEXT_ROOT {
+		parts = parts[1:]
+	}
+	return parts
+}
+
+type valuesSchemaLookup struct {
+	root map[string]any
+}
+
+// schemasForPath returns all (sub)schemas that apply to the value at the path
+// and whether the last element of the path is required by one of its parents
+func (l valuesSchemaLookup) schemasForPath(schemas []map[string]any, path []string) ([]map[string]any, bool) {
+	required := false
+	for _, key := range path {
+		next := []map[string]any{}
+		required = false
+		for _, schema := range l.expand(schemas) {
+			subSchemas := l.subSchemasForKey(schema, key)
+			next = append(next, subSchemas...)
+			if len(subSchemas) > 0 && slices.Contains(toStringSlice(schema["required"]), key) {
+				required = true
+			}
+		}
+		if len(next) == 0 {
+			return nil, false
+		}
+		schemas = next
+	}
+	return l.expand(schemas), required
+}
+
+func (l valuesSchemaLookup) subSchemasForKey(schema map[string]any, key string) []map[string]any {
+	result := []map[string]any{}
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		if property, ok := properties[key].(map[string]any); ok {
+			result = append(result, property)
+		}
+	}
+	if additionalProperties, ok := schema["additionalProperties"].(map[string]any); ok && len(result) == 0 {
+		result = append(result, additionalProperties)
+	}
+	if _, err := strconv.Atoi(key); err == nil {
+		if items, ok := schema["items"].(map[string]any); ok {
+			result = append(result, items)
+		}
+	}
+	return result
+}
+
+// expand resolves references and combinations (allOf, anyOf, oneOf) of the schemas
+func (l valuesSchemaLookup) expand(schemas []map[string]any) []map[string]any {
+	result := []map[string]any{}
+	for _, schema := range schemas {
+		result = append(result, l.expandSchema(schema, map[string]bool{})...)
+	}
+	return result
+}
+
+func (l valuesSchemaLookup) expandSchema(schema map[string]any, seenRefs map[string]bool) []map[string]any {
+	result := []map[string]any{schema}
+
+	if ref, ok := schema["$ref"].(string); ok && !seenRefs[ref] {
+		seenRefs[ref] = true
+		if resolved, ok := l.resolveRef(ref); ok {
+			result = append(result, l.expandSchema(resolved, seenRefs)...)
+		}
+	}
+
+	for _, combination := range []string{"allOf", "anyOf", "oneOf"} {
+		subSchemas, ok := schema[combination].([]any)
+		if !ok {
+			continue
+		}
+		for _, subSchema := range subSchemas {
+			if subSchemaMap, ok := subSchema.(map[string]any); ok {
+				result = append(result, l.expandSchema(subSchemaMap, seenRefs)...)
+			}
+		}
+	}
+	return result
+}
+
+// resolveRef resolves a JSON pointer reference into the root schema (e.g. #/definitions/image)
+func (l valuesSchemaLookup) resolveRef(ref string) (map[string]any, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	pointer := strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/")
+
+	var current any = l.root
+	if pointer == "" {
+		return l.root, true
+	}
+	for _, part := range strings.Split(pointer, "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		switch node := current.(type) {
+		case map[string]any:
+			current = node[part]
+		case []any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	result, ok := current.(map[string]any)
+	return result, ok
+}
+
+func (i *ValuesSchemaInfo) merge(schema map[string]any) {
+	if title, ok := schema["title"].(string); ok && i.Title == "" {
+		i.Title = title
+	}
+	if description, ok := schema["description"].(string); ok && i.Description == "" {
+		i.Description = description
+	}
+	for _, schemaType := range toStringSlice(schema["type"]) {
+		if !slices.Contains(i.Types, schemaType) {
+			i.Types = append(i.Types, schemaType)
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(i.Enum) == 0 {
+		i.Enum = enum
+	}
+	if defaultValue, ok := schema["default"]; ok && i.Default == nil {
+		i.Default = defaultValue
+	}
+}
+
+// toStringSlice converts a json value that is either a string or a list of strings (e.g. type or required)
+func toStringSlice(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		result := []string{}
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return []string{}
+}
@@ -0,0 +1,134 @@
+package jsonschema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+var testValuesSchema = []byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["image"],
+  "definitions": {
+    "image": {
+      "type": "object",
+      "description": "The container image",
+      "required": ["repository"],
+      "properties": {
+        "repository": { "type": "string", "description": "Repository of the image" },
+        "pullPolicy": { "type": "string", "enum": ["Always", "IfNotPresent"] }
+      }
+    }
+  },
+  "properties": {
+    "image": { "$ref": "#/definitions/image" },
+    "replicas": { "type": ["integer", "null"], "title": "Replicas", "default": 1 },
+    "ports": {
+      "type": "array",
+      "items": {
+        "allOf": [{ "properties": { "port": { "type": "integer", "description": "The port" } } }]
+      }
+    },
+    "labels": { "type": "object", "additionalProperties": { "type": "string", "description": "A label" } }
+  }
+}`)
+
+func TestGetValuesSchemaInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []string
+		want  ValuesSchemaInfo
+		found bool
+	}{
+		{
+			name:  "ref to definitions",
+			path:  []string{"image"},
+			want:  ValuesSchemaInfo{Description: "The container image", Types: []string{"object"}, Required: true},
+			found: true,
+		},
+		{
+			name:  "property of ref",
+			path:  []string{"image", "repository"},
+			want:  ValuesSchemaInfo{Description: "Repository of the image", Types: []string{"string"}, Required: true},
+			found: true,
+		},
+		{
+			name:  "enum",
+			path:  []string{"image", "pullPolicy"},
+			want:  ValuesSchemaInfo{Types: []string{"string"}, Enum: []any{"Always", "IfNotPresent"}},
+			found: true,
+		},
+		{
+			name:  "multiple types",
+			path:  []string{"replicas"},
+			want:  ValuesSchemaInfo{Title: "Replicas", Types: []string{"integer", "null"}, Default: float64(1)},
+			found: true,
+		},
+		{
+			name:  "items with allOf",
+			path:  []string{"ports", "0", "port"},
+			want:  ValuesSchemaInfo{Description: "The port", Types: []string{"integer"}},
+			found: true,
+		},
+		{
+			name:  "additional properties",
+			path:  []string{"labels", "app"},
+			want:  ValuesSchemaInfo{Description: "A label", Types: []string{"string"}},
+			found: true,
+		},
+		{
+			name:  "unknown",
+			path:  []string{"image", "unknown"},
+			found: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, found := GetValuesSchemaInfo(testValuesSchema, tt.path)
+			assert.Equal(t, tt.found, found)
+			if tt.found {
+				assert.Equal(t, tt.want, info)
+			}
+		})
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	errors, err := ValidateValues(testValuesSchema, map[string]any{
+		"image":    map[string]any{"pullPolicy": "Never"},
+		"replicas": "three",
+		"ports":    []any{map[string]any{"port": "http"}},
+	})
+	assert.NoError(t, err)
+
+	assert.Contains(t, errors, ValuesSchemaError{Path: []string{"image"}, Property: "repository", Message: "repository is required", Type: "required"})
+	assert.Contains(t, errors, ValuesSchemaError{Path: []string{"replicas"}, Message: "Invalid type. Expected: [integer,null], given: string", Type: "invalid_type"})
+	assert.Contains(t, errors, ValuesSchemaError{Path: []string{"ports", "0", "port"}, Message: "Invalid type. Expected: integer, given: string", Type: "invalid_type"})
+	assert.Contains(t, errors, ValuesSchemaError{
+		Path:    []string{"image", "pullPolicy"},
+		Message: `image.pullPolicy must be one of the following: "Always", "IfNotPresent"`,
+		Type:    "enum",
+	})
+}
+
+func TestSchemaFileIsReferencedInGeneratedSchema(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: withschema\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte("replicas: 1"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, ValuesSchemaFileName), testValuesSchema, 0o644))
+
+	chart := charts.NewChart(uri.File(tempDir), util.DefaultConfig.ValuesFilesConfig)
+	generated, err := CreateJSONSchemaForChart(chart, charts.NewChartStore(uri.File(tempDir), charts.NewChart, func(chart *charts.Chart) {}),
+		func(chart *charts.Chart) string { return "/" + chart.Name() })
+	assert.NoError(t, err)
+
+	definition := generated.schema.Definitions["withschema"]
+	assert.NotNil(t, definition)
+	assert.Contains(t, definition.AllOf, &Schema{Ref: string(uri.File(filepath.Join(tempDir, ValuesSchemaFileName)))})
+}
@@ -0,0 +1,192 @@
+package kubernetesschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// swaggerDocumented is implemented by the types of the kubernetes client libraries,
+// the descriptions are the ones of the OpenAPI schemas of the API server
+type swaggerDocumented interface {
+	SwaggerDoc() map[string]string
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+	bundledSchemasMutex sync.Mutex
+	bundledSchemas      = map[GroupVersionKind][]byte{}
+
+	// the types that are serialized differently than their go type
+	specialTypeSchemas = map[reflect.Type]map[string]any{
+		reflect.TypeOf(metav1.Time{}):          {"type": "string", "format": "date-time"},
+		reflect.TypeOf(metav1.MicroTime{}):     {"type": "string", "format": "date-time"},
+		reflect.TypeOf(metav1.Duration{}):      {"type": "string"},
+		reflect.TypeOf(metav1.FieldsV1{}):      {"type": "object"},
+		reflect.TypeOf(resource.Quantity{}):    {"type": []any{"string", "number"}},
+		reflect.TypeOf(intstr.IntOrString{}):   {"type": []any{"string", "integer"}},
+		reflect.TypeOf(runtime.RawExtension{}): {},
+		reflect.TypeOf(json.RawMessage{}):      {},
+		// base64 encoded, e.g. the data of secrets
+		reflect.TypeOf([]byte{}): {"type": "string"},
+	}
+)
+
+// GetBundledSchema returns the JSON schema of a built-in kind. It is generated from the types that are compiled
+// into the kubernetes client libraries (the same ones the rendered objects are validated with), so no network access
+// is needed. Like the strict schemas of yaml-language-server, unknown fields are not allowed.
+// Returns false if the kind is not built-in.
+func GetBundledSchema(kind GroupVersionKind) ([]byte, bool) {
+	bundledSchemasMutex.Lock()
+	defer bundledSchemasMutex.Unlock()
+	if schema, ok := bundledSchemas[kind]; ok {
+		return schema, true
+	}
+
+	object, err := scheme.Scheme.New(kind.toSchemaGroupVersionKind())
+	if err != nil {
+		return nil, false
+	}
+	schema := schemaForType(reflect.TypeOf(object), map[reflect.Type]bool{})
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		restrictToValue(properties, "apiVersion", kind.APIVersion)
+		restrictToValue(properties, "kind", kind.Kind)
+	}
+
+	result, err := json.Marshal(schema)
+	if err != nil {
+		logger.Error("Could not marshal the bundled schema", kind, err)
+		return nil, false
+	}
+	bundledSchemas[kind] = result
+	return result, true
+}
+
+func restrictToValue(properties map[string]any, name string, value string) {
+	if property, ok := properties[name].(map[string]any); ok {
+		property["enum"] = []any{value}
+	}
+}
+
+// schemaForType converts the go type to a JSON schema using the json tags of the fields,
+// types that are already converted are not converted again to avoid endless recursion
+func schemaForType(t reflect.Type, converting map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if schema, ok := specialTypeSchemas[t]; ok {
+		return copySchema(schema)
+	}
+	if reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		// serialized differently than the go type, e.g. the JSON of the default of a CRD
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem(), converting)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaForType(t.Elem(), converting)}
+	case reflect.Struct:
+		if converting[t] {
+			return map[string]any{"type": "object"}
+		}
+		converting[t] = true
+		defer delete(converting, t)
+
+		schema := map[string]any{"type": "object", "additionalProperties": false}
+		docs := getSwaggerDocs(t)
+		if description := docs[""]; description != "" {
+			schema["description"] = description
+		}
+		schema["properties"] = propertiesForStruct(t, docs, converting)
+		return schema
+	}
+	return map[string]any{}
+}
+
+// propertiesForStruct returns the properties of the fields of the struct,
+// the fields of inlined structs (e.g. metav1.TypeMeta) are added to the properties
+func propertiesForStruct(t reflect.Type, docs map[string]string, converting map[reflect.Type]bool) map[string]any {
+	properties := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, inline := getJSONName(field)
+		if inline {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() != reflect.Struct {
+				continue
+			}
+			for key, value := range propertiesForStruct(fieldType, getSwaggerDocs(fieldType), converting) {
+				properties[key] = value
+			}
+			continue
+		}
+		if name == "" || !field.IsExported() {
+			continue
+		}
+		property := schemaForType(field.Type, converting)
+		if description := docs[name]; description != "" {
+			property["description"] = description
+		}
+		properties[name] = property
+	}
+	return properties
+}
+
+// getJSONName returns the name of the field in the JSON object, an empty name for fields that are not serialized
+func getJSONName(field reflect.StructField) (name string, inline bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		if field.Anonymous {
+			return "", true
+		}
+		return field.Name, false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" && (field.Anonymous || strings.Contains(options, "inline")) {
+		return "", true
+	}
+	if name == "" {
+		return field.Name, false
+	}
+	return name, false
+}
+
+func getSwaggerDocs(t reflect.Type) map[string]string {
+	if documented, ok := reflect.New(t).Interface().(swaggerDocumented); ok {
+		return documented.SwaggerDoc()
+	}
+	return map[string]string{}
+}
+
+func copySchema(schema map[string]any) map[string]any {
+	result := make(map[string]any, len(schema))
+	for key, value := range schema {
+		result[key] = value
+	}
+	return result
+}
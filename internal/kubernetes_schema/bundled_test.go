@@ -0,0 +1,40 @@
+package kubernetesschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBundledSchema(t *testing.T) {
+	schema, ok := GetBundledSchema(GroupVersionKind{APIVersion: "apps/v1", Kind: "Deployment"})
+	assert.True(t, ok)
+
+	var root map[string]any
+	assert.NoError(t, json.Unmarshal(schema, &root))
+	get := func(path ...string) map[string]any {
+		current := root
+		for _, element := range path {
+			current = current[element].(map[string]any)
+		}
+		return current
+	}
+
+	assert.Equal(t, []any{"apps/v1"}, get("properties", "apiVersion")["enum"])
+	assert.Equal(t, []any{"Deployment"}, get("properties", "kind")["enum"])
+	assert.Equal(t, false, get("properties", "spec")["additionalProperties"])
+	replicas := get("properties", "spec", "properties", "replicas")
+	assert.Equal(t, "integer", replicas["type"])
+	assert.Contains(t, replicas["description"], "Number of desired pods.")
+	assert.Equal(t, map[string]any{"type": "string"}, get("properties", "metadata", "properties", "labels")["additionalProperties"])
+
+	podSpec := []string{"properties", "spec", "properties", "template", "properties", "spec"}
+	limits := get(append(podSpec, "properties", "containers", "items", "properties", "resources", "properties", "limits")...)
+	assert.Equal(t, map[string]any{"type": []any{"string", "number"}}, limits["additionalProperties"])
+	maxSurge := get("properties", "spec", "properties", "strategy", "properties", "rollingUpdate", "properties", "maxSurge")
+	assert.Equal(t, []any{"string", "integer"}, maxSurge["type"])
+
+	_, ok = GetBundledSchema(GroupVersionKind{APIVersion: "example.com/v1", Kind: "Unknown"})
+	assert.False(t, ok)
+}
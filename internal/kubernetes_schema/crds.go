@@ -0,0 +1,229 @@
+package kubernetesschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"hash/adler32"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"go.lsp.dev/uri"
+	"gopkg.in/yaml.v3"
+)
+
+type crdSchema struct {
+	kind   GroupVersionKind
+	schema map[string]any
+}
+
+type cachedCRDSchemas struct {
+	checksum uint32
+	schemas  map[GroupVersionKind][]byte
+}
+
+// CRDSchemaCache converts the CRDs of charts to JSON schemas,
+// the CRDs of a chart are only converted again if they changed
+type CRDSchemaCache struct {
+	mutex   sync.Mutex
+	schemas map[uri.URI]cachedCRDSchemas
+}
+
+func NewCRDSchemaCache() *CRDSchemaCache {
+	return &CRDSchemaCache{
+		schemas: map[uri.URI]cachedCRDSchemas{},
+	}
+}
+
+// Get returns the JSON schemas of the CRDs in the crds directory of the chart
+// and its dependencies by their kind
+func (c *CRDSchemaCache) Get(chart *charts.Chart) map[GroupVersionKind][]byte {
+	if c == nil || chart == nil || chart.HelmChart == nil {
+		return map[GroupVersionKind][]byte{}
+	}
+
+	crdData := [][]byte{}
+	for _, crd := range chart.HelmChart.CRDObjects() {
+		crdData = append(crdData, crd.File.Data)
+	}
+	checksum := adler32.Checksum(slices.Concat(crdData...))
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if cached, ok := c.schemas[chart.RootURI]; ok && cached.checksum == checksum {
+		return cached.schemas
+	}
+
+	schemas := map[GroupVersionKind][]byte{}
+	for _, data := range crdData {
+		for _, crdSchema := range getCRDSchemas(data) {
+			schema, err := json.Marshal(crdSchema.schema)
+			if err != nil {
+				logger.Error("Could not convert the schema of CRD", crdSchema.kind, err)
+				continue
+			}
+			schemas[crdSchema.kind] = schema
+		}
+	}
+
+	c.schemas[chart.RootURI] = cachedCRDSchemas{checksum: checksum, schemas: schemas}
+	return schemas
+}
+
+// getCRDSchemas converts the openAPIV3Schema of all versions of the CustomResourceDefinitions
+// in the data to JSON schemas. The apiVersion and kind of the schemas are restricted to the
+// values of the definition, so that a schema only matches its own kind.
+func getCRDSchemas(data []byte) []crdSchema {
+	result := []crdSchema{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		document := map[string]any{}
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		if err != nil {
+			logger.Debug("Could not parse CRD file", err)
+			return result
+		}
+		if document["kind"] != "CustomResourceDefinition" {
+			continue
+		}
+		result = append(result, getSchemasOfCRD(document)...)
+	}
+}
+
+func getSchemasOfCRD(crd map[string]any) []crdSchema {
+	spec, _ := crd["spec"].(map[string]any)
+	group, _ := spec["group"].(string)
+	names, _ := spec["names"].(map[string]any)
+	kind, _ := names["kind"].(string)
+	if group == "" || kind == "" {
+		return nil
+	}
+
+	// apiextensions.k8s.io/v1beta1 allowed a single schema for all versions
+	validation, _ := spec["validation"].(map[string]any)
+	commonSchema, _ := validation["openAPIV3Schema"].(map[string]any)
+
+	versionNames := []string{}
+	versionSchemas := map[string]map[string]any{}
+	if version, ok := spec["version"].(string); ok {
+		versionNames = append(versionNames, version)
+	}
+	versions, _ := spec["versions"].([]any)
+	for _, v := range versions {
+		version, _ := v.(map[string]any)
+		name, _ := version["name"].(string)
+		if name == "" {
+			continue
+		}
+		versionNames = append(versionNames, name)
+		versionSchema, _ := version["schema"].(map[string]any)
+		if openAPIV3Schema, ok := versionSchema["openAPIV3Schema"].(map[string]any); ok {
+			versionSchemas[name] = openAPIV3Schema
+		}
+	}
+
+	result := []crdSchema{}
+	seen := map[string]bool{}
+	for _, name := range versionNames {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		schema, ok := versionSchemas[name]
+		if !ok {
+			schema = commonSchema
+		}
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+
+		gvk := GroupVersionKind{APIVersion: group + "/" + name, Kind: kind}
+		result = append(result, crdSchema{kind: gvk, schema: restrictToKind(toStrictSchema(schema), gvk)})
+	}
+	return result
+}
+
+// restrictToKind returns a copy of the schema that requires the apiVersion and kind of the definition
+func restrictToKind(schema map[string]any, gvk GroupVersionKind) map[string]any {
+	result := map[string]any{}
+	for key, value := range schema {
+		result[key] = value
+	}
+
+	properties := map[string]any{}
+	if existing, ok := schema["properties"].(map[string]any); ok {
+		for key, value := range existing {
+			properties[key] = value
+		}
+	}
+	properties["apiVersion"] = map[string]any{"type": "string", "enum": []string{gvk.APIVersion}}
+	properties["kind"] = map[string]any{"type": "string", "enum": []string{gvk.Kind}}
+	if _, ok := properties["metadata"]; !ok {
+		properties["metadata"] = map[string]any{"type": "object"}
+	}
+	result["properties"] = properties
+
+	return result
+}
+
+// toStrictSchema returns a copy of the schema that does not allow fields which are not declared,
+// like the API server prunes them. Only the schema and the schemas of its properties and items are made strict,
+// the branches of allOf, anyOf, oneOf and not are left unchanged, because fields may be declared in another branch.
+// Objects that preserve unknown fields or combine schemas are left unchanged as well.
+func toStrictSchema(schema map[string]any) map[string]any {
+	result := map[string]any{}
+	for key, value := range schema {
+		switch key {
+		case "properties", "patternProperties":
+			properties, _ := value.(map[string]any)
+			strictProperties := map[string]any{}
+			for name, property := range properties {
+				strictProperties[name] = toStrictSubSchema(property)
+			}
+			result[key] = strictProperties
+		case "items", "additionalProperties":
+			result[key] = toStrictSubSchema(value)
+		default:
+			result[key] = value
+		}
+	}
+
+	_, hasProperties := result["properties"]
+	_, hasAdditionalProperties := result["additionalProperties"]
+	preserveUnknownFields, _ := result["x-kubernetes-preserve-unknown-fields"].(bool)
+	if hasProperties && !hasAdditionalProperties && !preserveUnknownFields && !combinesSchemas(result) {
+		result["additionalProperties"] = false
+	}
+	return result
+}
+
+func combinesSchemas(schema map[string]any) bool {
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if _, ok := schema[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// toStrictSubSchema converts a schema or a list of schemas, other values (e.g. booleans) are returned unchanged
+func toStrictSubSchema(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return toStrictSchema(v)
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			result = append(result, toStrictSubSchema(item))
+		}
+		return result
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+package kubernetesschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToStrictSchema(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"properties": map[string]any{"type": "object", "x-kubernetes-preserve-unknown-fields": true, "properties": map[string]any{}},
+			"items":      map[string]any{"type": "array", "items": map[string]any{"properties": map[string]any{}}},
+			"labels":     map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"combined": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"a": map[string]any{"type": "string"}},
+				"anyOf":      []any{map[string]any{"properties": map[string]any{"b": map[string]any{"type": "string"}}}},
+			},
+		},
+	}
+
+	result := toStrictSchema(schema)
+
+	properties := result["properties"].(map[string]any)
+	assert.Equal(t, false, result["additionalProperties"])
+	assert.NotContains(t, properties, "additionalProperties")
+	assert.NotContains(t, properties["properties"], "additionalProperties")
+	assert.Equal(t, false, properties["items"].(map[string]any)["items"].(map[string]any)["additionalProperties"])
+	assert.Equal(t, map[string]any{"type": "string"}, properties["labels"].(map[string]any)["additionalProperties"])
+	// the fields of a schema with branches may be declared in the branches
+	combined := properties["combined"].(map[string]any)
+	assert.NotContains(t, combined, "additionalProperties")
+	assert.NotContains(t, combined["anyOf"].([]any)[0], "additionalProperties")
+	assert.NotContains(t, schema, "additionalProperties")
+}
+
+func TestGetCRDSchemas(t *testing.T) {
+	v1beta1CRD := `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+spec:
+  group: example.com
+  names:
+    kind: Gadget
+  versions:
+    - name: v1alpha1
+    - name: v1beta1
+  validation:
+    openAPIV3Schema:
+      type: object
+      properties:
+        spec:
+          type: object
+`
+	data := []byte(testCRD + "---\napiVersion: v1\nkind: ConfigMap\n---\n" + v1beta1CRD)
+
+	result := getCRDSchemas(data)
+
+	kinds := []GroupVersionKind{}
+	for _, crdSchema := range result {
+		kinds = append(kinds, crdSchema.kind)
+		assert.Contains(t, crdSchema.schema["properties"], "spec")
+		assert.Equal(t, "object", crdSchema.schema["type"])
+	}
+	spec := result[0].schema["properties"].(map[string]any)["spec"].(map[string]any)
+	assert.Equal(t, false, spec["additionalProperties"])
+	assert.Contains(t, result[0].schema["properties"], "metadata")
+
+	assert.Equal(t, []GroupVersionKind{
+		{APIVersion: "example.com/v1", Kind: "Widget"},
+		{APIVersion: "example.com/v1alpha1", Kind: "Gadget"},
+		{APIVersion: "example.com/v1beta1", Kind: "Gadget"},
+	}, kinds)
+}
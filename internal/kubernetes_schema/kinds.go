@@ -0,0 +1,88 @@
+package kubernetesschema
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var topLevelFieldRegex = regexp.MustCompile(`^(apiVersion|kind):(.*)$`)
+
+// GroupVersionKind identifies the kind of a Kubernetes object by its apiVersion and kind fields
+type GroupVersionKind struct {
+	APIVersion string
+	Kind       string
+}
+
+func (g GroupVersionKind) toSchemaGroupVersionKind() schema.GroupVersionKind {
+	return schema.FromAPIVersionAndKind(g.APIVersion, g.Kind)
+}
+
+// IsBuiltIn returns true if the kind is compiled into the kubernetes client libraries
+func (g GroupVersionKind) IsBuiltIn() bool {
+	return scheme.Scheme.Recognizes(g.toSchemaGroupVersionKind())
+}
+
+// GetGroupVersionKinds returns the kinds of all YAML documents of the text.
+// Only the top level apiVersion and kind lines are read, so that the text of a template
+// with the actions removed can be used, even if it is not valid YAML.
+// The result is not complete if a document has no literal apiVersion or kind
+// (e.g. because they are set by a template action) or has more than one of them.
+func GetGroupVersionKinds(text string) (kinds []GroupVersionKind, complete bool) {
+	kinds = []GroupVersionKind{}
+	for _, document := range splitDocuments(text) {
+		kind, isEmpty, ok := getGroupVersionKind(document)
+		if isEmpty {
+			continue
+		}
+		if !ok {
+			return kinds, false
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, true
+}
+
+func splitDocuments(text string) [][]string {
+	documents := [][]string{{}}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "---") {
+			documents = append(documents, []string{})
+			continue
+		}
+		documents[len(documents)-1] = append(documents[len(documents)-1], line)
+	}
+	return documents
+}
+
+func getGroupVersionKind(lines []string) (kind GroupVersionKind, isEmpty bool, ok bool) {
+	isEmpty = true
+	values := map[string][]string{}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		isEmpty = false
+		if match := topLevelFieldRegex.FindStringSubmatch(strings.TrimRight(line, " \t\r")); match != nil {
+			values[match[1]] = append(values[match[1]], scalarValue(match[2]))
+		}
+	}
+
+	if len(values["apiVersion"]) != 1 || len(values["kind"]) != 1 {
+		return kind, isEmpty, false
+	}
+	kind = GroupVersionKind{APIVersion: values["apiVersion"][0], Kind: values["kind"][0]}
+	return kind, isEmpty, kind.APIVersion != "" && kind.Kind != ""
+}
+
+// scalarValue returns the value of a plain or quoted scalar without a trailing comment
+func scalarValue(value string) string {
+	value = strings.TrimSpace(value)
+	if index := strings.Index(value, " #"); index >= 0 {
+		value = strings.TrimSpace(value[:index])
+	}
+	return strings.Trim(value, `"'`)
+}
@@ -0,0 +1,69 @@
+package kubernetesschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGroupVersionKinds(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		text             string
+		expected         []GroupVersionKind
+		expectedComplete bool
+	}{
+		{
+			desc:             "single document",
+			text:             "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: test\n",
+			expected:         []GroupVersionKind{{APIVersion: "apps/v1", Kind: "Deployment"}},
+			expectedComplete: true,
+		},
+		{
+			desc: "multiple documents with empty documents",
+			text: "---\napiVersion: v1\nkind: Service\n---\n# only a comment\n---\napiVersion: example.com/v1\nkind: Custom\n",
+			expected: []GroupVersionKind{
+				{APIVersion: "v1", Kind: "Service"},
+				{APIVersion: "example.com/v1", Kind: "Custom"},
+			},
+			expectedComplete: true,
+		},
+		{
+			desc:             "apiVersion set by a removed action",
+			text:             "apiVersion:                                \nkind: Deployment\n",
+			expected:         []GroupVersionKind{},
+			expectedComplete: false,
+		},
+		{
+			desc:             "invalid yaml with quoted values and comments",
+			text:             "apiVersion: \"v1\" # comment\nkind: 'Service'\n  - broken: [",
+			expected:         []GroupVersionKind{{APIVersion: "v1", Kind: "Service"}},
+			expectedComplete: true,
+		},
+		{
+			desc:             "apiVersion in branches of an if action",
+			text:             "\napiVersion: networking.k8s.io/v1\n\napiVersion: extensions/v1beta1\n\nkind: Ingress\n",
+			expected:         []GroupVersionKind{},
+			expectedComplete: false,
+		},
+		{
+			desc:             "nested apiVersion and kind are ignored",
+			text:             "spec:\n  apiVersion: v1\n  kind: Service\n",
+			expected:         []GroupVersionKind{},
+			expectedComplete: false,
+		},
+		{
+			desc:             "empty text",
+			text:             "   \n",
+			expected:         []GroupVersionKind{},
+			expectedComplete: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result, complete := GetGroupVersionKinds(tt.text)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expectedComplete, complete)
+		})
+	}
+}
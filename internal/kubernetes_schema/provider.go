@@ -0,0 +1,140 @@
+package kubernetesschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/log"
+	"go.lsp.dev/uri"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+var logger = log.GetLogger()
+
+// KubernetesSchemaBaseURL is the location of the JSON schemas of the built-in kinds for all Kubernetes versions.
+// It is the source yaml-language-server uses for its kubernetes schema.
+var KubernetesSchemaBaseURL = "https://raw.githubusercontent.com/yannh/kubernetes-json-schema/master"
+
+// DefaultKubernetesVersion is used if render.kubeVersion is not set,
+// it matches the kubernetes client libraries helm-ls is built with
+const DefaultKubernetesVersion = "v1.32.1"
+
+// SchemaProvider selects the JSON schemas for Kubernetes objects by their apiVersion and kind
+type SchemaProvider struct {
+	schemaFilesDir string
+	crdSchemas     *CRDSchemaCache
+}
+
+// NewSchemaProvider creates a provider that converts the CRDs of the charts with the given cache
+func NewSchemaProvider(crdSchemas *CRDSchemaCache) (*SchemaProvider, error) {
+	schemaFilesDir := filepath.Join(os.TempDir(), "helm-ls", "kubernetes")
+
+	err := os.MkdirAll(schemaFilesDir, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema files directory: %w", err)
+	}
+
+	return &SchemaProvider{
+		schemaFilesDir: schemaFilesDir,
+		crdSchemas:     crdSchemas,
+	}, nil
+}
+
+// GetKubernetesSchemaURL returns the location of the schemas of the built-in kinds for the Kubernetes version
+// of render.kubeVersion, versions without a patch version use the first patch version
+func GetKubernetesSchemaURL(kubeVersion string) string {
+	version := DefaultKubernetesVersion
+	if kubeVersion != "" {
+		parsed, err := chartutil.ParseKubeVersion(kubeVersion)
+		if err != nil {
+			logger.Error("Invalid kubeVersion, using the schemas of the default version", kubeVersion, err)
+		} else {
+			version = parsed.Version
+		}
+	}
+	return fmt.Sprintf("%s/%s-standalone-strict", KubernetesSchemaBaseURL, version)
+}
+
+// GetSchemaForKinds returns the schema for a file containing objects of the given kinds.
+// Built-in kinds get the schema of the Kubernetes version of render.kubeVersion.
+// The CRDs of the chart are used for custom kinds, kinds that are neither built-in
+// nor defined by the chart only get their apiVersion and kind validated.
+// Files with objects of different kinds get a schema that accepts any of them.
+// An empty URI is returned if there are no kinds.
+func (p *SchemaProvider) GetSchemaForKinds(chart *charts.Chart, kinds []GroupVersionKind, kubeVersion string) (uri.URI, error) {
+	crdSchemas := p.crdSchemas.Get(chart)
+
+	refs := []uri.URI{}
+	for _, kind := range kinds {
+		ref, err := p.getSchemaForKind(kind, crdSchemas, kubeVersion)
+		if err != nil {
+			return "", err
+		}
+		if !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+
+	switch len(refs) {
+	case 0:
+		return "", nil
+	case 1:
+		return refs[0], nil
+	}
+
+	anyOf := []any{}
+	for _, ref := range refs {
+		anyOf = append(anyOf, map[string]any{"$ref": string(ref)})
+	}
+	return p.writeSchema(map[string]any{"anyOf": anyOf}, "multiple-kinds")
+}
+
+func (p *SchemaProvider) getSchemaForKind(kind GroupVersionKind, crdSchemas map[GroupVersionKind][]byte, kubeVersion string) (uri.URI, error) {
+	if schema, ok := crdSchemas[kind]; ok {
+		return p.writeSchemaBytes(schema, kind.Kind)
+	}
+	if kind.IsBuiltIn() {
+		return GetBuiltInSchemaURL(kind, kubeVersion), nil
+	}
+	return p.writeSchema(restrictToKind(map[string]any{"type": "object"}, kind), kind.Kind)
+}
+
+// GetBuiltInSchemaURL returns the URL of the schema of a built-in kind for the Kubernetes version,
+// the files are named after the kind, the first part of the group and the version
+func GetBuiltInSchemaURL(kind GroupVersionKind, kubeVersion string) uri.URI {
+	gvk := kind.toSchemaGroupVersionKind()
+	name := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		name += "-" + strings.Split(gvk.Group, ".")[0]
+	}
+	name += "-" + gvk.Version
+	return uri.URI(fmt.Sprintf("%s/%s.json", GetKubernetesSchemaURL(kubeVersion), name))
+}
+
+// writeSchema writes the schema to a file that is named after its content
+func (p *SchemaProvider) writeSchema(schema map[string]any, name string) (uri.URI, error) {
+	bytes, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return p.writeSchemaBytes(bytes, name)
+}
+
+// writeSchemaBytes writes the schema to a file that is named after its content,
+// existing files are not written again
+func (p *SchemaProvider) writeSchemaBytes(schema []byte, name string) (uri.URI, error) {
+	path := filepath.Join(p.schemaFilesDir, fmt.Sprintf("%d-%s.json", adler32.Checksum(schema), strings.ToLower(name)))
+	if _, err := os.Stat(path); err == nil {
+		return uri.File(path), nil
+	}
+	if err := os.WriteFile(path, schema, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write schema to file: %w", err)
+	}
+	return uri.File(path), nil
+}
@@ -0,0 +1,122 @@
+package kubernetesschema
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const testCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: integer
+`
+
+var defaultSchemaURL = GetKubernetesSchemaURL("")
+
+func readSchema(t *testing.T, schemaURI uri.URI) map[string]any {
+	t.Helper()
+	content, err := os.ReadFile(schemaURI.Filename())
+	assert.NoError(t, err)
+	schema := map[string]any{}
+	assert.NoError(t, json.Unmarshal(content, &schema))
+	return schema
+}
+
+func TestGetSchemaForKinds(t *testing.T) {
+	sut := &SchemaProvider{
+		schemaFilesDir: t.TempDir(),
+		crdSchemas:     NewCRDSchemaCache(),
+	}
+	testChart := &charts.Chart{
+		RootURI: uri.File("/chart"),
+		HelmChart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "chart"},
+			Files:    []*chart.File{{Name: "crds/widget.yaml", Data: []byte(testCRD)}},
+		},
+	}
+
+	result, err := sut.GetSchemaForKinds(testChart, []GroupVersionKind{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, uri.URI(""), result)
+
+	result, err = sut.GetSchemaForKinds(testChart, []GroupVersionKind{{APIVersion: "apps/v1", Kind: "Deployment"}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, uri.URI(defaultSchemaURL+"/deployment-apps-v1.json"), result)
+
+	result, err = sut.GetSchemaForKinds(testChart, []GroupVersionKind{{APIVersion: "v1", Kind: "Service"}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, uri.URI(defaultSchemaURL+"/service-v1.json"), result)
+
+	result, err = sut.GetSchemaForKinds(testChart, []GroupVersionKind{{APIVersion: "example.com/v1", Kind: "Widget"}}, "")
+	assert.NoError(t, err)
+	schema := readSchema(t, result)
+	properties := schema["properties"].(map[string]any)
+	assert.Contains(t, properties, "spec")
+	assert.Equal(t, []any{"example.com/v1"}, properties["apiVersion"].(map[string]any)["enum"])
+	assert.Equal(t, []any{"Widget"}, properties["kind"].(map[string]any)["enum"])
+
+	result, err = sut.GetSchemaForKinds(testChart, []GroupVersionKind{{APIVersion: "unknown.io/v1", Kind: "Unknown"}}, "")
+	assert.NoError(t, err)
+	schema = readSchema(t, result)
+	assert.Equal(t, []any{"Unknown"}, schema["properties"].(map[string]any)["kind"].(map[string]any)["enum"])
+
+	result, err = sut.GetSchemaForKinds(testChart, []GroupVersionKind{
+		{APIVersion: "v1", Kind: "Service"},
+		{APIVersion: "v1", Kind: "Service"},
+		{APIVersion: "apps/v1", Kind: "Deployment"},
+	}, "")
+	assert.NoError(t, err)
+	schema = readSchema(t, result)
+	assert.Equal(t, []any{
+		map[string]any{"$ref": defaultSchemaURL + "/service-v1.json"},
+		map[string]any{"$ref": defaultSchemaURL + "/deployment-apps-v1.json"},
+	}, schema["anyOf"])
+}
+
+func TestGetKubernetesSchemaURL(t *testing.T) {
+	assert.Equal(t, KubernetesSchemaBaseURL+"/v1.32.1-standalone-strict", GetKubernetesSchemaURL(""))
+	assert.Equal(t, KubernetesSchemaBaseURL+"/v1.29.0-standalone-strict", GetKubernetesSchemaURL("1.29"))
+	assert.Equal(t, KubernetesSchemaBaseURL+"/v1.30.2-standalone-strict", GetKubernetesSchemaURL("v1.30.2"))
+	assert.Equal(t, KubernetesSchemaBaseURL+"/v1.32.1-standalone-strict", GetKubernetesSchemaURL("invalid"))
+
+	sut := &SchemaProvider{schemaFilesDir: t.TempDir(), crdSchemas: NewCRDSchemaCache()}
+	result, err := sut.GetSchemaForKinds(nil, []GroupVersionKind{{APIVersion: "v1", Kind: "Service"}}, "1.29")
+	assert.NoError(t, err)
+	assert.Equal(t, uri.URI(KubernetesSchemaBaseURL+"/v1.29.0-standalone-strict/service-v1.json"), result)
+}
+
+func TestGetSchemaForKindsWithoutHelmChart(t *testing.T) {
+	sut := &SchemaProvider{
+		schemaFilesDir: t.TempDir(),
+		crdSchemas:     NewCRDSchemaCache(),
+	}
+
+	result, err := sut.GetSchemaForKinds(nil, []GroupVersionKind{{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, uri.URI(defaultSchemaURL+"/ingress-networking-v1.json"), result)
+}
@@ -0,0 +1,135 @@
+package languagefeatures
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/symboltable"
+	lsp "go.lsp.dev/protocol"
+)
+
+// defineDot is the inferred dot of a define, it is either a template context
+// or a dict literal (e.g. (dict "ctx" $ "svc" .Values.service)) with dots as values.
+// The elements of a list literal (e.g. (list $ .Values.service)) are stored in the dict
+// by their index element (e.g. [1]), they are accessed with index . 1
+type defineDot struct {
+	templateContext symboltable.TemplateContext
+	dict            map[string]defineDot
+	list            bool
+	// chart is the chart of the call site if it is not the chart of the define,
+	// e.g. the parent chart for a define of a library chart
+	chart *charts.Chart
+}
+
+func (d defineDot) isDict() bool {
+	return d.dict != nil
+}
+
+// resolve applies the template context to the dot, e.g. .svc.port with the dot
+// (dict "svc" .Values.service) is resolved to Values.service.port.
+// Returns false if the dot is a dict without a matching key.
+func (d defineDot) resolve(templateContext symboltable.TemplateContext) (defineDot, bool) {
+	if !d.isDict() {
+		result := d.templateContext.Copy()
+		for _, element := range templateContext {
+			if symboltable.IsIndexElement(element) && len(result) > 0 {
+				// an element of a list in the values, e.g. index . 1 with the dot .Values.list
+				result = result.AppendSuffix("[]")
+				continue
+			}
+			result = append(result, element)
+		}
+		return defineDot{templateContext: result, chart: d.chart}, true
+	}
+	if len(templateContext) == 0 {
+		return d, true
+	}
+	value, ok := d.dict[templateContext[0]]
+	if !ok {
+		return defineDot{}, false
+	}
+	return value.resolve(templateContext.Tail())
+}
+
+// getDictKeyCompletions returns the keys of dicts that are passed to the surrounding define
+// and match the template context of the node without its last (incomplete) element
+func (f *GenericTemplateContextFeature) getDictKeyCompletions() []lsp.CompletionItem {
+	templateContext, err := f.Document.SymbolTable.GetTemplateContext(f.Node.Range())
+	if err != nil {
+		return []lsp.CompletionItem{}
+	}
+	if len(templateContext) > 0 {
+		templateContext = templateContext[:len(templateContext)-1]
+	}
+
+	items := []lsp.CompletionItem{}
+	keys := map[string]bool{}
+	for _, dot := range f.getDotsForNode() {
+		resolved, ok := dot.resolve(templateContext)
+		if !ok || !resolved.isDict() || resolved.list {
+			continue
+		}
+		for key, value := range resolved.dict {
+			if keys[key] {
+				continue
+			}
+			keys[key] = true
+			items = append(items, lsp.CompletionItem{
+				Label:      key,
+				InsertText: key,
+				Kind:       lsp.CompletionItemKindField,
+				Detail:     fmt.Sprintf("dict key passed to the template: %s", value.format()),
+			})
+		}
+	}
+	return items
+}
+
+func (d defineDot) format() string {
+	if d.list {
+		return "list"
+	}
+	if d.isDict() {
+		return "dict"
+	}
+	return "." + d.templateContext.Format()
+}
+
+// apply returns the dot of the called define for a call within the context of this dot
+func (d defineDot) apply(includeCall symboltable.IncludeCall) (defineDot, bool) {
+	if includeCall.IsList() {
+		list := map[string]defineDot{}
+		for i, value := range includeCall.ListArgument {
+			if value == nil {
+				continue
+			}
+			if resolved, ok := d.resolve(value); ok {
+				list[symboltable.IndexElement(strconv.Itoa(i))] = resolved
+			}
+		}
+		return defineDot{dict: list, list: true, chart: d.chart}, true
+	}
+	if !includeCall.IsDict() {
+		return d.resolve(includeCall.ArgumentContext)
+	}
+
+	dict := map[string]defineDot{}
+	for key, value := range includeCall.DictArgument {
+		if resolved, ok := d.resolve(value); ok {
+			dict[key] = resolved
+		}
+	}
+	return defineDot{dict: dict, chart: d.chart}, true
+}
+
+func appendUniqueDot(dots []defineDot, dot defineDot) []defineDot {
+	// fmt prints maps sorted by key, so the representation is stable
+	representation := fmt.Sprint(dot)
+	for _, existing := range dots {
+		if fmt.Sprint(existing) == representation {
+			return dots
+		}
+	}
+	return append(dots, dot)
+}
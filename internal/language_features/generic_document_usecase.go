@@ -3,6 +3,7 @@ package languagefeatures
 import (
 	"github.com/mrjosh/helm-ls/internal/charts"
 	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
@@ -15,8 +16,22 @@ type GenericDocumentUseCase struct {
 	NodeType       string
 	ParentNode     *sitter.Node
 	ParentNodeType string
+	RenderConfig   util.RenderConfig
 }
 
 func (u *GenericDocumentUseCase) NodeContent() string {
 	return u.Node.Content([]byte(u.Document.Content))
 }
+
+// WithNode returns a copy of the use case for another node of the same document
+func (u *GenericDocumentUseCase) WithNode(node *sitter.Node) *GenericDocumentUseCase {
+	result := *u
+	result.Node = node
+	result.NodeType = node.Type()
+	result.ParentNode = node.Parent()
+	result.ParentNodeType = ""
+	if result.ParentNode != nil {
+		result.ParentNodeType = result.ParentNode.Type()
+	}
+	return &result
+}
@@ -2,7 +2,11 @@ package languagefeatures
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/mrjosh/helm-ls/internal/charts"
 	helmdocs "github.com/mrjosh/helm-ls/internal/documentation/helm"
 	"github.com/mrjosh/helm-ls/internal/lsp/symboltable"
 	"github.com/mrjosh/helm-ls/internal/util"
@@ -13,8 +17,134 @@ type GenericTemplateContextFeature struct {
 	*GenericDocumentUseCase
 }
 
+// getTemplateContext returns the first template context of the node, see getTemplateContexts
 func (f *GenericTemplateContextFeature) getTemplateContext() (symboltable.TemplateContext, error) {
-	return f.GenericDocumentUseCase.Document.SymbolTable.GetTemplateContext(f.Node.Range())
+	templateContexts, err := f.getTemplateContexts()
+	if err != nil || len(templateContexts) == 0 {
+		return symboltable.TemplateContext{}, err
+	}
+	return templateContexts[0], nil
+}
+
+// getTemplateContexts returns the template contexts of the node.
+// Inside of a define the dot is inferred from the include and template calls of the define,
+// there is one template context for each distinct argument that is passed to the define.
+func (f *GenericTemplateContextFeature) getTemplateContexts() ([]symboltable.TemplateContext, error) {
+	dots, err := f.getResolvedDots()
+	result := []symboltable.TemplateContext{}
+	for _, dot := range dots {
+		result = append(result, dot.templateContext)
+	}
+	return result, err
+}
+
+// getResolvedDots returns the template contexts of the node like getTemplateContexts
+// together with the charts of the call sites they were inferred from
+func (f *GenericTemplateContextFeature) getResolvedDots() ([]defineDot, error) {
+	templateContext, err := f.Document.SymbolTable.GetTemplateContext(f.Node.Range())
+	if err != nil {
+		return []defineDot{{templateContext: templateContext}}, err
+	}
+	return f.resolveDots(templateContext), nil
+}
+
+// resolveDots resolves the template context with each of the dots of the surrounding define
+func (f *GenericTemplateContextFeature) resolveDots(templateContext symboltable.TemplateContext) []defineDot {
+	dots := f.getDotsForNode()
+	if len(dots) == 0 {
+		// outside of defines or the define is not called anywhere, the dot is assumed to be the root context
+		return []defineDot{{templateContext: templateContext}}
+	}
+
+	result := []defineDot{}
+	for _, dot := range dots {
+		resolved, ok := dot.resolve(templateContext)
+		if ok && !resolved.isDict() && !slices.ContainsFunc(result, func(existing defineDot) bool {
+			return existing.chart == resolved.chart && slices.Equal(existing.templateContext, resolved.templateContext)
+		}) {
+			result = append(result, resolved)
+		}
+	}
+	return result
+}
+
+// withChartOfDot returns a copy of the feature that uses the chart of the call site of the dot
+// for the values and metadata, e.g. the parent chart for a define of a library chart
+func (f *GenericTemplateContextFeature) withChartOfDot(dot defineDot) *GenericTemplateContextFeature {
+	if dot.chart == nil {
+		return f
+	}
+	useCase := *f.GenericDocumentUseCase
+	useCase.Chart = dot.chart
+	return &GenericTemplateContextFeature{&useCase}
+}
+
+func (f *GenericTemplateContextFeature) getDotsForNode() []defineDot {
+	if f.DocumentStore == nil {
+		return []defineDot{}
+	}
+	defineName, ok := f.Document.SymbolTable.GetIncludeDefinitionForRange(f.Node.Range())
+	if !ok {
+		return []defineDot{}
+	}
+	return f.getDotsForDefine(defineName, map[string]bool{})
+}
+
+// getDotsForDefine returns the dots that are passed to the define by all include and template calls
+// in the open and loaded template documents of the chart, its parent charts and their dependencies
+func (f *GenericTemplateContextFeature) getDotsForDefine(defineName string, visited map[string]bool) []defineDot {
+	if visited[defineName] {
+		return []defineDot{}
+	}
+	visited[defineName] = true
+	defer delete(visited, defineName)
+
+	result := []defineDot{}
+	for _, doc := range f.DocumentStore.GetAllTemplateDocs() {
+		if doc.SymbolTable == nil || !f.isInChart(doc.Path) {
+			continue
+		}
+		for _, includeCall := range doc.SymbolTable.GetIncludeCallContexts(defineName) {
+			callerDots := []defineDot{{chart: f.getCallSiteChart(doc.URI)}}
+			if callerDefine, ok := doc.SymbolTable.GetIncludeDefinitionForRange(includeCall.Range); ok {
+				// the call is inside of another define, its dot is resolved recursively
+				if visited[callerDefine] {
+					continue
+				}
+				if dots := f.getDotsForDefine(callerDefine, visited); len(dots) > 0 {
+					callerDots = dots
+				}
+			}
+			for _, callerDot := range callerDots {
+				if dot, ok := callerDot.apply(includeCall); ok {
+					result = appendUniqueDot(result, dot)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// isInChart returns true if the path is inside of the directory of the top chart of the chart,
+// so that the call sites of the parent charts are used for defines of library and dependency charts
+func (f *GenericTemplateContextFeature) isInChart(path string) bool {
+	if f.Chart == nil {
+		return true
+	}
+	return strings.HasPrefix(path, f.Chart.GetTopChartRootURI().Filename()+string(filepath.Separator))
+}
+
+// getCallSiteChart returns the chart of the document if it is not the chart of the feature,
+// e.g. the parent chart that calls a define of a library chart, otherwise nil
+func (f *GenericTemplateContextFeature) getCallSiteChart(docURI lsp.DocumentURI) *charts.Chart {
+	if f.Chart == nil || f.ChartStore == nil {
+		return nil
+	}
+	chart, err := f.ChartStore.GetChartForDoc(docURI)
+	if err != nil || chart == nil || chart.RootURI == f.Chart.RootURI {
+		return nil
+	}
+	return chart
 }
 
 func (f *GenericTemplateContextFeature) getReferencesFromSymbolTable(templateContext symboltable.TemplateContext) []lsp.Location {
@@ -2,13 +2,17 @@ package languagefeatures
 
 import (
 	"fmt"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 
 	lsp "go.lsp.dev/protocol"
 
 	"github.com/mrjosh/helm-ls/internal/charts"
 	helmdocs "github.com/mrjosh/helm-ls/internal/documentation/helm"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	"github.com/mrjosh/helm-ls/internal/jsonschema"
 	"github.com/mrjosh/helm-ls/internal/lsp/symboltable"
 	"github.com/mrjosh/helm-ls/internal/protocol"
 	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
@@ -21,36 +25,64 @@ type TemplateContextFeature struct {
 }
 
 func NewTemplateContextFeature(genericDocumentUseCase *GenericDocumentUseCase) *TemplateContextFeature {
+	if genericDocumentUseCase.ParentNodeType == gotemplate.NodeTypeInterpretedStringLiteral {
+		// the cursor is at the quotes of a key, e.g. index .Values "^"
+		genericDocumentUseCase = genericDocumentUseCase.WithNode(genericDocumentUseCase.ParentNode)
+	}
 	return &TemplateContextFeature{
 		GenericTemplateContextFeature: &GenericTemplateContextFeature{genericDocumentUseCase},
 	}
 }
 
+// withChartOfDot returns a copy of the feature that uses the chart of the call site of the dot
+func (f *TemplateContextFeature) withChartOfDot(dot defineDot) *TemplateContextFeature {
+	return &TemplateContextFeature{f.GenericTemplateContextFeature.withChartOfDot(dot)}
+}
+
 func (f *TemplateContextFeature) AppropriateForNode() bool {
 	if f.NodeType == gotemplate.NodeTypeDot || f.NodeType == gotemplate.NodeTypeDotSymbol {
 		return true
 	}
+	if f.NodeType == gotemplate.NodeTypeInterpretedStringLiteral {
+		// keys of index, get and dig calls
+		return f.Document.SymbolTable.HasTemplateContext(f.Node.Range())
+	}
 	return (f.ParentNodeType == gotemplate.NodeTypeField && f.NodeType == gotemplate.NodeTypeIdentifier) ||
 		f.NodeType == gotemplate.NodeTypeFieldIdentifier ||
 		f.NodeType == gotemplate.NodeTypeField
 }
 
 func (f *TemplateContextFeature) References() (result []lsp.Location, err error) {
-	templateContext, err := f.getTemplateContext()
-	if err != nil || len(templateContext) == 0 {
+	dots, err := f.getResolvedDots()
+	if err != nil {
 		return []lsp.Location{}, err
 	}
 
-	locations := f.getReferencesFromSymbolTable(templateContext)
-	return append(locations, f.getDefinitionLocations(templateContext)...), nil
+	locations := []lsp.Location{}
+	for _, dot := range dots {
+		if len(dot.templateContext) == 0 {
+			continue
+		}
+		locations = append(locations, f.getReferencesFromSymbolTable(dot.templateContext)...)
+		locations = append(locations, f.withChartOfDot(dot).getDefinitionLocations(dot.templateContext)...)
+	}
+	return locations, nil
 }
 
 func (f *TemplateContextFeature) Definition() (result []lsp.Location, err error) {
-	templateContext, err := f.getTemplateContext()
-	if err != nil || len(templateContext) == 0 {
+	dots, err := f.getResolvedDots()
+	if err != nil {
 		return []lsp.Location{}, err
 	}
-	return f.getDefinitionLocations(templateContext), nil
+
+	locations := []lsp.Location{}
+	for _, dot := range dots {
+		if len(dot.templateContext) == 0 {
+			continue
+		}
+		locations = append(locations, f.withChartOfDot(dot).getDefinitionLocations(dot.templateContext)...)
+	}
+	return locations, nil
 }
 
 func (f *TemplateContextFeature) getDefinitionLocations(templateContext symboltable.TemplateContext) []lsp.Location {
@@ -76,11 +108,43 @@ func (f *TemplateContextFeature) getDefinitionLocations(templateContext symbolta
 }
 
 func (f *TemplateContextFeature) Hover() (string, error) {
-	templateContext, err := f.getTemplateContext()
-	if err != nil || len(templateContext) == 0 {
+	dots, err := f.getResolvedDots()
+	if err != nil {
+		// fields of variables with a type other than a template context, e.g. $d.key for {{ $d := dict "key" 1 }}
+		if valueType, typeErr := f.Document.SymbolTable.GetValueType(f.Node.Range()); typeErr == nil {
+			return formatValueType(valueType), nil
+		}
 		return "", err
 	}
+	if len(dots) == 1 {
+		if len(dots[0].templateContext) == 0 {
+			return "", nil
+		}
+		return f.withChartOfDot(dots[0]).hoverForTemplateContext(dots[0].templateContext)
+	}
+
+	// the dot of a define is called with different contexts, the hovers of all of them are combined
+	results := []string{}
+	for _, dot := range dots {
+		if len(dot.templateContext) == 0 {
+			continue
+		}
+		result, hoverErr := f.withChartOfDot(dot).hoverForTemplateContext(dot.templateContext)
+		if hoverErr != nil {
+			err = hoverErr
+			continue
+		}
+		if result != "" && !slices.Contains(results, result) {
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 {
+		return "", err
+	}
+	return strings.Join(results, "\n"), nil
+}
 
+func (f *TemplateContextFeature) hoverForTemplateContext(templateContext symboltable.TemplateContext) (string, error) {
 	switch templateContext[0] {
 	case "Values":
 		return f.valuesHover(templateContext.Tail())
@@ -88,21 +152,39 @@ func (f *TemplateContextFeature) Hover() (string, error) {
 		docs, err := f.builtInOjectDocsLookup(templateContext.Tail().Format(), helmdocs.BuiltInOjectVals[templateContext[0]])
 		value := f.getMetadataField(&f.Chart.ChartMetadata.Metadata, docs.Name)
 		return fmt.Sprintf("%s\n\n%s\n", docs.Doc, value), err
-	case "Release", "Files", "Capabilities", "Template":
+	case "Release", "Capabilities":
+		docs, err := f.builtInOjectDocsLookup(templateContext.Tail().Format(), helmdocs.BuiltInOjectVals[templateContext[0]])
+		if err != nil {
+			return docs.Doc, err
+		}
+		// the value that is used for rendering and linting, e.g. the configured kubeVersion
+		value, valueErr := helmrender.GetBuiltInObjectValue(f.RenderConfig, templateContext.Format())
+		if valueErr != nil || value == "" {
+			return docs.Doc, nil
+		}
+		return fmt.Sprintf("%s\n\n%s\n", docs.Doc, value), nil
+	case "Files", "Template":
 		docs, err := f.builtInOjectDocsLookup(templateContext.Tail().Format(), helmdocs.BuiltInOjectVals[templateContext[0]])
 		return docs.Doc, err
 	}
 
-	return templateContext.Format(), err
+	return templateContext.Format(), nil
 }
 
 func (f *TemplateContextFeature) valuesHover(templateContext symboltable.TemplateContext) (string, error) {
 	var (
-		valuesFiles  = f.Chart.ResolveValueFiles(templateContext, f.ChartStore)
-		hoverResults = protocol.HoverResultsWithFiles{}
+		valuesFiles   = f.Chart.ResolveValueFiles(templateContext, f.ChartStore)
+		hoverResults  = protocol.HoverResultsWithFiles{}
+		profileHovers = []string{}
 	)
 	for _, valuesFiles := range valuesFiles {
-		for _, valuesFile := range valuesFiles.ValuesFiles.AllValuesFiles() {
+		if valuesFiles.ValuesFiles.HasActiveProfile() {
+			if hover, ok := f.activeProfileHover(valuesFiles); ok {
+				profileHovers = append(profileHovers, hover)
+			}
+			continue
+		}
+		for _, valuesFile := range valuesFiles.ValuesFiles.ActiveValuesFiles() {
 			logger.Debug(fmt.Sprintf("Looking for selector: %s in values %v", strings.Join(valuesFiles.Selector, "."), valuesFile.Values))
 			result, err := util.GetTableOrValueForSelector(valuesFile.Values, valuesFiles.Selector)
 
@@ -111,7 +193,61 @@ func (f *TemplateContextFeature) valuesHover(templateContext symboltable.Templat
 			}
 		}
 	}
-	return hoverResults.FormatYaml(f.ChartStore.RootURI), nil
+	slices.Sort(profileHovers)
+	return f.valuesSchemaHover(templateContext) + strings.Join(profileHovers, "") + hoverResults.FormatYaml(f.ChartStore.RootURI), nil
+}
+
+// activeProfileHover shows the coalesced value of the active profile, which is used for rendering,
+// and the values files that set it
+func (f *TemplateContextFeature) activeProfileHover(valuesFiles *charts.QueriedValuesFiles) (string, bool) {
+	value, err := util.GetTableOrValueForSelector(valuesFiles.ValuesFiles.GetRenderValues(), valuesFiles.Selector)
+	if err != nil {
+		return "", false
+	}
+	if value == "" {
+		value = "\"\""
+	} else {
+		value = fmt.Sprintf("```yaml\n%s\n```", value)
+	}
+
+	sources := []string{}
+	for _, valuesFile := range valuesFiles.ValuesFiles.ActiveValuesFiles() {
+		if _, err := util.GetTableOrValueForSelector(valuesFile.Values, valuesFiles.Selector); err != nil {
+			continue
+		}
+		path, err := filepath.Rel(f.ChartStore.RootURI.Filename(), valuesFile.URI.Filename())
+		if err != nil {
+			path = valuesFile.URI.Filename()
+		}
+		sources = append(sources, path)
+	}
+	return fmt.Sprintf("### Active profile\n%s\nSet in: %s\n", value, strings.Join(sources, ", ")), true
+}
+
+// valuesSchemaHover documents the value using the values.schema.json of the chart
+func (f *TemplateContextFeature) valuesSchemaHover(templateContext symboltable.TemplateContext) string {
+	if f.Chart.HelmChart == nil || f.Chart.HelmChart.Schema == nil || len(templateContext) == 0 {
+		return ""
+	}
+	path := []string{}
+	for _, part := range templateContext {
+		// elements of lists (e.g. from a range) are described by the items of the list schema
+		if strings.HasSuffix(part, "[]") {
+			path = append(path, strings.TrimSuffix(part, "[]"), "0")
+			continue
+		}
+		path = append(path, part)
+	}
+	info, ok := jsonschema.GetValuesSchemaInfo(f.Chart.HelmChart.Schema, path)
+	if !ok {
+		return ""
+	}
+
+	markdown := info.Markdown()
+	if markdown == "" {
+		return ""
+	}
+	return fmt.Sprintf("### %s\n%s\n", jsonschema.ValuesSchemaFileName, markdown)
 }
 
 func (f *TemplateContextFeature) getMetadataField(v *chart.Metadata, fieldName string) string {
@@ -2,6 +2,7 @@ package languagefeatures
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	helmdocs "github.com/mrjosh/helm-ls/internal/documentation/helm"
@@ -12,11 +13,66 @@ import (
 )
 
 func (f *TemplateContextFeature) Completion() (*lsp.CompletionList, error) {
-	templateContext, err := f.getTemplateContext()
+	if items := f.getVariableDictKeyCompletions(); len(items) > 0 {
+		return &lsp.CompletionList{Items: items, IsIncomplete: false}, nil
+	}
+	dots, err := f.getResolvedDots()
 	if err != nil {
 		return nil, err
 	}
+	dictKeyCompletions := f.getDictKeyCompletions()
+	if len(dots) == 1 && len(dictKeyCompletions) == 0 {
+		return f.withChartOfDot(dots[0]).completionForTemplateContext(dots[0].templateContext)
+	}
+
+	// the dot of a define is called with different contexts, the completions of all of them are combined
+	items := map[string]lsp.CompletionItem{}
+	for _, item := range dictKeyCompletions {
+		items[item.Label] = item
+	}
+	for _, dot := range dots {
+		result, err := f.withChartOfDot(dot).completionForTemplateContext(dot.templateContext)
+		if err != nil || result == nil {
+			continue
+		}
+		for _, item := range result.Items {
+			items[item.Label] = item
+		}
+	}
+	completions := []lsp.CompletionItem{}
+	for _, item := range items {
+		completions = append(completions, item)
+	}
+	return &lsp.CompletionList{Items: completions, IsIncomplete: false}, nil
+}
+
+// getVariableDictKeyCompletions returns the keys of a dict literal that is assigned to a variable,
+// e.g. $d.^ with {{ $d := dict "name" .Values.name }}
+func (f *TemplateContextFeature) getVariableDictKeyCompletions() []lsp.CompletionItem {
+	valueType, err := f.Document.SymbolTable.GetValueTypeOfParent(f.Node.Range())
+	if err != nil || valueType.Kind != symboltable.ValueKindDict {
+		return []lsp.CompletionItem{}
+	}
 
+	keys := []string{}
+	for key := range valueType.Dict {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	items := []lsp.CompletionItem{}
+	for _, key := range keys {
+		items = append(items, lsp.CompletionItem{
+			Label:      key,
+			InsertText: key,
+			Kind:       lsp.CompletionItemKindField,
+			Detail:     valueType.Dict[key].Format(),
+		})
+	}
+	return items
+}
+
+func (f *TemplateContextFeature) completionForTemplateContext(templateContext symboltable.TemplateContext) (*lsp.CompletionList, error) {
 	if len(templateContext) == 0 {
 		return protocol.CompletionResults{}.WithDocs(helmdocs.BuiltInObjects, lsp.CompletionItemKindConstant).ToList(), nil
 	}
@@ -58,7 +114,14 @@ func trimPrefixForNestedDocs(nestedDocs []helmdocs.HelmDocumentation, templateCo
 func (f *TemplateContextFeature) valuesCompletion(templateContext symboltable.TemplateContext) (*lsp.CompletionList, error) {
 	m := make(map[string]lsp.CompletionItem)
 	for _, queriedValuesFiles := range f.Chart.ResolveValueFiles(templateContext.Tail(), f.ChartStore) {
-		for _, valuesFile := range queriedValuesFiles.ValuesFiles.AllValuesFiles() {
+		if queriedValuesFiles.ValuesFiles.HasActiveProfile() {
+			// the coalesced values of the profile are used for rendering
+			for _, item := range util.GetValueCompletion(queriedValuesFiles.ValuesFiles.GetRenderValues(), queriedValuesFiles.Selector) {
+				m[item.InsertText] = item
+			}
+			continue
+		}
+		for _, valuesFile := range queriedValuesFiles.ValuesFiles.ActiveValuesFiles() {
 			for _, item := range util.GetValueCompletion(valuesFile.Values, queriedValuesFiles.Selector) {
 				m[item.InsertText] = item
 			}
@@ -84,3 +84,43 @@ func TestGetValuesCompletionsContainsNoDupliactes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, result.Items, 1)
 }
+
+func TestGetValuesCompletionsUsesActiveProfile(t *testing.T) {
+	main := &charts.ValuesFile{Values: map[string]interface{}{"image": map[string]interface{}{"tag": "1.0", "repository": "nginx"}}}
+	prod := &charts.ValuesFile{Values: map[string]interface{}{"image": map[string]interface{}{"tag": "2.0", "pullPolicy": "Always"}}}
+	staging := &charts.ValuesFile{Values: map[string]interface{}{"image": map[string]interface{}{"digest": "sha256:abc"}}}
+	chart := &charts.Chart{
+		ChartMetadata: &charts.ChartMetadata{Metadata: chart.Metadata{Name: "test"}},
+		ValuesFiles: &charts.ValuesFiles{
+			MainValuesFile:        main,
+			AdditionalValuesFiles: []*charts.ValuesFile{prod, staging},
+			ActiveProfile:         []*charts.ValuesFile{main, prod},
+		},
+		RootURI: "", HelmChart: &chart.Chart{},
+	}
+
+	templateConextFeature := TemplateContextFeature{
+		GenericTemplateContextFeature: &GenericTemplateContextFeature{
+			GenericDocumentUseCase: &GenericDocumentUseCase{
+				Chart: chart,
+			},
+		},
+	}
+
+	result, err := templateConextFeature.valuesCompletion([]string{"Values", "image", ""})
+	assert.NoError(t, err)
+	items := map[string]string{}
+	for _, item := range result.Items {
+		items[item.InsertText] = item.Documentation.(string)
+	}
+	assert.Len(t, items, 3)
+	assert.Contains(t, items["tag"], "2.0")
+	assert.Contains(t, items, "pullPolicy")
+
+	// the documentation shows the coalesced table
+	result, err = templateConextFeature.valuesCompletion([]string{"Values", ""})
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 1)
+	assert.Contains(t, result.Items[0].Documentation, "repository: nginx")
+	assert.Contains(t, result.Items[0].Documentation, "tag: \"2.0\"")
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/symboltable"
+	"github.com/mrjosh/helm-ls/internal/util"
 	"github.com/stretchr/testify/assert"
 	"go.lsp.dev/uri"
 	"helm.sh/helm/v3/pkg/chart"
@@ -284,6 +286,71 @@ value
 %s
 hello
 %s
+`, "```yaml", "```"),
+			wantErr: false,
+		},
+		{
+			name: "values schema",
+			args: args{
+				chart: &charts.Chart{
+					ChartMetadata: &charts.ChartMetadata{},
+					ValuesFiles: &charts.ValuesFiles{
+						MainValuesFile: &charts.ValuesFile{
+							Values: map[string]interface{}{
+								"ports": []interface{}{map[string]interface{}{"protocol": "TCP"}},
+							},
+							URI: "file://tmp/values.yaml",
+						},
+					},
+					HelmChart: &chart.Chart{
+						Schema: []byte(`{
+  "definitions": {"protocol": {"type": "string", "description": "Protocol of the port", "enum": ["TCP", "UDP"]}},
+  "properties": {"ports": {"type": "array", "items": {"required": ["protocol"], "properties": {"protocol": {"$ref": "#/definitions/protocol"}}}}}
+}`),
+					},
+				},
+				splittedVar: []string{"ports[]", "protocol"},
+			},
+			want: fmt.Sprintf(`### values.schema.json
+Protocol of the port
+
+Type: %s
+
+Enum: %s, %s
+
+Required
+### values.yaml
+%s
+TCP
+%s
+`, "`string`", "`TCP`", "`UDP`", "```yaml", "```"),
+			wantErr: false,
+		},
+		{
+			name: "active profile shows the coalesced value",
+			args: args{
+				chart: func() *charts.Chart {
+					main := &charts.ValuesFile{Values: map[string]interface{}{"image": map[string]interface{}{"repository": "nginx", "tag": "1.0"}}, URI: "file://tmp/values.yaml"}
+					prod := &charts.ValuesFile{Values: map[string]interface{}{"image": map[string]interface{}{"tag": "2.0"}}, URI: "file://tmp/values-prod.yaml"}
+					staging := &charts.ValuesFile{Values: map[string]interface{}{"image": map[string]interface{}{"tag": "3.0"}}, URI: "file://tmp/values-staging.yaml"}
+					return &charts.Chart{
+						ChartMetadata: &charts.ChartMetadata{},
+						ValuesFiles: &charts.ValuesFiles{
+							MainValuesFile:        main,
+							AdditionalValuesFiles: []*charts.ValuesFile{prod, staging},
+							ActiveProfile:         []*charts.ValuesFile{main, prod},
+						},
+						HelmChart: &chart.Chart{},
+					}
+				}(),
+				splittedVar: []string{"image"},
+			},
+			want: fmt.Sprintf(`### Active profile
+%s
+repository: nginx
+tag: "2.0"
+%s
+Set in: values.yaml, values-prod.yaml
 `, "```yaml", "```"),
 			wantErr: false,
 		},
@@ -307,3 +374,21 @@ hello
 		})
 	}
 }
+
+func TestBuiltInObjectHoverShowsConfiguredValue(t *testing.T) {
+	config := util.DefaultConfig.RenderConfig
+	config.KubeVersion = "v1.29.0"
+	feature := NewTemplateContextFeature(&GenericDocumentUseCase{RenderConfig: config})
+
+	result, err := feature.hoverForTemplateContext(symboltable.TemplateContext{"Capabilities", "KubeVersion", "Minor"})
+	assert.NoError(t, err)
+	assert.Equal(t, "The Kubernetes minor version.\n\n29\n", result)
+
+	result, err = feature.hoverForTemplateContext(symboltable.TemplateContext{"Release", "Name"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Name of the release\n\nrelease-name\n", result)
+
+	result, err = feature.hoverForTemplateContext(symboltable.TemplateContext{"Files", "Get"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Get file contents. Path is relative to chart.", result)
+}
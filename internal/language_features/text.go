@@ -3,24 +3,29 @@ package languagefeatures
 import (
 	"context"
 
-	"github.com/mrjosh/helm-ls/internal/adapter/yamlls"
 	"github.com/mrjosh/helm-ls/internal/documentation/godocs"
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
 	"github.com/mrjosh/helm-ls/internal/protocol"
 	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
 	lsp "go.lsp.dev/protocol"
 )
 
+// YamlCompletionProvider provides the completions for the YAML text of templates
+type YamlCompletionProvider interface {
+	CallCompletion(ctx context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error)
+}
+
 type TextFeature struct {
 	*GenericDocumentUseCase
 	textDocumentPosition *lsp.TextDocumentPositionParams
 	ctx                  context.Context
-	yamllsConnector      *yamlls.Connector
+	yamllsConnector      YamlCompletionProvider
 }
 
 func NewTextFeature(
 	ctx context.Context,
 	genericDocumentUseCase *GenericDocumentUseCase,
-	yamllsConnector *yamlls.Connector,
+	yamllsConnector YamlCompletionProvider,
 	textDocumentPosition *lsp.TextDocumentPositionParams,
 ) *TextFeature {
 	return &TextFeature{
@@ -50,5 +55,26 @@ func (f *TextFeature) yamllsCompletions(params *lsp.CompletionParams) []lsp.Comp
 		return []lsp.CompletionItem{}
 	}
 	logger.Debug("Got completions from yamlls", response)
-	return response.Items
+	return f.translateCompletions(response.Items)
+}
+
+// translateCompletions removes the completions of yamlls whose edits would replace a part of a template action.
+// The other ranges are the same in the projection and the template.
+func (f *TextFeature) translateCompletions(items []lsp.CompletionItem) []lsp.CompletionItem {
+	if f.Document == nil || f.Document.Ast == nil {
+		return items
+	}
+	projection := lsplocal.ProjectTemplate(f.Document.Ast.Copy(), f.Document.Content)
+
+	result := []lsp.CompletionItem{}
+	for _, item := range items {
+		if item.TextEdit != nil {
+			textEditRange, ok := projection.ToTemplateRange(item.TextEdit.Range)
+			if !ok || textEditRange != item.TextEdit.Range {
+				continue
+			}
+		}
+		result = append(result, item)
+	}
+	return result
 }
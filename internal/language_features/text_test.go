@@ -0,0 +1,38 @@
+package languagefeatures
+
+import (
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+)
+
+func TestTranslateCompletions(t *testing.T) {
+	content := []byte("metadata:\n  name: {{ .Release.Name }}\n  \n")
+	feature := &TextFeature{
+		GenericDocumentUseCase: &GenericDocumentUseCase{
+			Document: &document.TemplateDocument{
+				Document: document.Document{Content: content},
+				Ast:      templateast.ParseAst(nil, content),
+			},
+		},
+	}
+	completionAt := func(label string, start, end lsp.Position) lsp.CompletionItem {
+		return lsp.CompletionItem{Label: label, TextEdit: &lsp.TextEdit{Range: lsp.Range{Start: start, End: end}, NewText: label}}
+	}
+
+	result := feature.translateCompletions([]lsp.CompletionItem{
+		{Label: "without edit"},
+		completionAt("labels", lsp.Position{Line: 2, Character: 2}, lsp.Position{Line: 2, Character: 2}),
+		completionAt("in action", lsp.Position{Line: 1, Character: 8}, lsp.Position{Line: 1, Character: 9}),
+		completionAt("cuts action", lsp.Position{Line: 1, Character: 2}, lsp.Position{Line: 1, Character: 9}),
+	})
+
+	labels := []string{}
+	for _, item := range result {
+		labels = append(labels, item.Label)
+	}
+	assert.Equal(t, []string{"without edit", "labels"}, labels)
+}
@@ -1,6 +1,11 @@
 package languagefeatures
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/lsp/symboltable"
 	"github.com/mrjosh/helm-ls/internal/protocol"
 	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
 	"github.com/mrjosh/helm-ls/internal/util"
@@ -44,3 +49,40 @@ func (f *VariablesFeature) References() (result []lsp.Location, err error) {
 func (f *VariablesFeature) Completion() (result *lsp.CompletionList, err error) {
 	return protocol.CompletionResults{}.WithVariableDefinitions(f.Document.SymbolTable.GetAllVariableDefinitions()).ToList(), nil
 }
+
+func (f *VariablesFeature) Hover() (string, error) {
+	name := f.Node.Content([]byte(f.Document.Content))
+	if f.NodeType == gotemplate.NodeTypeIdentifier {
+		name = f.Node.Parent().Content([]byte(f.Document.Content))
+	}
+	valueType, err := f.Document.SymbolTable.GetVariableValueType(name, f.Node.Range())
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("### %s\n%s", name, formatValueType(valueType))
+	if valueType.Kind != symboltable.ValueKindTemplateContext || len(valueType.TemplateContext) == 0 {
+		return result, nil
+	}
+
+	// show the values (or docs) the variable refers to
+	templateContextFeature := NewTemplateContextFeature(f.GenericDocumentUseCase)
+	hovers := []string{}
+	for _, dot := range templateContextFeature.resolveDots(valueType.TemplateContext) {
+		if len(dot.templateContext) == 0 {
+			continue
+		}
+		hover, err := templateContextFeature.withChartOfDot(dot).hoverForTemplateContext(dot.templateContext)
+		if err == nil && hover != "" && !slices.Contains(hovers, hover) {
+			hovers = append(hovers, hover)
+		}
+	}
+	if len(hovers) == 0 {
+		return result, nil
+	}
+	return fmt.Sprintf("%s\n%s", result, strings.Join(hovers, "\n")), nil
+}
+
+func formatValueType(valueType symboltable.ValueType) string {
+	return fmt.Sprintf("Type: `%s`\n", valueType.Format())
+}
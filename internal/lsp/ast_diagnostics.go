@@ -27,3 +27,12 @@ func IsInElseBranch(node *sitter.Node) bool {
 	}
 	return IsInElseBranch(parent)
 }
+
+func IsInIfAction(node *sitter.Node) bool {
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Type() == gotemplate.NodeTypeIfAction {
+			return true
+		}
+	}
+	return false
+}
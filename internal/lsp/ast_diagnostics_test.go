@@ -52,3 +52,19 @@ func TestIsInElseBranch(t *testing.T) {
 		t.Errorf("t4 was incorrectly identified as not in else branch")
 	}
 }
+
+func TestIsInIfAction(t *testing.T) {
+	template := "{{ if .Values.a }}a: b{{ else }}a: c{{ end }}\nd: e"
+	ast := templateast.ParseAst(nil, []byte(template))
+
+	for _, point := range []sitter.Point{{Row: 0, Column: 18}, {Row: 0, Column: 32}} {
+		node := ast.RootNode().NamedDescendantForPointRange(point, point)
+		if !IsInIfAction(node) {
+			t.Errorf("%s was incorrectly identified as not in an if action", node.Content([]byte(template)))
+		}
+	}
+	point := sitter.Point{Row: 1, Column: 0}
+	if IsInIfAction(ast.RootNode().NamedDescendantForPointRange(point, point)) {
+		t.Errorf("text after the if action was incorrectly identified as in an if action")
+	}
+}
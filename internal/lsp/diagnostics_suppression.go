@@ -0,0 +1,51 @@
+package lsp
+
+import (
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/util"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+)
+
+// SuppressYamlDiagnostics removes the yaml diagnostics that match one of the filters,
+// suppression comments are applied by the DiagnosticsCache of the document
+func SuppressYamlDiagnostics(diagnostics []lsp.Diagnostic, ast *sitter.Tree, filters []util.YamllsDiagnosticsFilter) []lsp.Diagnostic {
+	result := []lsp.Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		if isSuppressedByFilter(diagnostic, ast, filters) {
+			logger.Debug("Suppressing yaml diagnostic", diagnostic.Message)
+			continue
+		}
+		result = append(result, diagnostic)
+	}
+	return result
+}
+
+func isSuppressedByFilter(diagnostic lsp.Diagnostic, ast *sitter.Tree, filters []util.YamllsDiagnosticsFilter) bool {
+	for _, filter := range filters {
+		if !filter.Matches(diagnostic) {
+			continue
+		}
+		if filter.Branch == "" {
+			return true
+		}
+		if ast == nil {
+			continue
+		}
+		node := templateast.NodeAtPosition(ast, diagnostic.Range.Start)
+		if node == nil {
+			continue
+		}
+		switch filter.Branch {
+		case util.BranchIf:
+			if IsInIfAction(node) {
+				return true
+			}
+		case util.BranchElse:
+			if IsInElseBranch(node) {
+				return true
+			}
+		}
+	}
+	return false
+}
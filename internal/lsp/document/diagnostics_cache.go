@@ -1,14 +1,32 @@
 package document
 
 import (
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
 	"github.com/mrjosh/helm-ls/internal/util"
 	lsp "go.lsp.dev/protocol"
 )
 
+// diagnosticSources are the groups of the rules that are reported by the sources of the cache
+var diagnosticSources = []string{
+	diagnosticrules.HelmLintTemplateError.Group,
+	diagnosticrules.TypeCheckFieldAccess.Group,
+	diagnosticrules.RenderLintInvalidYaml.Group,
+	diagnosticrules.YamllsSchema.Group,
+}
+
 type DiagnosticsCache struct {
-	YamlDiagnostics             []lsp.Diagnostic
-	HelmDiagnostics             []lsp.Diagnostic
-	helmlsConfig                util.HelmlsConfiguration
+	YamlDiagnostics []lsp.Diagnostic
+	HelmDiagnostics []lsp.Diagnostic
+	// TypeCheckDiagnostics are updated on every change of the document
+	TypeCheckDiagnostics []lsp.Diagnostic
+	// RenderDiagnostics are found in the rendered output of the template
+	RenderDiagnostics []lsp.Diagnostic
+	// Suppressions are the comments of the document that disable diagnostics
+	Suppressions []lsplocal.Suppression
+	helmlsConfig util.HelmlsConfiguration
+	// reportedSources are the diagnosticSources that set their diagnostics
+	reportedSources             map[string]bool
 	gotYamlDiagnosticsTimes     int
 	yamlDiagnosticsCountReduced bool
 }
@@ -17,7 +35,11 @@ func NewDiagnosticsCache(helmlsConfig util.HelmlsConfiguration) DiagnosticsCache
 	return DiagnosticsCache{
 		[]lsp.Diagnostic{},
 		[]lsp.Diagnostic{},
+		[]lsp.Diagnostic{},
+		[]lsp.Diagnostic{},
+		[]lsplocal.Suppression{},
 		helmlsConfig,
+		map[string]bool{},
 		0,
 		false,
 	}
@@ -27,20 +49,89 @@ func (d *DiagnosticsCache) SetYamlDiagnostics(diagnostics []lsp.Diagnostic) {
 	d.yamlDiagnosticsCountReduced = len(diagnostics) < len(d.YamlDiagnostics)
 	d.YamlDiagnostics = diagnostics
 	d.gotYamlDiagnosticsTimes++
+	d.setReported(diagnosticrules.YamllsSchema.Group)
+}
+
+func (d *DiagnosticsCache) SetHelmDiagnostics(diagnostics []lsp.Diagnostic) {
+	d.HelmDiagnostics = diagnostics
+	d.setReported(diagnosticrules.HelmLintTemplateError.Group)
+}
+
+func (d *DiagnosticsCache) SetTypeCheckDiagnostics(diagnostics []lsp.Diagnostic) {
+	d.TypeCheckDiagnostics = diagnostics
+	d.setReported(diagnosticrules.TypeCheckFieldAccess.Group)
+}
+
+func (d *DiagnosticsCache) SetRenderDiagnostics(diagnostics []lsp.Diagnostic) {
+	d.RenderDiagnostics = diagnostics
+	d.setReported(diagnosticrules.RenderLintInvalidYaml.Group)
+}
+
+// setReported marks the source as reported, the zero value of the cache has no map yet
+func (d *DiagnosticsCache) setReported(source string) {
+	if d.reportedSources == nil {
+		d.reportedSources = map[string]bool{}
+	}
+	d.reportedSources[source] = true
 }
 
+// GetMergedDiagnostics returns the diagnostics of all sources without the disabled rules and the ones that are
+// disabled by suppression comments and adds warnings for the suppression comments that are unused.
+// A suppression is only reported as unused once the sources of its rules set their diagnostics.
 func (d DiagnosticsCache) GetMergedDiagnostics() (merged []lsp.Diagnostic) {
+	used := make([]bool, len(d.Suppressions))
+	suppress := func(diagnostics []lsp.Diagnostic) []lsp.Diagnostic {
+		return lsplocal.ApplySuppressions(diagnosticrules.RemoveDisabled(diagnostics, d.helmlsConfig.DisabledRules), d.Suppressions, used)
+	}
 	merged = []lsp.Diagnostic{}
-	merged = append(merged, d.HelmDiagnostics...)
-	for i, diagnostic := range d.YamlDiagnostics {
+	merged = append(merged, suppress(d.HelmDiagnostics)...)
+	merged = append(merged, suppress(d.TypeCheckDiagnostics)...)
+	merged = append(merged, suppress(d.RenderDiagnostics)...)
+	for i, diagnostic := range suppress(d.YamlDiagnostics) {
 		if i < d.helmlsConfig.YamllsConfiguration.DiagnosticsLimit {
 			merged = append(merged, diagnostic)
 		}
 	}
+	for i, suppression := range d.Suppressions {
+		if !d.hasReported(suppression) {
+			// the diagnostics it disables may not be there yet
+			used[i] = true
+		}
+	}
+	unused := lsplocal.GetUnusedSuppressionDiagnostics(d.Suppressions, used)
+	merged = append(merged, diagnosticrules.RemoveDisabled(unused, d.helmlsConfig.DisabledRules)...)
 	logger.Debug("Merged diagnostics", merged)
 	return merged
 }
 
+// hasReported returns true if the sources of the rules of the suppression set their diagnostics,
+// a suppression without rules needs all sources
+func (d DiagnosticsCache) hasReported(suppression lsplocal.Suppression) bool {
+	for _, source := range diagnosticSources {
+		if d.isSourceReported(source) {
+			continue
+		}
+		if len(suppression.Rules) == 0 {
+			return false
+		}
+		for _, rule := range suppression.Rules {
+			if diagnosticrules.ReferencesGroup(rule, source) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isSourceReported returns true if the source set its diagnostics or never reports any, like a disabled yamlls
+func (d DiagnosticsCache) isSourceReported(source string) bool {
+	if source == diagnosticrules.YamllsSchema.Group &&
+		(!d.helmlsConfig.YamllsConfiguration.Enabled || !d.helmlsConfig.YamllsConfiguration.DiagnosticsEnabled) {
+		return true
+	}
+	return d.reportedSources[source]
+}
+
 func (d *DiagnosticsCache) ShouldShowDiagnosticsOnNewYamlDiagnostics() bool {
 	return d.yamlDiagnosticsCountReduced || // show the diagnostics when the count is reduced, this means an error was fixed and it should be shown to the user
 		d.helmlsConfig.YamllsConfiguration.ShowDiagnosticsDirectly || // show the diagnostics directly when the user configured to show them
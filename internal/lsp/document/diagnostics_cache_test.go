@@ -66,7 +66,7 @@ func TestDiagnosticsCache_GetMergedDiagnostics(t *testing.T) {
 	}
 
 	cache.SetYamlDiagnostics(yamlDiagnostics)
-	cache.HelmDiagnostics = helmDiagnostics
+	cache.SetHelmDiagnostics(helmDiagnostics)
 	merged := cache.GetMergedDiagnostics()
 
 	assert.Equal(t, 2, len(merged))
@@ -127,3 +127,59 @@ func TestDiagnosticsCache_ShouldShowDiagnosticsOnNewYamlDiagnosticsForInitialDia
 
 	assert.False(t, cache.ShouldShowDiagnosticsOnNewYamlDiagnostics())
 }
+
+func TestDiagnosticsCache_GetMergedDiagnosticsWithSuppressions(t *testing.T) {
+	content := "{{/* helm-ls:disable-next-line yamlls */}}\na: b\n{{/* helm-ls:disable-next-line helm-lint */}}\nc: d\n"
+	doc := NewTemplateDocument("file:///test.yaml", []byte(content), true, util.DefaultConfig)
+
+	suppressed := lsp.Diagnostic{Range: lsp.Range{Start: lsp.Position{Line: 1}}, Code: "yamlls", Message: "suppressed"}
+	shown := lsp.Diagnostic{Range: lsp.Range{Start: lsp.Position{Line: 1}}, Code: "helm-lint", Message: "shown"}
+	doc.DiagnosticsCache.SetYamlDiagnostics([]lsp.Diagnostic{suppressed})
+	doc.DiagnosticsCache.SetHelmDiagnostics([]lsp.Diagnostic{shown})
+
+	merged := doc.DiagnosticsCache.GetMergedDiagnostics()
+
+	messages := []string{}
+	for _, diagnostic := range merged {
+		messages = append(messages, diagnostic.Message)
+	}
+	assert.Equal(t, []string{"shown", "Unused suppression, there are no diagnostics of helm-lint to disable"}, messages)
+}
+
+func TestDiagnosticsCache_GetMergedDiagnosticsWithDisabledRules(t *testing.T) {
+	helmlsConfig := util.DefaultConfig
+	helmlsConfig.DisabledRules = []string{"type-check", "HLS301"}
+	content := "{{/* helm-ls:disable-next-line yamlls */}}\na: b\n"
+	doc := NewTemplateDocument("file:///test.yaml", []byte(content), true, helmlsConfig)
+
+	doc.DiagnosticsCache.SetTypeCheckDiagnostics([]lsp.Diagnostic{{Code: "HLS001", Message: "disabled"}})
+	doc.DiagnosticsCache.SetRenderDiagnostics([]lsp.Diagnostic{{Code: "HLS104", Message: "shown"}})
+
+	assert.Equal(t, []lsp.Diagnostic{{Code: "HLS104", Message: "shown"}}, doc.DiagnosticsCache.GetMergedDiagnostics())
+}
+
+func TestDiagnosticsCache_GetMergedDiagnosticsReportsUnusedSuppressionsOfReportedSources(t *testing.T) {
+	content := "{{/* helm-ls-ignore: yamlls */}}\na: b\n{{/* helm-ls:disable-next-line type-check */}}\nc: d\n{{/* helm-ls:disable-next-line */}}\ne: f\n"
+	doc := NewTemplateDocument("file:///test.yaml", []byte(content), true, util.DefaultConfig)
+
+	getMessages := func() []string {
+		messages := []string{}
+		for _, diagnostic := range doc.DiagnosticsCache.GetMergedDiagnostics() {
+			messages = append(messages, diagnostic.Message)
+		}
+		return messages
+	}
+
+	assert.Empty(t, getMessages())
+
+	doc.DiagnosticsCache.SetTypeCheckDiagnostics([]lsp.Diagnostic{})
+	assert.Equal(t, []string{"Unused suppression, there are no diagnostics of type-check to disable"}, getMessages())
+
+	doc.DiagnosticsCache.SetYamlDiagnostics([]lsp.Diagnostic{{Range: lsp.Range{Start: lsp.Position{Line: 1}}, Code: "yamlls/syntax", Message: "suppressed"}})
+	doc.DiagnosticsCache.SetRenderDiagnostics([]lsp.Diagnostic{})
+	doc.DiagnosticsCache.SetHelmDiagnostics([]lsp.Diagnostic{})
+	assert.Equal(t, []string{
+		"Unused suppression, there are no diagnostics of type-check to disable",
+		"Unused suppression, there are no diagnostics of any rule to disable",
+	}, getMessages())
+}
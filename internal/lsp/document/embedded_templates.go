@@ -0,0 +1,183 @@
+package document
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mrjosh/helm-ls/internal/lsp/symboltable"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"go.lsp.dev/uri"
+	"gopkg.in/yaml.v3"
+)
+
+// newEmbeddedTemplateDocument returns a template document for the string values of a values file
+// that contain templates (e.g. annotations: '{{ .Release.Name }}' which is rendered with tpl).
+// All other content is replaced by spaces, so that positions in the template document are the
+// same as in the values file. Returns nil if the values file contains no templates.
+func newEmbeddedTemplateDocument(fileURI uri.URI, content []byte, node *yaml.Node) *TemplateDocument {
+	lines := bytes.Split(content, []byte("\n"))
+	masked := make([][]byte, len(lines))
+	for i, line := range lines {
+		masked[i] = bytes.Repeat([]byte(" "), len(line))
+	}
+	starts := getNodeStarts(node, lines)
+
+	found := false
+	walkStringValues(node, func(valueNode *yaml.Node) {
+		if strings.Contains(valueNode.Value, "{{") && unmaskScalar(valueNode, starts, lines, masked) {
+			found = true
+		}
+	})
+	if !found {
+		return nil
+	}
+
+	maskedContent := bytes.Join(masked, []byte("\n"))
+	ast := templateast.ParseAst(nil, maskedContent)
+	return &TemplateDocument{
+		Document:    *NewDocument(fileURI, maskedContent, false),
+		Ast:         ast,
+		SymbolTable: symboltable.NewSymbolTable(ast, maskedContent),
+	}
+}
+
+// walkStringValues calls f for all string values (not keys) of the yaml node
+func walkStringValues(node *yaml.Node, f func(*yaml.Node)) {
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			walkStringValues(child, f)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			walkStringValues(node.Content[i], f)
+		}
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" {
+			f(node)
+		}
+	}
+}
+
+// position is a line and a byte offset in the line
+type position struct {
+	row, column int
+}
+
+func (p position) before(other position) bool {
+	return p.row < other.row || (p.row == other.row && p.column < other.column)
+}
+
+// getNodeStarts returns the start positions of all nodes in document order
+func getNodeStarts(node *yaml.Node, lines [][]byte) []position {
+	result := []position{}
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node == nil {
+			return
+		}
+		if start, ok := getStart(node, lines); ok && node.Kind != yaml.DocumentNode {
+			result = append(result, start)
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(node)
+	slices.SortFunc(result, func(a, b position) int {
+		if a.before(b) {
+			return -1
+		}
+		if b.before(a) {
+			return 1
+		}
+		return 0
+	})
+	return result
+}
+
+// getStart converts the position of the node to a byte offset, yaml counts the columns in runes
+func getStart(node *yaml.Node, lines [][]byte) (position, bool) {
+	row := node.Line - 1
+	if row < 0 || row >= len(lines) || node.Column < 1 {
+		return position{}, false
+	}
+	column := 0
+	for i := 1; i < node.Column; i++ {
+		if column >= len(lines[row]) {
+			return position{}, false
+		}
+		_, size := utf8.DecodeRune(lines[row][column:])
+		column += size
+	}
+	return position{row: row, column: column}, column < len(lines[row])
+}
+
+// getScalarEnd returns the end of the scalar (exclusive), yaml.v3 does not store it.
+// The scalar ends before the next node, trailing lines that are empty or comments are not part of it.
+func getScalarEnd(start position, starts []position, lines [][]byte) position {
+	end := position{row: len(lines) - 1, column: len(lines[len(lines)-1])}
+	for _, next := range starts {
+		if start.before(next) {
+			end = next
+			break
+		}
+	}
+	for end.row > start.row {
+		trimmed := bytes.TrimSpace(lines[end.row][:end.column])
+		if len(trimmed) > 0 && trimmed[0] != '#' {
+			break
+		}
+		end = position{row: end.row - 1, column: len(lines[end.row-1])}
+	}
+	return end
+}
+
+// unmaskScalar copies the source of the scalar into masked, quotes and the indicators of block scalars are left masked
+func unmaskScalar(node *yaml.Node, starts []position, lines [][]byte, masked [][]byte) bool {
+	start, ok := getStart(node, lines)
+	if !ok {
+		return false
+	}
+	end := getScalarEnd(start, starts, lines)
+
+	quoted := node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0
+	quote := lines[start.row][start.column]
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		// the content of block scalars starts in the line after the indicator
+		start = position{row: start.row + 1}
+	}
+
+	for row := start.row; row <= end.row && row < len(lines); row++ {
+		from, to := 0, len(lines[row])
+		if row == start.row {
+			from = start.column
+		}
+		if row == end.row {
+			to = end.column
+		}
+		if from < to {
+			copy(masked[row][from:to], lines[row][from:to])
+		}
+	}
+
+	if quoted {
+		masked[start.row][start.column] = ' '
+		for row := end.row; row >= start.row; row-- {
+			to := len(lines[row])
+			if row == end.row {
+				to = end.column
+			}
+			if index := bytes.LastIndexByte(lines[row][:to], quote); index >= 0 && (row > start.row || index > start.column) {
+				masked[row][index] = ' '
+				break
+			}
+		}
+	}
+	return true
+}
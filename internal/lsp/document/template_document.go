@@ -1,6 +1,7 @@
 package document
 
 import (
+	lsplocal "github.com/mrjosh/helm-ls/internal/lsp"
 	"github.com/mrjosh/helm-ls/internal/lsp/symboltable"
 	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
 	"github.com/mrjosh/helm-ls/internal/util"
@@ -25,7 +26,7 @@ func (d *TemplateDocument) GetDocumentType() DocumentType {
 
 func NewTemplateDocument(fileURI uri.URI, content []byte, isOpen bool, helmlsConfig util.HelmlsConfiguration) *TemplateDocument {
 	ast := templateast.ParseAst(nil, content)
-	return &TemplateDocument{
+	doc := &TemplateDocument{
 		Document:                *NewDocument(fileURI, content, isOpen),
 		NeedsRefreshDiagnostics: false,
 		Ast:                     ast,
@@ -33,6 +34,8 @@ func NewTemplateDocument(fileURI uri.URI, content []byte, isOpen bool, helmlsCon
 		SymbolTable:             symboltable.NewSymbolTable(ast, content),
 		IsYaml:                  IsYamllsEnabled(fileURI, helmlsConfig.YamllsConfiguration),
 	}
+	doc.DiagnosticsCache.Suppressions = lsplocal.GetSuppressions(ast.RootNode(), content)
+	return doc
 }
 
 // ApplyChanges updates the content of the document from LSP textDocument/didChange events.
@@ -45,6 +48,7 @@ func (d *TemplateDocument) ApplyChanges(changes []lsp.TextDocumentContentChangeE
 
 func (d *TemplateDocument) ApplyChangesToAst(newContent []byte) {
 	d.Ast = templateast.ParseAst(nil, newContent)
+	d.DiagnosticsCache.Suppressions = lsplocal.GetSuppressions(d.Ast.RootNode(), newContent)
 }
 
 func IsYamllsEnabled(uri lsp.URI, yamllsConfiguration util.YamllsConfiguration) bool {
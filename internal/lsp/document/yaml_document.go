@@ -13,6 +13,8 @@ type YamlDocument struct {
 	Node       yaml.Node
 	ParsedYaml map[string]any
 	ParseErr   error
+	// EmbeddedTemplates contains the templates in string values, nil if there are none
+	EmbeddedTemplates *TemplateDocument
 }
 
 func (d *YamlDocument) GetDocumentType() DocumentType {
@@ -23,10 +25,11 @@ func NewYamlDocument(fileURI uri.URI, content []byte, isOpen bool, helmlsConfig
 	node, parsedYaml, unmarshalErr := parseYaml(content)
 	logger.Debug("Parsed yaml", fileURI.Filename(), unmarshalErr)
 	return &YamlDocument{
-		Document:   *NewDocument(fileURI, content, isOpen),
-		Node:       node,
-		ParsedYaml: parsedYaml,
-		ParseErr:   unmarshalErr,
+		Document:          *NewDocument(fileURI, content, isOpen),
+		Node:              node,
+		ParsedYaml:        parsedYaml,
+		ParseErr:          unmarshalErr,
+		EmbeddedTemplates: newEmbeddedTemplateDocument(fileURI, content, &node),
 	}
 }
 
@@ -39,6 +42,7 @@ func (d *YamlDocument) ApplyChanges(changes []lsp.TextDocumentContentChangeEvent
 	d.Node = node
 	d.ParsedYaml = parsedYaml
 	d.ParseErr = unmarshalErr
+	d.EmbeddedTemplates = newEmbeddedTemplateDocument(d.URI, d.Content, &node)
 }
 
 func parseYaml(content []byte) (yaml.Node, map[string]any, error) {
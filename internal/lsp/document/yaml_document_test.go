@@ -22,3 +22,62 @@ broken
 	doc = NewYamlDocument(uri.File("test"), []byte(brokenYaml), true, util.DefaultConfig)
 	assert.Error(t, doc.ParseErr)
 }
+
+func TestNewYamlDocumentWithEmbeddedTemplates(t *testing.T) {
+	content := `name: test
+annotation: '{{ .Release.Name }}-x'
+quoted: "{{ .Values.name }}"
+plain: prefix-{{ .Values.name }}
+block: |
+  {{- if .Values.name }}
+  enabled
+  {{- end }}
+`
+	doc := NewYamlDocument(uri.File("values.yaml"), []byte(content), true, util.DefaultConfig)
+
+	assert.NotNil(t, doc.EmbeddedTemplates)
+	assert.Equal(t, `          
+             {{ .Release.Name }}-x 
+         {{ .Values.name }} 
+       prefix-{{ .Values.name }}
+        
+  {{- if .Values.name }}
+  enabled
+  {{- end }}
+`, string(doc.EmbeddedTemplates.Content))
+	assert.Equal(t, len(content), len(doc.EmbeddedTemplates.Content))
+
+	doc = NewYamlDocument(uri.File("values.yaml"), []byte("name: test"), true, util.DefaultConfig)
+	assert.Nil(t, doc.EmbeddedTemplates)
+}
+
+func TestNewYamlDocumentWithMultiLineEmbeddedTemplates(t *testing.T) {
+	content := `ä: "{{ .Values.a }}"
+plain: first
+  {{ .Values.b }}
+quoted: "first
+  {{ .Values.c }}"
+folded: >
+  {{ .Values.d }}
+
+  {{ .Values.e }}
+# {{ comment }}
+last: x
+`
+	doc := NewYamlDocument(uri.File("values.yaml"), []byte(content), true, util.DefaultConfig)
+
+	assert.NotNil(t, doc.EmbeddedTemplates)
+	assert.Equal(t, `     {{ .Values.a }} 
+       first
+  {{ .Values.b }}
+         first
+  {{ .Values.c }} 
+         
+  {{ .Values.d }}
+
+  {{ .Values.e }}
+               
+       
+`, string(doc.EmbeddedTemplates.Content))
+	assert.Equal(t, len(content), len(doc.EmbeddedTemplates.Content))
+}
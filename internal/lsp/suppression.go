@@ -0,0 +1,140 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+)
+
+const (
+	disableNextLineDirective = "helm-ls:disable-next-line"
+	disableDirective         = "helm-ls:disable"
+	// ignoreDirective is an alias of disableNextLineDirective, e.g. {{/* helm-ls-ignore: yamlls */}}
+	ignoreDirective = "helm-ls-ignore:"
+)
+
+// Suppression is a comment like {{/* helm-ls:disable-next-line type-check */}} or {{/* helm-ls:disable yamlls */}}
+// that disables diagnostics of the following line or of the whole file
+type Suppression struct {
+	// Rules are the rule IDs or groups of rules that are disabled, all rules are disabled if it is empty
+	Rules []string
+	// Line is the line that is disabled by a disable-next-line comment
+	Line      uint32
+	WholeFile bool
+	// Range is the range of the comment
+	Range lsp.Range
+}
+
+// GetSuppressions returns the suppression comments of the template
+func GetSuppressions(node *sitter.Node, content []byte) []Suppression {
+	suppressions := []Suppression{}
+	if node == nil {
+		return suppressions
+	}
+	collectSuppressions(node, content, &suppressions)
+	return suppressions
+}
+
+func collectSuppressions(node *sitter.Node, content []byte, suppressions *[]Suppression) {
+	if node.Type() == gotemplate.NodeTypeComment {
+		if suppression, ok := parseSuppression(node, content); ok {
+			*suppressions = append(*suppressions, suppression)
+		}
+		return
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		collectSuppressions(node.NamedChild(i), content, suppressions)
+	}
+}
+
+func parseSuppression(node *sitter.Node, content []byte) (Suppression, bool) {
+	comment := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(node.Content(content), "/*"), "*/"))
+	suppression := Suppression{Range: templateast.GetLspRangeForNode(node)}
+
+	rules, ok := cutDirective(comment, disableNextLineDirective)
+	if !ok {
+		rules, ok = strings.CutPrefix(comment, ignoreDirective)
+	}
+	if ok {
+		suppression.Line = node.EndPoint().Row + 1
+	} else if rules, ok = cutDirective(comment, disableDirective); ok {
+		suppression.WholeFile = true
+	} else {
+		return suppression, false
+	}
+	suppression.Rules = strings.FieldsFunc(rules, func(r rune) bool { return r == ',' || r == ' ' })
+	return suppression, true
+}
+
+// cutDirective returns the text after the directive, if the comment starts with it
+func cutDirective(comment string, directive string) (string, bool) {
+	rest, ok := strings.CutPrefix(comment, directive)
+	if !ok || (rest != "" && rest[0] != ' ') {
+		return "", false
+	}
+	return rest, true
+}
+
+// Matches returns true if the diagnostic is disabled by the suppression
+func (s Suppression) Matches(diagnostic lsp.Diagnostic) bool {
+	if !s.WholeFile && diagnostic.Range.Start.Line != s.Line {
+		return false
+	}
+	if len(s.Rules) == 0 {
+		return true
+	}
+	for _, rule := range s.Rules {
+		if diagnosticrules.Matches(rule, diagnostic) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplySuppressions removes the diagnostics that are disabled by one of the suppressions
+// and marks the suppressions that disabled a diagnostic in used
+func ApplySuppressions(diagnostics []lsp.Diagnostic, suppressions []Suppression, used []bool) []lsp.Diagnostic {
+	result := []lsp.Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		suppressed := false
+		for i, suppression := range suppressions {
+			if suppression.Matches(diagnostic) {
+				used[i] = true
+				suppressed = true
+			}
+		}
+		if !suppressed {
+			result = append(result, diagnostic)
+		}
+	}
+	return result
+}
+
+// GetUnusedSuppressionDiagnostics returns warnings for the suppressions that did not disable any diagnostic
+func GetUnusedSuppressionDiagnostics(suppressions []Suppression, used []bool) []lsp.Diagnostic {
+	diagnostics := []lsp.Diagnostic{}
+	for i, suppression := range suppressions {
+		if used[i] {
+			continue
+		}
+		rules := "any rule"
+		if len(suppression.Rules) > 0 {
+			rules = strings.Join(suppression.Rules, ", ")
+		}
+		diagnostics = append(diagnostics, lsp.Diagnostic{
+			Range:           suppression.Range,
+			Severity:        lsp.DiagnosticSeverityWarning,
+			Code:            diagnosticrules.UnusedSuppression.Code,
+			CodeDescription: diagnosticrules.UnusedSuppression.CodeDescription(),
+			Source:          "Helm-ls",
+			Message:         fmt.Sprintf("Unused suppression, there are no diagnostics of %s to disable", rules),
+			Tags:            []lsp.DiagnosticTag{lsp.DiagnosticTagUnnecessary},
+		})
+	}
+	return diagnostics
+}
@@ -0,0 +1,72 @@
+package lsp
+
+import (
+	"testing"
+
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+)
+
+func TestGetSuppressions(t *testing.T) {
+	template := `{{/* helm-ls:disable yamlls */}}
+a: b
+{{- /* helm-ls:disable-next-line type-check/field-access, helm-lint */}}
+c: {{ .Values.c.d }}
+{{/* helm-ls:disable-next-line */}}
+e: f
+{{/* helm-ls:disabled */}}
+{{/* a comment */}}
+{{/* helm-ls-ignore: helm-lint, yamlls */}}
+g: h
+`
+	suppressions := GetSuppressions(templateast.ParseAst(nil, []byte(template)).RootNode(), []byte(template))
+
+	assert.Len(t, suppressions, 4)
+	assert.Equal(t, []string{"yamlls"}, suppressions[0].Rules)
+	assert.True(t, suppressions[0].WholeFile)
+	assert.Equal(t, []string{"type-check/field-access", "helm-lint"}, suppressions[1].Rules)
+	assert.False(t, suppressions[1].WholeFile)
+	assert.Equal(t, uint32(3), suppressions[1].Line)
+	assert.Empty(t, suppressions[2].Rules)
+	assert.Equal(t, uint32(5), suppressions[2].Line)
+	assert.Equal(t, []string{"helm-lint", "yamlls"}, suppressions[3].Rules)
+	assert.False(t, suppressions[3].WholeFile)
+	assert.Equal(t, uint32(9), suppressions[3].Line)
+}
+
+func TestApplySuppressions(t *testing.T) {
+	template := `{{/* helm-ls:disable yamlls */}}
+a: b
+{{/* helm-ls:disable-next-line type-check */}}
+c: {{ .Values.c.d }}
+{{/* helm-ls:disable-next-line helm-lint */}}
+e: f
+`
+	suppressions := GetSuppressions(templateast.ParseAst(nil, []byte(template)).RootNode(), []byte(template))
+	diagnosticAt := func(line uint32, code string) lsp.Diagnostic {
+		return lsp.Diagnostic{Range: lsp.Range{Start: lsp.Position{Line: line}, End: lsp.Position{Line: line}}, Code: code}
+	}
+
+	used := make([]bool, len(suppressions))
+	result := ApplySuppressions([]lsp.Diagnostic{
+		diagnosticAt(1, diagnosticrules.YamllsSchema.Code),
+		diagnosticAt(3, diagnosticrules.TypeCheckFieldAccess.Code),
+		diagnosticAt(3, diagnosticrules.RenderLintInvalidObject.Code),
+		diagnosticAt(5, diagnosticrules.TypeCheckFieldAccess.Code),
+	}, suppressions, used)
+
+	assert.Equal(t, []lsp.Diagnostic{
+		diagnosticAt(3, diagnosticrules.RenderLintInvalidObject.Code),
+		diagnosticAt(5, diagnosticrules.TypeCheckFieldAccess.Code),
+	}, result)
+	assert.Equal(t, []bool{true, true, false}, used)
+
+	unused := GetUnusedSuppressionDiagnostics(suppressions, used)
+	assert.Len(t, unused, 1)
+	assert.Equal(t, diagnosticrules.UnusedSuppression.Code, unused[0].Code)
+	assert.Equal(t, lsp.DiagnosticSeverityWarning, unused[0].Severity)
+	assert.Equal(t, uint32(4), unused[0].Range.Start.Line)
+	assert.Equal(t, "Unused suppression, there are no diagnostics of helm-lint to disable", unused[0].Message)
+}
@@ -35,6 +35,16 @@ func (t TemplateContext) AppendSuffix(suffix string) TemplateContext {
 	return t
 }
 
+// IndexElement returns the element of a template context for an index of the dot, e.g. [1] for index . 1
+func IndexElement(index string) string {
+	return "[" + index + "]"
+}
+
+// IsIndexElement returns true if the element of a template context is an index of the dot, see IndexElement
+func IsIndexElement(element string) bool {
+	return len(element) > 2 && strings.HasPrefix(element, "[") && strings.HasSuffix(element, "]")
+}
+
 func NewTemplateContext(string string) TemplateContext {
 	if string == "." {
 		return TemplateContext{}
@@ -51,8 +61,10 @@ type SymbolTable struct {
 	contextsReversed    map[sitter.Range]TemplateContext
 	includeDefinitions  map[string][]sitter.Range
 	includeUsages       map[string][]sitter.Range
+	includeCalls        map[string][]IncludeCall
 	variableDefinitions map[string][]VariableDefinition
 	variableUsages      map[string][]sitter.Range
+	variableTypes       map[sitter.Range]ValueType
 }
 
 func NewSymbolTable(ast *sitter.Tree, content []byte) *SymbolTable {
@@ -61,8 +73,10 @@ func NewSymbolTable(ast *sitter.Tree, content []byte) *SymbolTable {
 		contextsReversed:    map[sitter.Range]TemplateContext{},
 		includeDefinitions:  map[string][]sitter.Range{},
 		includeUsages:       map[string][]sitter.Range{},
+		includeCalls:        map[string][]IncludeCall{},
 		variableDefinitions: map[string][]VariableDefinition{},
 		variableUsages:      map[string][]sitter.Range{},
+		variableTypes:       map[sitter.Range]ValueType{},
 	}
 	s.parseTree(ast, content)
 	return s
@@ -85,6 +99,12 @@ func (s *SymbolTable) GetTemplateContextRanges(templateContext TemplateContext)
 	return s.contexts[templateContext.Format()]
 }
 
+// HasTemplateContext returns true if a template context was stored for the range
+func (s *SymbolTable) HasTemplateContext(pointRange sitter.Range) bool {
+	_, ok := s.contextsReversed[pointRange]
+	return ok
+}
+
 func (s *SymbolTable) GetTemplateContext(pointRange sitter.Range) (TemplateContext, error) {
 	result, ok := s.contextsReversed[pointRange]
 	if !ok {
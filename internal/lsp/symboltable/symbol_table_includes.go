@@ -62,3 +62,90 @@ func ParseIncludeFunctionCall(node *sitter.Node, content []byte) (string, error)
 func (v *IncludeDefinitionsVisitor) Exit(_ *sitter.Node)                        {}
 func (v *IncludeDefinitionsVisitor) EnterContextShift(_ *sitter.Node, _ string) {}
 func (v *IncludeDefinitionsVisitor) ExitContextShift(_ *sitter.Node)            {}
+
+// IncludeCall is a call of a named template (include or template) with the
+// template context that is passed as argument, e.g. {{ include "foo" .Values.ingress }}
+type IncludeCall struct {
+	Range           sitter.Range
+	ArgumentContext TemplateContext
+	// DictArgument is set if the argument is a dict literal, e.g. {{ include "foo" (dict "ctx" $ "svc" .Values.service) }}
+	// it maps the keys of the dict to the template contexts of the values
+	DictArgument map[string]TemplateContext
+	// ListArgument is set if the argument is a list literal, e.g. {{ include "foo" (list $ .Values.service) }}
+	// it contains the template contexts of the elements, elements that are no template context are nil
+	ListArgument []TemplateContext
+}
+
+func (c IncludeCall) IsDict() bool {
+	return c.DictArgument != nil
+}
+
+func (c IncludeCall) IsList() bool {
+	return c.ListArgument != nil
+}
+
+func (s *SymbolTable) AddIncludeCall(symbol string, includeCall IncludeCall) {
+	if s.includeCalls == nil {
+		s.includeCalls = map[string][]IncludeCall{}
+	}
+	s.includeCalls[symbol] = append(s.includeCalls[symbol], includeCall)
+}
+
+// GetIncludeCallContexts returns the template contexts that are passed to the named template
+// by the calls in this document. Variables in the contexts are resolved.
+func (s *SymbolTable) GetIncludeCallContexts(symbol string) []IncludeCall {
+	result := []IncludeCall{}
+	for _, includeCall := range s.includeCalls[symbol] {
+		if includeCall.IsDict() {
+			dict := map[string]TemplateContext{}
+			for key, value := range includeCall.DictArgument {
+				templateContext, ok := s.resolveIncludeArgument(symbol, value, includeCall.Range)
+				if ok {
+					dict[key] = templateContext
+				}
+			}
+			result = append(result, IncludeCall{Range: includeCall.Range, DictArgument: dict})
+			continue
+		}
+		if includeCall.IsList() {
+			list := make([]TemplateContext, len(includeCall.ListArgument))
+			for i, value := range includeCall.ListArgument {
+				if value == nil {
+					continue
+				}
+				if templateContext, ok := s.resolveIncludeArgument(symbol, value, includeCall.Range); ok {
+					list[i] = templateContext
+				}
+			}
+			result = append(result, IncludeCall{Range: includeCall.Range, ListArgument: list})
+			continue
+		}
+
+		templateContext, ok := s.resolveIncludeArgument(symbol, includeCall.ArgumentContext, includeCall.Range)
+		if ok {
+			result = append(result, IncludeCall{Range: includeCall.Range, ArgumentContext: templateContext})
+		}
+	}
+	return result
+}
+
+func (s *SymbolTable) resolveIncludeArgument(symbol string, argument TemplateContext, callRange sitter.Range) (TemplateContext, bool) {
+	templateContext, err := s.ResolveVariablesInTemplateContext(argument.Copy(), callRange)
+	if err != nil || templateContext.IsVariable() {
+		logger.Debug("Could not resolve argument of include call", symbol, err)
+		return templateContext, false
+	}
+	return templateContext, true
+}
+
+// GetIncludeDefinitionForRange returns the name of the define action surrounding the range
+func (s *SymbolTable) GetIncludeDefinitionForRange(pointRange sitter.Range) (string, bool) {
+	for name, definitionRanges := range s.includeDefinitions {
+		for _, definitionRange := range definitionRanges {
+			if util.RangeContainsRange(definitionRange, pointRange) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
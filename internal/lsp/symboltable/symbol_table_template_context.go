@@ -2,6 +2,7 @@ package symboltable
 
 import (
 	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	"github.com/mrjosh/helm-ls/internal/util"
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
@@ -64,18 +65,215 @@ func (v *TemplateContextVisitor) Enter(node *sitter.Node) {
 	case gotemplate.NodeTypeField:
 		content := node.ChildByFieldName("name").Content(v.content)
 		v.symbolTable.AddTemplateContext(append(v.currentContext, content), node.ChildByFieldName("name").Range())
-	case gotemplate.NodeTypeSelectorExpression:
+	case gotemplate.NodeTypeSelectorExpression, gotemplate.NodeTypeUnfinishedSelectorExpression:
 		operandNode := node.ChildByFieldName("operand")
 		if operandNode != nil && operandNode.Type() == gotemplate.NodeTypeVariable {
 			v.StashContext()
 			v.PushContext(operandNode.Content(v.content))
 		}
+	case gotemplate.NodeTypeFunctionCall:
+		v.enterIncludeCall(node)
+		v.enterIndexCall(node)
+	case gotemplate.NodeTypeTemplateAction:
+		v.enterTemplateAction(node)
+	case gotemplate.NodeTypeVariableDefinition:
+		v.enterVariableDefinition(node)
+	case gotemplate.NodeTypeRangeVariableDefinition:
+		v.enterRangeVariableDefinition(node)
+	}
+}
+
+// enterIncludeCall stores the template context that is passed to a named template
+func (v *TemplateContextVisitor) enterIncludeCall(node *sitter.Node) {
+	includeName, err := ParseIncludeFunctionCall(node, v.content)
+	if err != nil {
+		return
+	}
+	arguments := node.ChildByFieldName("arguments")
+	if arguments.NamedChildCount() < 2 {
+		return
+	}
+	v.addIncludeCall(includeName, node, arguments.NamedChild(1))
+}
+
+// enterIndexCall stores the template contexts of string literal keys of index, get and dig calls,
+// e.g. "my-key" in {{ index .Values "my-key" }} has the template context Values.my-key
+func (v *TemplateContextVisitor) enterIndexCall(node *sitter.Node) {
+	function, arguments := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+	if function == nil || arguments == nil {
+		return
+	}
+	argumentNodes := []*sitter.Node{}
+	for i := 0; i < int(arguments.NamedChildCount()); i++ {
+		argumentNodes = append(argumentNodes, arguments.NamedChild(i))
+	}
+
+	var (
+		collection *sitter.Node
+		keys       []*sitter.Node
+	)
+	switch function.Content(v.content) {
+	case "index", "get":
+		if len(argumentNodes) < 2 {
+			return
+		}
+		collection, keys = argumentNodes[0], argumentNodes[1:]
+	case "dig":
+		// dig "a" "b" $default $dict, the dict can also be passed with a pipe
+		if piped := getPipedArgument(node); piped != nil {
+			if len(argumentNodes) < 2 {
+				return
+			}
+			collection, keys = piped, argumentNodes[:len(argumentNodes)-1]
+		} else {
+			if len(argumentNodes) < 3 {
+				return
+			}
+			collection, keys = argumentNodes[len(argumentNodes)-1], argumentNodes[:len(argumentNodes)-2]
+		}
+	default:
+		return
+	}
+
+	templateContext, ok := v.getContextForArgument(collection)
+	if !ok {
+		return
+	}
+	for _, key := range keys {
+		switch key.Type() {
+		case gotemplate.NodeTypeInterpretedStringLiteral:
+			templateContext = append(templateContext.Copy(), util.RemoveQuotes(key.Content(v.content)))
+			v.symbolTable.AddTemplateContext(templateContext, key.Range())
+		case gotemplate.NodeTypeIntLiteral:
+			if len(templateContext) == 0 {
+				// the dot of a define that is called with a list, e.g. index . 1
+				templateContext = TemplateContext{IndexElement(key.Content(v.content))}
+				continue
+			}
+			templateContext = templateContext.Copy().AppendSuffix("[]")
+		default:
+			return
+		}
+	}
+}
+
+// getPipedArgument returns the element of a chained pipeline that is passed to the function call
+func getPipedArgument(node *sitter.Node) *sitter.Node {
+	parent := node.Parent()
+	if parent == nil || parent.Type() != gotemplate.NodeTypeChainedPipeline {
+		return nil
+	}
+	previous := node.PrevNamedSibling()
+	if previous == nil || previous.Type() == gotemplate.NodeTypeFunctionCall {
+		return nil
+	}
+	return previous
+}
+
+// enterTemplateAction stores the template context that is passed to a named template
+// with {{ template "foo" .Values.ingress }}
+func (v *TemplateContextVisitor) enterTemplateAction(node *sitter.Node) {
+	name, argument := node.ChildByFieldName("name"), node.ChildByFieldName("argument")
+	if name == nil || argument == nil {
+		return
+	}
+	v.addIncludeCall(util.RemoveQuotes(name.Content(v.content)), node, argument)
+}
+
+func (v *TemplateContextVisitor) addIncludeCall(includeName string, node *sitter.Node, argument *sitter.Node) {
+	if dict, ok := v.getDictForArgument(argument); ok {
+		v.symbolTable.AddIncludeCall(includeName, IncludeCall{Range: node.Range(), DictArgument: dict})
+		return
+	}
+	if list, ok := v.getListForArgument(argument); ok {
+		v.symbolTable.AddIncludeCall(includeName, IncludeCall{Range: node.Range(), ListArgument: list})
+		return
+	}
+	argumentContext, ok := v.getContextForArgument(argument)
+	if !ok {
+		return
+	}
+	v.symbolTable.AddIncludeCall(includeName, IncludeCall{Range: node.Range(), ArgumentContext: argumentContext})
+}
+
+// getDictForArgument returns the template contexts of the values of a dict literal
+// (e.g. (dict "ctx" $ "svc" .Values.service)), values that are no template context are skipped
+func (v *TemplateContextVisitor) getDictForArgument(node *sitter.Node) (map[string]TemplateContext, bool) {
+	arguments, ok := v.getLiteralArguments(node, "dict")
+	if !ok {
+		return nil, false
+	}
+
+	result := map[string]TemplateContext{}
+	for i := 0; i+1 < int(arguments.NamedChildCount()); i += 2 {
+		key := arguments.NamedChild(i)
+		if key.Type() != gotemplate.NodeTypeInterpretedStringLiteral {
+			continue
+		}
+		templateContext, ok := v.getContextForArgument(arguments.NamedChild(i + 1))
+		if ok {
+			result[util.RemoveQuotes(key.Content(v.content))] = templateContext
+		}
+	}
+	return result, true
+}
+
+// getListForArgument returns the template contexts of the elements of a list literal
+// (e.g. (list $ .Values.service)), elements that are no template context are nil
+func (v *TemplateContextVisitor) getListForArgument(node *sitter.Node) ([]TemplateContext, bool) {
+	arguments, ok := v.getLiteralArguments(node, "list")
+	if !ok {
+		return nil, false
+	}
+
+	result := make([]TemplateContext, arguments.NamedChildCount())
+	for i := range result {
+		if templateContext, ok := v.getContextForArgument(arguments.NamedChild(i)); ok {
+			result[i] = templateContext
+		}
+	}
+	return result, true
+}
+
+// getLiteralArguments returns the arguments of a call of the function (e.g. dict or list) that is passed as argument
+func (v *TemplateContextVisitor) getLiteralArguments(node *sitter.Node, functionName string) (*sitter.Node, bool) {
+	if node.Type() == gotemplate.NodeTypeParenthesizedPipeline && node.NamedChildCount() == 1 {
+		node = node.NamedChild(0)
+	}
+	if node.Type() != gotemplate.NodeTypeFunctionCall {
+		return nil, false
+	}
+	function, arguments := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+	if function == nil || function.Content(v.content) != functionName || arguments == nil {
+		return nil, false
 	}
+	return arguments, true
+}
+
+// getContextForArgument returns the template context of an argument (e.g. ., .Values.foo or $var.foo)
+func (v *TemplateContextVisitor) getContextForArgument(node *sitter.Node) (TemplateContext, bool) {
+	switch node.Type() {
+	case gotemplate.NodeTypeDot:
+		return v.currentContext.Copy(), true
+	case gotemplate.NodeTypeVariable:
+		return TemplateContext{node.Content(v.content)}, true
+	case gotemplate.NodeTypeField, gotemplate.NodeTypeSelectorExpression:
+		templateContext := getContextForSelectorExpression(node, v.content)
+		if templateContext.IsVariable() {
+			return templateContext, true
+		}
+		return append(v.currentContext.Copy(), templateContext...), true
+	case gotemplate.NodeTypeParenthesizedPipeline:
+		if node.NamedChildCount() == 1 {
+			return v.getContextForArgument(node.NamedChild(0))
+		}
+	}
+	return TemplateContext{}, false
 }
 
 func (v *TemplateContextVisitor) Exit(node *sitter.Node) {
 	switch node.Type() {
-	case gotemplate.NodeTypeSelectorExpression:
+	case gotemplate.NodeTypeSelectorExpression, gotemplate.NodeTypeUnfinishedSelectorExpression:
 		operandNode := node.ChildByFieldName("operand")
 		if operandNode != nil && operandNode.Type() == gotemplate.NodeTypeVariable {
 			v.PopContext()
@@ -6,36 +6,20 @@ import (
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
+// ResolveVariablesInTemplateContext replaces a variable at the start of the template context
+// with the template context of its inferred type, e.g. $svc.port with {{ $svc := .Values.service }}
+// is resolved to Values.service.port
 func (s *SymbolTable) ResolveVariablesInTemplateContext(templateContext TemplateContext, pointRange sitter.Range) (TemplateContext, error) {
 	if !templateContext.IsVariable() {
 		return templateContext, nil
 	}
 
-	variableName := templateContext[0]
-	if variableName == "$" {
-		return templateContext.Tail(), nil
-	}
-
-	variableDefinitions := s.variableDefinitions[variableName]
-
-	if len(variableDefinitions) == 0 {
-		return templateContext, fmt.Errorf("variable %s not found", variableName)
-	}
-
-	definition, err := findDefinitionForRange(variableDefinitions, pointRange)
+	valueType, err := s.GetValueTypeForTemplateContext(templateContext, pointRange)
 	if err != nil {
-		return templateContext, fmt.Errorf("variable %s not found %e", variableName, err)
+		return templateContext, err
 	}
-
-	prefix := getPrefixTemplateContextForVariable(definition)
-
-	return s.ResolveVariablesInTemplateContext(append(prefix, templateContext.Tail()...), pointRange)
-}
-
-func getPrefixTemplateContextForVariable(definition VariableDefinition) TemplateContext {
-	prefix := NewTemplateContext(definition.Value)
-	if definition.VariableType == VariableTypeRangeValue && len(prefix) > 0 {
-		prefix[len(prefix)-1] = prefix[len(prefix)-1] + "[]"
+	if valueType.Kind != ValueKindTemplateContext {
+		return templateContext, fmt.Errorf("%s is of type %s", templateContext.Format(), valueType.Format())
 	}
-	return prefix
+	return valueType.TemplateContext, nil
 }
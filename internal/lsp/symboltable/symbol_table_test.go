@@ -339,3 +339,106 @@ func TestSymbolTableForValuesSingleTests(t *testing.T) {
 		})
 	}
 }
+
+func TestSymbolTableForIncludeCalls(t *testing.T) {
+	content := `
+{{ include "dot" . }}
+{{ include "field" .Values.ingress }}
+{{ range .Values.list }}{{ include "range" . }}{{ end }}
+{{ $svc := .Values.service }}{{ include "variable" $svc.port }}
+{{ template "template" $.Values.root }}
+{{ include "parenthesized" (.Values.paren) }}
+{{ include "pipeline" (.Values.pipe | toYaml) }}
+{{ define "inner" }}{{ include "field" .ingress }}{{ end }}
+`
+
+	ast := templateast.ParseAst(nil, []byte(content))
+	symbolTable := NewSymbolTable(ast, []byte(content))
+
+	contextsForInclude := func(name string) []TemplateContext {
+		result := []TemplateContext{}
+		for _, includeCall := range symbolTable.GetIncludeCallContexts(name) {
+			result = append(result, includeCall.ArgumentContext)
+		}
+		return result
+	}
+
+	assert.Equal(t, []TemplateContext{{}}, contextsForInclude("dot"))
+	assert.Equal(t, []TemplateContext{{"Values", "ingress"}, {"ingress"}}, contextsForInclude("field"))
+	assert.Equal(t, []TemplateContext{{"Values", "list[]"}}, contextsForInclude("range"))
+	assert.Equal(t, []TemplateContext{{"Values", "service", "port"}}, contextsForInclude("variable"))
+	assert.Equal(t, []TemplateContext{{"Values", "root"}}, contextsForInclude("template"))
+	assert.Equal(t, []TemplateContext{{"Values", "paren"}}, contextsForInclude("parenthesized"))
+	assert.Equal(t, []TemplateContext{}, contextsForInclude("pipeline"))
+
+	calls := symbolTable.GetIncludeCallContexts("field")
+	defineName, ok := symbolTable.GetIncludeDefinitionForRange(calls[1].Range)
+	assert.True(t, ok)
+	assert.Equal(t, "inner", defineName)
+	_, ok = symbolTable.GetIncludeDefinitionForRange(calls[0].Range)
+	assert.False(t, ok)
+}
+
+func TestSymbolTableForIncludeCallsWithDict(t *testing.T) {
+	content := `
+{{ $svc := .Values.service }}
+{{ include "dict" (dict "ctx" $ "svc" $svc "port" .Values.port "text" (printf "x") 1 .Values.ignored) }}
+`
+
+	ast := templateast.ParseAst(nil, []byte(content))
+	symbolTable := NewSymbolTable(ast, []byte(content))
+
+	calls := symbolTable.GetIncludeCallContexts("dict")
+	assert.Len(t, calls, 1)
+	assert.True(t, calls[0].IsDict())
+	assert.Equal(t, map[string]TemplateContext{
+		"ctx":  {},
+		"svc":  {"Values", "service"},
+		"port": {"Values", "port"},
+	}, calls[0].DictArgument)
+}
+
+func TestSymbolTableForIncludeCallsWithList(t *testing.T) {
+	content := `
+{{ $svc := .Values.service }}
+{{ include "list" (list $ $svc (printf "x") .Values.port) }}
+`
+
+	ast := templateast.ParseAst(nil, []byte(content))
+	symbolTable := NewSymbolTable(ast, []byte(content))
+
+	calls := symbolTable.GetIncludeCallContexts("list")
+	assert.Len(t, calls, 1)
+	assert.True(t, calls[0].IsList())
+	assert.Equal(t, []TemplateContext{{}, {"Values", "service"}, nil, {"Values", "port"}}, calls[0].ListArgument)
+}
+
+func TestSymbolTableForIndexCalls(t *testing.T) {
+	testCases := []struct {
+		template   string
+		path       []string
+		startPoint sitter.Point
+	}{
+		{`{{ index .Values "my-key" "nested" }}`, []string{"Values", "my-key"}, sitter.Point{Row: 0, Column: 17}},
+		{`{{ index .Values "my-key" "nested" }}`, []string{"Values", "my-key", "nested"}, sitter.Point{Row: 0, Column: 26}},
+		{`{{ index .Values.list 0 "name" }}`, []string{"Values", "list[]", "name"}, sitter.Point{Row: 0, Column: 24}},
+		{`{{ index . 1 "name" }}`, []string{"[1]", "name"}, sitter.Point{Row: 0, Column: 13}},
+		{`{{ with .Values.service }}{{ get . "port" }}{{ end }}`, []string{"Values", "service", "port"}, sitter.Point{Row: 0, Column: 35}},
+		{`{{ get .Values.map "k" }}`, []string{"Values", "map", "k"}, sitter.Point{Row: 0, Column: 19}},
+		{`{{ dig "a" "b" "" .Values }}`, []string{"Values", "a", "b"}, sitter.Point{Row: 0, Column: 11}},
+		{`{{ .Values | dig "a" "b" "" }}`, []string{"Values", "a", "b"}, sitter.Point{Row: 0, Column: 21}},
+		{`{{ $x := .Values }}{{ index $x "my-key" }}`, []string{"$x", "my-key"}, sitter.Point{Row: 0, Column: 31}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.template, func(t *testing.T) {
+			ast := templateast.ParseAst(nil, []byte(tt.template))
+			symbolTable := NewSymbolTable(ast, []byte(tt.template))
+			points := []sitter.Point{}
+			for _, pointRange := range symbolTable.GetTemplateContextRanges(tt.path) {
+				points = append(points, pointRange.StartPoint)
+			}
+			assert.Contains(t, points, tt.startPoint, "Ast was %s", ast.RootNode())
+		})
+	}
+}
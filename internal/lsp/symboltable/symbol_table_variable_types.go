@@ -0,0 +1,384 @@
+package symboltable
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	helmdocs "github.com/mrjosh/helm-ls/internal/documentation/helm"
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	"github.com/mrjosh/helm-ls/internal/util"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+type ValueKind int64
+
+const (
+	ValueKindUnknown ValueKind = iota
+	ValueKindTemplateContext
+	ValueKindDict
+	ValueKindList
+	ValueKindScalar
+)
+
+// ValueType is the type of a variable inferred from its definition
+type ValueType struct {
+	Kind ValueKind
+	// TemplateContext the value refers to, e.g. Values.service for {{ $svc := .Values.service }}
+	TemplateContext TemplateContext
+	// Dict contains the types of the keys of a dict literal
+	Dict map[string]ValueType
+	// Elem is the type of the elements of a list literal
+	Elem *ValueType
+	// Name of a scalar type, e.g. string or int
+	Name string
+}
+
+func (t ValueType) Format() string {
+	switch t.Kind {
+	case ValueKindTemplateContext:
+		return "." + t.TemplateContext.Format()
+	case ValueKindDict:
+		if len(t.Dict) == 0 {
+			return helmdocs.ReturnTypeDict
+		}
+		keys := []string{}
+		for key := range t.Dict {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		return fmt.Sprintf("%s{%s}", helmdocs.ReturnTypeDict, strings.Join(keys, ", "))
+	case ValueKindList:
+		if t.Elem == nil || t.Elem.Kind == ValueKindUnknown {
+			return helmdocs.ReturnTypeList
+		}
+		return fmt.Sprintf("%s of %s", helmdocs.ReturnTypeList, t.Elem.Format())
+	case ValueKindScalar:
+		return t.Name
+	}
+	return "unknown"
+}
+
+// Lookup returns the type of the value at path inside of the value,
+// e.g. the path svc of the value (dict "svc" .Values.service) is .Values.service
+func (t ValueType) Lookup(path TemplateContext) (ValueType, bool) {
+	if len(path) == 0 {
+		return t, true
+	}
+	switch t.Kind {
+	case ValueKindTemplateContext:
+		return ValueType{Kind: ValueKindTemplateContext, TemplateContext: append(t.TemplateContext.Copy(), path...)}, true
+	case ValueKindDict:
+		value, ok := t.Dict[path[0]]
+		if !ok {
+			return ValueType{}, false
+		}
+		return value.Lookup(path.Tail())
+	}
+	return ValueType{}, false
+}
+
+// rangeElement returns the type of the elements when ranging over the value
+func (t ValueType) rangeElement() ValueType {
+	switch t.Kind {
+	case ValueKindTemplateContext:
+		templateContext := t.TemplateContext.Copy()
+		if len(templateContext) > 0 {
+			templateContext = templateContext.AppendSuffix("[]")
+		}
+		return ValueType{Kind: ValueKindTemplateContext, TemplateContext: templateContext}
+	case ValueKindList:
+		if t.Elem != nil {
+			return *t.Elem
+		}
+	}
+	return ValueType{}
+}
+
+// rangeKey returns the type of the keys or indexes when ranging over the value
+func (t ValueType) rangeKey() ValueType {
+	switch t.Kind {
+	case ValueKindList:
+		return scalarType(helmdocs.ReturnTypeInt)
+	case ValueKindDict:
+		return scalarType(helmdocs.ReturnTypeString)
+	}
+	return scalarType(helmdocs.ReturnTypeInt + " | " + helmdocs.ReturnTypeString)
+}
+
+func scalarType(name string) ValueType {
+	return ValueType{Kind: ValueKindScalar, Name: name}
+}
+
+func returnType(name string) ValueType {
+	switch name {
+	case helmdocs.ReturnTypeDict:
+		return ValueType{Kind: ValueKindDict}
+	case helmdocs.ReturnTypeList:
+		return ValueType{Kind: ValueKindList}
+	}
+	return scalarType(name)
+}
+
+func (s *SymbolTable) AddVariableType(definitionRange sitter.Range, valueType ValueType) {
+	if s.variableTypes == nil {
+		s.variableTypes = map[sitter.Range]ValueType{}
+	}
+	s.variableTypes[definitionRange] = valueType
+}
+
+// GetValueTypeForTemplateContext returns the type of a template context, variables are
+// resolved to the type of their definition
+func (s *SymbolTable) GetValueTypeForTemplateContext(templateContext TemplateContext, pointRange sitter.Range) (ValueType, error) {
+	if !templateContext.IsVariable() {
+		return ValueType{Kind: ValueKindTemplateContext, TemplateContext: templateContext}, nil
+	}
+	variableName := templateContext[0]
+	if variableName == "$" {
+		return ValueType{Kind: ValueKindTemplateContext, TemplateContext: templateContext.Tail()}, nil
+	}
+
+	definition, err := s.getVariableDefinition(variableName, pointRange)
+	if err != nil {
+		return ValueType{}, err
+	}
+	valueType := s.variableTypes[definition.Range]
+	if valueType.Kind == ValueKindUnknown {
+		return ValueType{}, fmt.Errorf("type of variable %s is unknown", variableName)
+	}
+	result, ok := valueType.Lookup(templateContext.Tail())
+	if !ok {
+		return ValueType{}, fmt.Errorf("%s of type %s has no field %s", variableName, valueType.Format(), templateContext.Tail().Format())
+	}
+	return result, nil
+}
+
+// GetValueType returns the type of the template context at the range
+func (s *SymbolTable) GetValueType(pointRange sitter.Range) (ValueType, error) {
+	templateContext, ok := s.contextsReversed[pointRange]
+	if !ok {
+		return ValueType{}, fmt.Errorf("No template context found for range %v", pointRange)
+	}
+	return s.GetValueTypeForTemplateContext(templateContext.Copy(), pointRange)
+}
+
+// GetValueTypeOfParent returns the type of the template context at the range without
+// its last element, e.g. the type of $d for $d.^
+func (s *SymbolTable) GetValueTypeOfParent(pointRange sitter.Range) (ValueType, error) {
+	templateContext, ok := s.contextsReversed[pointRange]
+	if !ok || len(templateContext) == 0 {
+		return ValueType{}, fmt.Errorf("No template context found for range %v", pointRange)
+	}
+	return s.GetValueTypeForTemplateContext(templateContext[:len(templateContext)-1].Copy(), pointRange)
+}
+
+// GetVariableValueType returns the type of the variable that is accessed at the range
+func (s *SymbolTable) GetVariableValueType(name string, pointRange sitter.Range) (ValueType, error) {
+	return s.GetValueTypeForTemplateContext(TemplateContext{name}, pointRange)
+}
+
+func (v *TemplateContextVisitor) enterVariableDefinition(node *sitter.Node) {
+	nameNode, valueNode := node.ChildByFieldName("variable"), node.ChildByFieldName("value")
+	if nameNode == nil || valueNode == nil {
+		return
+	}
+	valueType := v.inferValueType(valueNode)
+	if isRangeElementDefinition(node) {
+		valueType = valueType.rangeElement()
+	}
+	v.symbolTable.AddVariableType(getVariableDefinitionRange(nameNode, valueNode), valueType)
+}
+
+// isRangeElementDefinition returns true for {{ range $element := .Values.list }}
+func isRangeElementDefinition(node *sitter.Node) bool {
+	parent := node.Parent()
+	if parent == nil || parent.Type() != gotemplate.NodeTypeRangeAction {
+		return false
+	}
+	rangeNode := parent.ChildByFieldName("range")
+	return rangeNode != nil && rangeNode.Equal(node)
+}
+
+func (v *TemplateContextVisitor) enterRangeVariableDefinition(node *sitter.Node) {
+	rangeNode := node.ChildByFieldName("range")
+	if rangeNode == nil {
+		return
+	}
+	rangeType := v.inferValueType(rangeNode)
+	if keyNode := node.ChildByFieldName("index"); keyNode != nil {
+		v.symbolTable.AddVariableType(getVariableDefinitionRange(keyNode, rangeNode), rangeType.rangeKey())
+	}
+	if elementNode := node.ChildByFieldName("element"); elementNode != nil {
+		v.symbolTable.AddVariableType(getVariableDefinitionRange(elementNode, rangeNode), rangeType.rangeElement())
+	}
+}
+
+// inferValueType returns the type of the value of an expression
+func (v *TemplateContextVisitor) inferValueType(node *sitter.Node) ValueType {
+	switch node.Type() {
+	case gotemplate.NodeTypeDot, gotemplate.NodeTypeVariable, gotemplate.NodeTypeField, gotemplate.NodeTypeSelectorExpression:
+		templateContext, ok := v.getContextForArgument(node)
+		if !ok {
+			return ValueType{}
+		}
+		// variables are defined before they are used, so their types are already known
+		valueType, err := v.symbolTable.GetValueTypeForTemplateContext(templateContext, node.Range())
+		if err != nil {
+			return ValueType{}
+		}
+		return valueType
+	case gotemplate.NodeTypeParenthesizedPipeline:
+		if node.NamedChildCount() == 1 {
+			return v.inferValueType(node.NamedChild(0))
+		}
+	case gotemplate.NodeTypeInterpretedStringLiteral, gotemplate.NodeTypeRawStringLiteral:
+		return scalarType(helmdocs.ReturnTypeString)
+	case gotemplate.NodeTypeIntLiteral:
+		return scalarType(helmdocs.ReturnTypeInt)
+	case gotemplate.NodeTypeFloatLiteral:
+		return scalarType(helmdocs.ReturnTypeFloat)
+	case gotemplate.NodeTypeTrue, gotemplate.NodeTypeFalse:
+		return scalarType(helmdocs.ReturnTypeBool)
+	case gotemplate.NodeTypeFunctionCall:
+		return v.inferFunctionCallType(node, nil)
+	case gotemplate.NodeTypeChainedPipeline:
+		return v.inferPipelineType(node)
+	}
+	return ValueType{}
+}
+
+// inferPipelineType returns the type of the last function of the pipeline, the result
+// of each element is passed as last argument to the next function
+func (v *TemplateContextVisitor) inferPipelineType(node *sitter.Node) ValueType {
+	if node.NamedChildCount() == 0 {
+		return ValueType{}
+	}
+	valueType := v.inferValueType(node.NamedChild(0))
+	for i := 1; i < int(node.NamedChildCount()); i++ {
+		element := node.NamedChild(i)
+		if element.Type() != gotemplate.NodeTypeFunctionCall {
+			return ValueType{}
+		}
+		piped := valueType
+		valueType = v.inferFunctionCallType(element, &piped)
+	}
+	return valueType
+}
+
+func (v *TemplateContextVisitor) inferFunctionCallType(node *sitter.Node, piped *ValueType) ValueType {
+	function := node.ChildByFieldName("function")
+	if function == nil {
+		return ValueType{}
+	}
+	argumentNodes := []*sitter.Node{}
+	if arguments := node.ChildByFieldName("arguments"); arguments != nil {
+		for i := 0; i < int(arguments.NamedChildCount()); i++ {
+			argumentNodes = append(argumentNodes, arguments.NamedChild(i))
+		}
+	}
+	argumentTypes := []ValueType{}
+	for _, argument := range argumentNodes {
+		argumentTypes = append(argumentTypes, v.inferValueType(argument))
+	}
+	if piped != nil {
+		argumentTypes = append(argumentTypes, *piped)
+	}
+
+	functionName := function.Content(v.content)
+	switch functionName {
+	case "dict":
+		return v.inferDictType(argumentNodes, argumentTypes)
+	case "list":
+		if len(argumentTypes) == 0 {
+			return ValueType{Kind: ValueKindList}
+		}
+		return ValueType{Kind: ValueKindList, Elem: &argumentTypes[0]}
+	case "index", "get":
+		if len(argumentNodes) == 0 || piped != nil {
+			return ValueType{}
+		}
+		return v.inferIndexType(argumentTypes[0], argumentNodes[1:])
+	case "dig":
+		// dig "a" "b" $default $dict
+		if len(argumentTypes) < 3 {
+			return ValueType{}
+		}
+		return v.inferIndexType(argumentTypes[len(argumentTypes)-1], argumentNodes[:len(argumentTypes)-2])
+	case "default", "required":
+		// the given value (last argument) is used if it is set
+		return lastKnownType(argumentTypes)
+	case "coalesce", "ternary":
+		for _, argumentType := range argumentTypes {
+			if argumentType.Kind != ValueKindUnknown {
+				return argumentType
+			}
+		}
+		return ValueType{}
+	case "first", "last":
+		if len(argumentTypes) == 1 {
+			return argumentTypes[0].rangeElement()
+		}
+		return ValueType{}
+	}
+
+	if name, ok := helmdocs.GetFunctionReturnType(functionName); ok {
+		return returnType(name)
+	}
+	return ValueType{}
+}
+
+func lastKnownType(valueTypes []ValueType) ValueType {
+	for i := len(valueTypes) - 1; i >= 0; i-- {
+		if valueTypes[i].Kind != ValueKindUnknown {
+			return valueTypes[i]
+		}
+	}
+	return ValueType{}
+}
+
+// inferDictType returns the type of a dict literal, keys that are no string literals are skipped
+func (v *TemplateContextVisitor) inferDictType(argumentNodes []*sitter.Node, argumentTypes []ValueType) ValueType {
+	result := ValueType{Kind: ValueKindDict, Dict: map[string]ValueType{}}
+	for i := 0; i+1 < len(argumentNodes); i += 2 {
+		key := argumentNodes[i]
+		if key.Type() != gotemplate.NodeTypeInterpretedStringLiteral {
+			continue
+		}
+		result.Dict[util.RemoveQuotes(key.Content(v.content))] = argumentTypes[i+1]
+	}
+	return result
+}
+
+// inferIndexType returns the type of index $collection "key" 0
+func (v *TemplateContextVisitor) inferIndexType(collection ValueType, keys []*sitter.Node) ValueType {
+	result := collection
+	for _, key := range keys {
+		switch key.Type() {
+		case gotemplate.NodeTypeInterpretedStringLiteral:
+			value, ok := result.Lookup(TemplateContext{util.RemoveQuotes(key.Content(v.content))})
+			if !ok {
+				return ValueType{}
+			}
+			result = value
+		case gotemplate.NodeTypeIntLiteral:
+			if result.Kind == ValueKindTemplateContext && len(result.TemplateContext) == 0 {
+				// the dot of a define that is called with a list, e.g. index . 1
+				result = ValueType{Kind: ValueKindTemplateContext, TemplateContext: TemplateContext{IndexElement(key.Content(v.content))}}
+				continue
+			}
+			result = result.rangeElement()
+		default:
+			return ValueType{}
+		}
+	}
+	return result
+}
+
+func getVariableDefinitionRange(variableNameNode, variableValueNode *sitter.Node) sitter.Range {
+	return sitter.Range{
+		StartPoint: variableNameNode.StartPoint(),
+		EndPoint:   variableValueNode.EndPoint(),
+		StartByte:  variableNameNode.StartByte(),
+		EndByte:    variableValueNode.EndByte(),
+	}
+}
@@ -0,0 +1,75 @@
+package symboltable
+
+import (
+	"strings"
+	"testing"
+
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVariableValueType(t *testing.T) {
+	tests := []struct {
+		template string
+		variable string
+		expected string
+	}{
+		{`{{ $svc := .Values.service }} ^`, "$svc", ".Values.service"},
+		{`{{ with .Values.service }}{{ $port := .port }} ^{{ end }}`, "$port", ".Values.service.port"},
+		{`{{ $root := $ }}{{ with .Values.service }}{{ $v := $root.Values.other }} ^{{ end }}`, "$v", ".Values.other"},
+		{`{{ range $k, $v := .Values.env }} ^{{ end }}`, "$v", ".Values.env[]"},
+		{`{{ range $k, $v := .Values.env }} ^{{ end }}`, "$k", "int | string"},
+		{`{{ range $i, $v := list 1 2 }} ^{{ end }}`, "$i", "int"},
+		{`{{ range $i, $v := list 1 2 }} ^{{ end }}`, "$v", "int"},
+		{`{{ range $a := .Values.a }}{{ range $b := $a.items }} ^{{ end }}{{ end }}`, "$b", ".Values.a[].items[]"},
+		{`{{ $d := dict "svc" .Values.service "name" "test" }} ^`, "$d", "dict{name, svc}"},
+		{`{{ $l := list .Values.a .Values.b }} ^`, "$l", "list of .Values.a"},
+		{`{{ $x := default "x" .Values.name }} ^`, "$x", ".Values.name"},
+		{`{{ $x := .Values.name | default "x" }} ^`, "$x", ".Values.name"},
+		{`{{ $x := index .Values "service" "port" }} ^`, "$x", ".Values.service.port"},
+		{`{{ $x := index .Values.list 0 }} ^`, "$x", ".Values.list[]"},
+		{`{{ $d := dict "svc" .Values.service }}{{ $x := get $d "svc" }} ^`, "$x", ".Values.service"},
+		{`{{ $x := dig "a" "b" "" .Values }} ^`, "$x", ".Values.a.b"},
+		{`{{ $x := .Values.name | quote }} ^`, "$x", "string"},
+		{`{{ $x := len .Values.list }} ^`, "$x", "int"},
+		{`{{ $x := .Values | toYaml | fromYaml }} ^`, "$x", "dict"},
+		{`{{ $x := true }} ^`, "$x", "bool"},
+		{`{{ $x := "test" }} ^`, "$x", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			col := strings.Index(tt.template, "^")
+			ast := templateast.ParseAst(nil, []byte(tt.template))
+			symbolTable := NewSymbolTable(ast, []byte(tt.template))
+
+			result, err := symbolTable.GetVariableValueType(tt.variable, sitter.Range{StartByte: uint32(col), EndByte: uint32(col + 1)})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Format())
+		})
+	}
+}
+
+func TestGetValueTypeForTemplateContextWithDict(t *testing.T) {
+	template := `{{ $d := dict "svc" .Values.service "name" "test" }} ^`
+	col := strings.Index(template, "^")
+	ast := templateast.ParseAst(nil, []byte(template))
+	symbolTable := NewSymbolTable(ast, []byte(template))
+	pointRange := sitter.Range{StartByte: uint32(col), EndByte: uint32(col + 1)}
+
+	result, err := symbolTable.GetValueTypeForTemplateContext(TemplateContext{"$d", "svc", "port"}, pointRange)
+	assert.NoError(t, err)
+	assert.Equal(t, ValueType{Kind: ValueKindTemplateContext, TemplateContext: TemplateContext{"Values", "service", "port"}}, result)
+
+	result, err = symbolTable.GetValueTypeForTemplateContext(TemplateContext{"$d", "name"}, pointRange)
+	assert.NoError(t, err)
+	assert.Equal(t, ValueType{Kind: ValueKindScalar, Name: "string"}, result)
+
+	_, err = symbolTable.ResolveVariablesInTemplateContext(TemplateContext{"$d", "name"}, pointRange)
+	assert.Error(t, err)
+
+	_, err = symbolTable.GetValueTypeForTemplateContext(TemplateContext{"$d", "unknown"}, pointRange)
+	assert.Error(t, err)
+}
@@ -45,7 +45,11 @@ func (v *VariablesVisitor) Enter(node *sitter.Node) {
 		if variableNameNode == nil || variableValueNode == nil {
 			return
 		}
-		v.addVariableDefinition(VariableTypeAssigment, node, variableNameNode, variableValueNode)
+		variableType := VariableTypeAssigment
+		if isRangeElementDefinition(node) {
+			variableType = VariableTypeRangeValue
+		}
+		v.addVariableDefinition(variableType, node, variableNameNode, variableValueNode)
 
 	case gotemplate.NodeTypeVariable:
 		if v.insideDefinition {
@@ -104,12 +108,7 @@ func (v *VariablesVisitor) addVariableDefinition(variableType VariableType, defi
 			StartByte:  definitionNode.StartByte(),
 			EndByte:    v.currentScope().EndByte(),
 		},
-		Range: sitter.Range{
-			StartPoint: variableNameNode.StartPoint(),
-			EndPoint:   variableValueNode.EndPoint(),
-			StartByte:  variableNameNode.StartByte(),
-			EndByte:    variableValueNode.EndByte(),
-		},
+		Range: getVariableDefinitionRange(variableNameNode, variableValueNode),
 	})
 }
 
@@ -0,0 +1,413 @@
+package lsp
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+)
+
+// PlaceholderKind describes what a template action was replaced with in the projection
+type PlaceholderKind int
+
+const (
+	// PlaceholderNone means the action was replaced with spaces
+	PlaceholderNone PlaceholderKind = iota
+	// PlaceholderScalar is a scalar value, e.g. for `image: {{ .Values.image }}`
+	PlaceholderScalar
+	// PlaceholderKey is a unique key, e.g. for `{{ $key }}: value`
+	PlaceholderKey
+	// PlaceholderMappingEntry is a unique key without a value for actions that output entries of a mapping
+	PlaceholderMappingEntry
+	// PlaceholderSequenceEntry is an empty item for actions that output items of a sequence
+	PlaceholderSequenceEntry
+	// PlaceholderObject is an empty mapping for actions that output a whole block
+	PlaceholderObject
+)
+
+// Placeholder is a template action that was replaced in the projection
+type Placeholder struct {
+	Kind PlaceholderKind
+	// Range of the action in the template, the placeholder has the same range in the projection
+	Range sitter.Range
+	// Text that replaced the action, it is padded with spaces to the length of the action
+	Text string
+}
+
+// TemplateProjection is the YAML projection of a template. The actions are replaced by placeholders
+// that fit the YAML context of the action, so that the projection stays valid YAML.
+// The placeholders are never longer than the actions they replace, therefore positions outside of
+// actions are the same in the template and the projection.
+type TemplateProjection struct {
+	Text         string
+	Placeholders []Placeholder
+	// templateRanges are the ranges of all actions, including control structures and broken actions
+	templateRanges []sitter.Range
+}
+
+// functions that always return a string, a number or a boolean
+var (
+	stringFunctions = []string{
+		"quote", "squote", "print", "printf", "println", "toString", "b64enc", "b64dec", "upper", "lower",
+		"title", "trim", "trimSuffix", "trimPrefix", "trunc", "replace", "join", "sha256sum", "toJson",
+		"required", "randAlphaNum", "uuidv4", "cat", "nospace", "kebabcase", "snakecase", "camelcase",
+	}
+	numberFunctions  = []string{"int", "int64", "float64", "len", "add", "add1", "sub", "mul", "div", "mod", "max", "min", "atoi"}
+	booleanFunctions = []string{"eq", "ne", "lt", "le", "gt", "ge", "and", "or", "not", "empty", "has", "hasKey", "contains", "hasPrefix", "hasSuffix", "semverCompare"}
+	blockFunctions   = []string{"toYaml", "include", "tpl", "nindent", "indent"}
+)
+
+var blockScalarHeaderRegex = regexp.MustCompile(`(^|[\s:-])[|>][-+0-9]*\s*(#.*)?$`)
+
+// ProjectTemplate converts the template to YAML by replacing the actions with placeholders.
+// Actions that output something are replaced depending on their YAML context: a scalar for values,
+// a unique key for keys, a key or a sequence item for actions that output mapping entries or
+// sequence items on their own line and an empty mapping for actions that output a nested block.
+// Control structures, comments and variable definitions are replaced with spaces like in TrimTemplate,
+// actions inside of quoted strings and comments as well. Inside of block scalars actions on their own
+// line are replaced with text, so that they can not change the indentation of the block.
+func ProjectTemplate(gotemplateTree *sitter.Tree, content []byte) TemplateProjection {
+	result := []byte(TrimTemplate(gotemplateTree, content))
+	// the lines of the result, the placeholders have the length of the replaced spaces
+	// so only the content of the line of a placeholder changes
+	lines := strings.Split(string(result), "\n")
+	projection := TemplateProjection{
+		Placeholders:   []Placeholder{},
+		templateRanges: GetTemplateRanges(gotemplateTree, content),
+	}
+
+	keyCount := 0
+	for _, action := range getActions(gotemplateTree.RootNode()) {
+		actionRange := sitter.Range{
+			StartPoint: pointForOffset(content, int(action.start)),
+			EndPoint:   pointForOffset(content, int(action.end)),
+			StartByte:  action.start,
+			EndByte:    action.end,
+		}
+		// the placeholder has to fit into the first line of the action
+		available := int(action.end - action.start)
+		if newline := bytes.IndexByte(content[action.start:action.end], '\n'); newline >= 0 {
+			available = newline
+		}
+		lineNumber := int(actionRange.StartPoint.Row)
+		kind, text := getPlaceholder(result, lines, lineNumber, content, action, available)
+		if kind == PlaceholderKey || kind == PlaceholderMappingEntry {
+			text = strings.Replace(text, "%", "_h"+strconv.Itoa(keyCount), 1)
+			keyCount++
+		}
+		if kind == PlaceholderNone || len(text) > available {
+			continue
+		}
+		copy(result[action.start:], text)
+		lineStart := int(action.start) - int(actionRange.StartPoint.Column)
+		lines[lineNumber] = string(result[lineStart : lineStart+len(lines[lineNumber])])
+		projection.Placeholders = append(projection.Placeholders, Placeholder{Kind: kind, Range: actionRange, Text: text})
+	}
+
+	projection.Text = string(result)
+	return projection
+}
+
+// IsInTemplate returns true if the position is part of a template action
+func (p TemplateProjection) IsInTemplate(position lsp.Position) bool {
+	_, ok := p.getTemplateRange(position)
+	return ok
+}
+
+// ToTemplateRange translates a range of the projection to the template. Ranges that start in an action
+// describe the placeholder rather than the template and are not translated. Ranges that end in an
+// action are extended to the end of the action, so that they do not cut it.
+func (p TemplateProjection) ToTemplateRange(r lsp.Range) (lsp.Range, bool) {
+	if p.IsInTemplate(r.Start) {
+		return lsp.Range{}, false
+	}
+	if templateRange, ok := p.getTemplateRange(r.End); ok && positionOf(templateRange.StartPoint) != r.End {
+		r.End = positionOf(templateRange.EndPoint)
+	}
+	return r, true
+}
+
+func (p TemplateProjection) getTemplateRange(position lsp.Position) (sitter.Range, bool) {
+	for _, templateRange := range p.templateRanges {
+		start, end := positionOf(templateRange.StartPoint), positionOf(templateRange.EndPoint)
+		if !isBefore(position, start) && isBefore(position, end) {
+			return templateRange, true
+		}
+	}
+	return sitter.Range{}, false
+}
+
+func positionOf(point sitter.Point) lsp.Position {
+	return lsp.Position{Line: point.Row, Character: point.Column}
+}
+
+func isBefore(a, b lsp.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// templateAction is an action of the template from the opening to the closing braces
+type templateAction struct {
+	start, end uint32
+	// pipeline is the content of actions that output something, nil for control structures etc.
+	pipeline *sitter.Node
+}
+
+// getActions returns the actions of the template in the order of the document
+func getActions(node *sitter.Node) []templateAction {
+	result := []templateAction{}
+	var open *sitter.Node
+	contents := []*sitter.Node{}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case gotemplate.NodeTypeOpenBraces, gotemplate.NodeTypeOpenBracesDash:
+			open, contents = child, []*sitter.Node{}
+		case gotemplate.NodeTypeCloseBraces, gotemplate.NodeTypeCloseBracesDash:
+			if open != nil {
+				result = append(result, templateAction{
+					start:    open.EndByte() - uint32(len(open.Type())),
+					end:      child.EndByte(),
+					pipeline: getOutputPipeline(contents),
+				})
+			}
+			open = nil
+		case gotemplate.NodeTypeTemplateAction:
+			result = append(result, templateAction{start: child.StartByte(), end: child.EndByte(), pipeline: child})
+		default:
+			if open != nil {
+				contents = append(contents, child)
+			} else if child.Type() != gotemplate.NodeTypeText {
+				result = append(result, getActions(child)...)
+			}
+		}
+	}
+	return result
+}
+
+// getOutputPipeline returns the pipeline of an action that outputs something
+func getOutputPipeline(contents []*sitter.Node) *sitter.Node {
+	if len(contents) != 1 || !contents[0].IsNamed() {
+		return nil
+	}
+	switch contents[0].Type() {
+	case gotemplate.NodeTypeVariableDefinition, gotemplate.NodeTypeAssignment, gotemplate.NodeTypeComment, gotemplate.NodeTypeError:
+		return nil
+	}
+	return contents[0]
+}
+
+// getPlaceholder selects the placeholder for the action based on the projection of the text before it.
+// Keys contain a % that is replaced with a unique name.
+func getPlaceholder(projection []byte, lines []string, lineNumber int, content []byte, action templateAction, available int) (PlaceholderKind, string) {
+	lineStart := bytes.LastIndexByte(projection[:action.start], '\n') + 1
+	lineEnd := bytes.IndexByte(projection[action.end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(projection)
+	} else {
+		lineEnd += int(action.end)
+	}
+	prefix := string(projection[lineStart:action.start])
+	suffix := strings.TrimRight(string(projection[action.end:lineEnd]), "\r")
+	trimmedPrefix, trimmedSuffix := strings.TrimSpace(prefix), strings.TrimSpace(suffix)
+	column := int(action.start) - lineStart
+
+	if isInCommentOrString(prefix) {
+		return PlaceholderNone, ""
+	}
+	if trimmedPrefix == "" && trimmedSuffix == "" {
+		if indent, ok := getBlockScalarContentIndent(lines, lineNumber, column); ok {
+			if indent < 0 || indent == column {
+				return PlaceholderScalar, "x"
+			}
+			return PlaceholderNone, ""
+		}
+	}
+	if action.pipeline == nil {
+		return PlaceholderNone, ""
+	}
+
+	touchesText := (prefix != "" && !isSpace(prefix[len(prefix)-1])) || (suffix != "" && !isSpace(suffix[0]))
+	switch {
+	case strings.HasPrefix(suffix, ":") && (trimmedPrefix == "" || trimmedPrefix == "-" || trimmedPrefix == "?"):
+		return PlaceholderKey, "%"
+	case touchesText:
+		return PlaceholderScalar, strings.Repeat("x", available)
+	case trimmedPrefix == "" && trimmedSuffix == "":
+		return getPlaceholderForLine(lines, lineNumber, content, column, action.pipeline)
+	case (strings.HasSuffix(trimmedPrefix, ":") || trimmedPrefix == "-" || strings.HasSuffix(trimmedPrefix, " -")) &&
+		(trimmedSuffix == "" || strings.HasPrefix(trimmedSuffix, "#")):
+		if getPipelineFunction(action.pipeline, content, blockFunctions) {
+			return PlaceholderObject, "{}"
+		}
+		return PlaceholderScalar, getScalarPlaceholder(action.pipeline, content)
+	}
+	return PlaceholderNone, ""
+}
+
+// getPlaceholderForLine selects the placeholder for an action that is the only content of its line
+// by the lines around it: the action outputs entries like its siblings or the value of the key above
+func getPlaceholderForLine(lines []string, lineNumber int, content []byte, column int, pipeline *sitter.Node) (PlaceholderKind, string) {
+	siblingKind := func(line projectedLine) (PlaceholderKind, string, bool) {
+		switch {
+		case line.isListItem && line.indent == column:
+			return PlaceholderSequenceEntry, "-", true
+		case line.isKey && line.keyColumn == column:
+			return PlaceholderMappingEntry, "%:", true
+		}
+		return PlaceholderNone, "", false
+	}
+
+	if next, ok := findLine(lines, lineNumber+1, 1); ok {
+		if kind, text, ok := siblingKind(next); ok {
+			return kind, text
+		}
+	}
+	previous, ok := findLine(lines, lineNumber-1, -1)
+	if !ok {
+		return PlaceholderNone, ""
+	}
+	if kind, text, ok := siblingKind(previous); ok {
+		return kind, text
+	}
+	if previous.opensBlock && previous.keyColumn < column {
+		if getPipelineFunction(pipeline, content, blockFunctions) {
+			return PlaceholderObject, "{}"
+		}
+		return PlaceholderScalar, getScalarPlaceholder(pipeline, content)
+	}
+	return PlaceholderNone, ""
+}
+
+// projectedLine is a line of the projection reduced to its structure
+type projectedLine struct {
+	indent     int
+	keyColumn  int
+	isListItem bool
+	isKey      bool
+	// opensBlock is true if the line ends with a key or a list item without a value
+	opensBlock bool
+}
+
+// findLine returns the next line with content starting at the line number in the direction
+func findLine(lines []string, lineNumber int, direction int) (projectedLine, bool) {
+	for i := lineNumber; i >= 0 && i < len(lines); i += direction {
+		content := strings.TrimLeft(lines[i], " ")
+		trimmed := strings.TrimSpace(content)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "---") {
+			return projectedLine{}, false
+		}
+
+		line := projectedLine{indent: len(lines[i]) - len(content)}
+		line.keyColumn = line.indent
+		for content == "-" || strings.HasPrefix(content, "- ") {
+			line.isListItem = true
+			rest := strings.TrimLeft(content[1:], " ")
+			line.keyColumn += len(content) - len(rest)
+			content = rest
+		}
+		trimmed = strings.TrimSpace(content)
+		line.isKey = strings.Contains(content, ": ") || strings.HasSuffix(trimmed, ":")
+		line.opensBlock = trimmed == "" || strings.HasSuffix(trimmed, ":")
+		return line, true
+	}
+	return projectedLine{}, false
+}
+
+// getBlockScalarContentIndent checks if the line is part of a block scalar (e.g. `key: |`).
+// It returns the indentation of the next line of the block scalar or -1 if there is none.
+func getBlockScalarContentIndent(lines []string, lineNumber int, column int) (int, bool) {
+	minIndent := column
+	headerIndent := -1
+	for i := lineNumber - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+		indent := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+		if indent >= minIndent {
+			continue
+		}
+		if blockScalarHeaderRegex.MatchString(strings.TrimRight(lines[i], "\r")) {
+			headerIndent = indent
+			break
+		}
+		minIndent = indent
+	}
+	if headerIndent < 0 {
+		return 0, false
+	}
+
+	for i := lineNumber + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		indent := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+		if indent <= headerIndent {
+			break
+		}
+		return indent, true
+	}
+	return -1, true
+}
+
+// getScalarPlaceholder returns a scalar of the type the pipeline returns
+func getScalarPlaceholder(pipeline *sitter.Node, content []byte) string {
+	switch {
+	case getPipelineFunction(pipeline, content, stringFunctions), pipeline.Type() == gotemplate.NodeTypeInterpretedStringLiteral,
+		pipeline.Type() == gotemplate.NodeTypeRawStringLiteral:
+		return `""`
+	case getPipelineFunction(pipeline, content, numberFunctions), pipeline.Type() == gotemplate.NodeTypeIntLiteral,
+		pipeline.Type() == gotemplate.NodeTypeFloatLiteral:
+		return "0"
+	case getPipelineFunction(pipeline, content, booleanFunctions), pipeline.Type() == gotemplate.NodeTypeTrue,
+		pipeline.Type() == gotemplate.NodeTypeFalse:
+		return "true"
+	}
+	return "x"
+}
+
+// getPipelineFunction returns true if the last function of the pipeline is one of the functions
+func getPipelineFunction(pipeline *sitter.Node, content []byte, functions []string) bool {
+	last := pipeline
+	if pipeline.Type() == gotemplate.NodeTypeChainedPipeline && pipeline.NamedChildCount() > 0 {
+		last = pipeline.NamedChild(int(pipeline.NamedChildCount()) - 1)
+	}
+	if last.Type() != gotemplate.NodeTypeFunctionCall {
+		return false
+	}
+	function := last.ChildByFieldName(gotemplate.FieldNameFunction)
+	if function == nil {
+		function = last.NamedChild(0)
+	}
+	return function != nil && slices.Contains(functions, function.Content(content))
+}
+
+// isInCommentOrString returns true if the text before the action on its line opens a comment or a quoted string
+func isInCommentOrString(prefix string) bool {
+	var quote rune
+	previous := ' '
+	for _, c := range prefix {
+		switch {
+		case quote == 0 && c == '#' && isSpace(byte(previous)):
+			return true
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == '"' && c == '"' && previous != '\\', quote == '\'' && c == '\'':
+			quote = 0
+		}
+		previous = c
+	}
+	return quote != 0
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
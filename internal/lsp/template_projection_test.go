@@ -0,0 +1,145 @@
+package lsp
+
+import (
+	"strings"
+	"testing"
+
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"gopkg.in/yaml.v3"
+)
+
+func TestProjectTemplate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		template string
+		expected string
+	}{
+		{
+			desc:     "scalar values by the type of the pipeline",
+			template: "a: {{ .Values.a | quote }}\nb: {{ .Values.b | int }}\nc: {{ .Values.c }}\nd: {{ eq .Values.d 1 }}",
+			expected: "a: \"\"\nb: 0\nc: x\nd: true",
+		},
+		{
+			desc:     "actions that are part of a scalar",
+			template: "name: {{ .Release.Name }}-{{ .Values.suffix }}\nimage: \"{{ .Values.image }}\"\n# {{ .Values.comment }}",
+			expected: "name: xxxxxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxxxxxx\nimage: \"                   \"\n#",
+		},
+		{
+			desc:     "keys",
+			template: "{{ $key }}: {{ $value }}\nlist:\n  - {{ .key }}: a",
+			expected: "_h0       : x\nlist:\n  - _h1       : a",
+		},
+		{
+			desc:     "mapping entries next to keys",
+			template: "labels:\n  a: b\n  {{- include \"labels\" . | nindent 2 }}",
+			expected: "labels:\n  a: b\n  _h0:",
+		},
+		{
+			desc:     "sequence items next to items",
+			template: "containers:\n  - name: a\n  {{- include \"containers\" . | nindent 2 }}",
+			expected: "containers:\n  - name: a\n  -",
+		},
+		{
+			desc:     "blocks as values",
+			template: "resources:\n  {{- toYaml .Values.resources | nindent 2 }}\nlabels: {{- include \"labels\" . | nindent 2 }}",
+			expected: "resources:\n  {}\nlabels: {}",
+		},
+		{
+			desc:     "control structures and variables are removed",
+			template: "{{- $name := .Release.Name }}\n{{- if .Values.a }}\na: b\n{{- end }}",
+			expected: "\n\na: b\n",
+		},
+		{
+			desc:     "block scalars",
+			template: "data:\n  config: |\n    {{- if .Values.a }}\n    a: b\n    {{- end }}\nb: c",
+			expected: "data:\n  config: |\n    x\n    a: b\n    x\nb: c",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			projection := ProjectTemplate(templateast.ParseAst(nil, []byte(tt.template)), []byte(tt.template))
+			assert.Equal(t, tt.expected, trimTrailingSpaces(projection.Text))
+			assert.Len(t, projection.Text, len(tt.template))
+
+			var node yaml.Node
+			assert.NoError(t, yaml.Unmarshal([]byte(projection.Text), &node))
+		})
+	}
+}
+
+func trimTrailingSpaces(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestProjectTemplateKeepsValidYaml(t *testing.T) {
+	template := `params:
+{{- range $key, $value := .params }}
+{{ $key }}:
+  {{- range $value }}
+  - {{ . | quote }}
+  {{- end }}
+{{- end }}
+metadata:
+  labels:
+    {{- include "labels" . | nindent 4 }}
+  annotations:
+    a: b
+    {{- with .Values.annotations }}
+    {{- toYaml . | nindent 4 }}
+    {{- end }}
+`
+	projection := ProjectTemplate(templateast.ParseAst(nil, []byte(template)), []byte(template))
+
+	var node yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(projection.Text), &node))
+	assert.Equal(t, strings.Count(template, "\n"), strings.Count(projection.Text, "\n"))
+
+	kinds := []PlaceholderKind{}
+	for _, placeholder := range projection.Placeholders {
+		kinds = append(kinds, placeholder.Kind)
+	}
+	assert.Equal(t, []PlaceholderKind{PlaceholderKey, PlaceholderScalar, PlaceholderObject, PlaceholderMappingEntry}, kinds)
+}
+
+func TestToTemplateRange(t *testing.T) {
+	template := "a: {{ .Values.a }}\nb: c\n"
+	projection := ProjectTemplate(templateast.ParseAst(nil, []byte(template)), []byte(template))
+
+	testCases := []struct {
+		desc       string
+		r          lsp.Range
+		expected   lsp.Range
+		expectedOk bool
+	}{
+		{
+			desc:       "range in the text",
+			r:          lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 4}},
+			expected:   lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 4}},
+			expectedOk: true,
+		},
+		{
+			desc:       "range in a placeholder",
+			r:          lsp.Range{Start: lsp.Position{Line: 0, Character: 3}, End: lsp.Position{Line: 0, Character: 4}},
+			expectedOk: false,
+		},
+		{
+			desc:       "range that ends in a placeholder is extended to the end of the action",
+			r:          lsp.Range{Start: lsp.Position{Line: 0, Character: 0}, End: lsp.Position{Line: 0, Character: 4}},
+			expected:   lsp.Range{Start: lsp.Position{Line: 0, Character: 0}, End: lsp.Position{Line: 0, Character: 18}},
+			expectedOk: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			result, ok := projection.ToTemplateRange(tt.r)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package lsp
 
 import (
+	"bytes"
+
 	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
 	sitter "github.com/smacker/go-tree-sitter"
 )
@@ -39,3 +41,46 @@ func TrimTemplate(gotemplateTree *sitter.Tree, content []byte) string {
 	}
 	return string(result)
 }
+
+// GetTemplateRanges returns the ranges of the template actions that are removed by TrimTemplate,
+// without the surrounding whitespace. The trimmed content has the same positions as the original
+// content, so results of yamlls only have to be checked against these ranges.
+func GetTemplateRanges(gotemplateTree *sitter.Tree, content []byte) []sitter.Range {
+	root := gotemplateTree.RootNode()
+	templateRanges := []sitter.Range{}
+	previousEnd := root.StartByte()
+
+	addGap := func(endByte uint32) {
+		start, end := int(previousEnd), int(endByte)
+		for start < end && isWhitespace(content[start]) {
+			start++
+		}
+		for end > start && isWhitespace(content[end-1]) {
+			end--
+		}
+		if start < end {
+			templateRanges = append(templateRanges, sitter.Range{
+				StartPoint: pointForOffset(content, start),
+				EndPoint:   pointForOffset(content, end),
+				StartByte:  uint32(start),
+				EndByte:    uint32(end),
+			})
+		}
+	}
+	for _, textRange := range getTextNodeRanges(root) {
+		addGap(textRange.StartByte)
+		previousEnd = textRange.EndByte
+	}
+	addGap(root.EndByte())
+	return templateRanges
+}
+
+func isWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+func pointForOffset(content []byte, offset int) sitter.Point {
+	before := content[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return sitter.Point{Row: uint32(bytes.Count(before, []byte{'\n'})), Column: uint32(offset - lineStart)}
+}
@@ -1,6 +1,7 @@
 package lsp
 
 import (
+	"strings"
 	"testing"
 
 	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
@@ -128,3 +129,26 @@ yaml: test
 		})
 	}
 }
+
+func TestGetTemplateRanges(t *testing.T) {
+	documentText := "a: {{ .Values.a }}\nb: c\n{{- if .Values.d }}\nd: e\n{{- end }}\n"
+	gotemplateTree := templateast.ParseAst(nil, []byte(documentText))
+
+	contents := []string{}
+	for _, templateRange := range GetTemplateRanges(gotemplateTree, []byte(documentText)) {
+		contents = append(contents, documentText[templateRange.StartByte:templateRange.EndByte])
+	}
+	assert.Equal(t, []string{"{{ .Values.a }}", "{{- if .Values.d }}", "{{- end }}"}, contents)
+
+	assert.Equal(t, sitter.Range{
+		StartPoint: sitter.Point{Row: 4, Column: 0},
+		EndPoint:   sitter.Point{Row: 4, Column: 10},
+		StartByte:  49,
+		EndByte:    59,
+	}, GetTemplateRanges(gotemplateTree, []byte(documentText))[2])
+
+	trimmed := TrimTemplate(gotemplateTree, []byte(documentText))
+	for _, templateRange := range GetTemplateRanges(gotemplateTree, []byte(documentText)) {
+		assert.Empty(t, strings.TrimSpace(trimmed[templateRange.StartByte:templateRange.EndByte]))
+	}
+}
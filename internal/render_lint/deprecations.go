@@ -0,0 +1,216 @@
+package renderlint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	"github.com/mrjosh/helm-ls/internal/util"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+type kubeVersion struct {
+	major int
+	minor int
+}
+
+func (v kubeVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+func (v kubeVersion) atLeast(other kubeVersion) bool {
+	return v.major > other.major || (v.major == other.major && v.minor >= other.minor)
+}
+
+// deprecatedAPI is an apiVersion of a kind that is deprecated or removed in a Kubernetes version
+type deprecatedAPI struct {
+	apiVersion   string
+	kind         string
+	deprecatedIn kubeVersion
+	removedIn    kubeVersion
+	replacement  string
+	// mechanical is true if only the apiVersion has to be changed to the replacement
+	mechanical bool
+}
+
+var deprecatedAPIs = []deprecatedAPI{
+	{"extensions/v1beta1", "Deployment", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", false},
+	{"extensions/v1beta1", "DaemonSet", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", false},
+	{"extensions/v1beta1", "ReplicaSet", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", false},
+	{"extensions/v1beta1", "NetworkPolicy", kubeVersion{1, 9}, kubeVersion{1, 16}, "networking.k8s.io/v1", true},
+	{"extensions/v1beta1", "PodSecurityPolicy", kubeVersion{1, 10}, kubeVersion{1, 16}, "policy/v1beta1", true},
+	{"extensions/v1beta1", "Ingress", kubeVersion{1, 14}, kubeVersion{1, 22}, "networking.k8s.io/v1", false},
+	{"apps/v1beta1", "Deployment", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", false},
+	{"apps/v1beta1", "StatefulSet", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", false},
+	{"apps/v1beta2", "Deployment", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", true},
+	{"apps/v1beta2", "DaemonSet", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", true},
+	{"apps/v1beta2", "ReplicaSet", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", true},
+	{"apps/v1beta2", "StatefulSet", kubeVersion{1, 9}, kubeVersion{1, 16}, "apps/v1", true},
+	{"networking.k8s.io/v1beta1", "Ingress", kubeVersion{1, 19}, kubeVersion{1, 22}, "networking.k8s.io/v1", false},
+	{"networking.k8s.io/v1beta1", "IngressClass", kubeVersion{1, 19}, kubeVersion{1, 22}, "networking.k8s.io/v1", true},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", kubeVersion{1, 17}, kubeVersion{1, 22}, "rbac.authorization.k8s.io/v1", true},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", kubeVersion{1, 17}, kubeVersion{1, 22}, "rbac.authorization.k8s.io/v1", true},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", kubeVersion{1, 17}, kubeVersion{1, 22}, "rbac.authorization.k8s.io/v1", true},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", kubeVersion{1, 17}, kubeVersion{1, 22}, "rbac.authorization.k8s.io/v1", true},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", kubeVersion{1, 16}, kubeVersion{1, 22}, "apiextensions.k8s.io/v1", false},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", kubeVersion{1, 16}, kubeVersion{1, 22}, "admissionregistration.k8s.io/v1", false},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", kubeVersion{1, 16}, kubeVersion{1, 22}, "admissionregistration.k8s.io/v1", false},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", kubeVersion{1, 14}, kubeVersion{1, 22}, "scheduling.k8s.io/v1", true},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", kubeVersion{1, 19}, kubeVersion{1, 22}, "certificates.k8s.io/v1", false},
+	{"coordination.k8s.io/v1beta1", "Lease", kubeVersion{1, 14}, kubeVersion{1, 22}, "coordination.k8s.io/v1", true},
+	{"batch/v1beta1", "CronJob", kubeVersion{1, 21}, kubeVersion{1, 25}, "batch/v1", true},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", kubeVersion{1, 21}, kubeVersion{1, 25}, "discovery.k8s.io/v1", false},
+	{"events.k8s.io/v1beta1", "Event", kubeVersion{1, 19}, kubeVersion{1, 25}, "events.k8s.io/v1", false},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", kubeVersion{1, 22}, kubeVersion{1, 25}, "autoscaling/v2", true},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", kubeVersion{1, 23}, kubeVersion{1, 26}, "autoscaling/v2", true},
+	{"policy/v1beta1", "PodDisruptionBudget", kubeVersion{1, 21}, kubeVersion{1, 25}, "policy/v1", true},
+	{"policy/v1beta1", "PodSecurityPolicy", kubeVersion{1, 21}, kubeVersion{1, 25}, "", false},
+	{"node.k8s.io/v1beta1", "RuntimeClass", kubeVersion{1, 20}, kubeVersion{1, 25}, "node.k8s.io/v1", true},
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", kubeVersion{1, 24}, kubeVersion{1, 27}, "storage.k8s.io/v1", true},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", kubeVersion{1, 26}, kubeVersion{1, 29}, "flowcontrol.apiserver.k8s.io/v1", true},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", kubeVersion{1, 26}, kubeVersion{1, 29}, "flowcontrol.apiserver.k8s.io/v1", false},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", kubeVersion{1, 29}, kubeVersion{1, 32}, "flowcontrol.apiserver.k8s.io/v1", true},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", kubeVersion{1, 29}, kubeVersion{1, 32}, "flowcontrol.apiserver.k8s.io/v1", true},
+}
+
+func getDeprecatedAPI(apiVersion, kind string) (deprecatedAPI, bool) {
+	for _, api := range deprecatedAPIs {
+		if api.apiVersion == apiVersion && api.kind == kind {
+			return api, true
+		}
+	}
+	return deprecatedAPI{}, false
+}
+
+// getTargetKubeVersion returns the configured Kubernetes version or the default version of helm
+func getTargetKubeVersion(config util.RenderConfig) (kubeVersion, error) {
+	version := &chartutil.DefaultCapabilities.KubeVersion
+	if config.KubeVersion != "" {
+		parsed, err := chartutil.ParseKubeVersion(config.KubeVersion)
+		if err != nil {
+			return kubeVersion{}, err
+		}
+		version = parsed
+	}
+	major, err := strconv.Atoi(strings.TrimRight(version.Major, "+"))
+	if err != nil {
+		return kubeVersion{}, err
+	}
+	minor, err := strconv.Atoi(strings.TrimRight(version.Minor, "+"))
+	if err != nil {
+		return kubeVersion{}, err
+	}
+	return kubeVersion{major, minor}, nil
+}
+
+// getDeprecationDiagnostics reports apiVersions of the rendered objects that are deprecated or removed in the
+// target Kubernetes version. Branches of the template that are guarded by a check of .Capabilities are skipped.
+func getDeprecationDiagnostics(sourceMap *helmrender.SourceMap, documents []renderedDocument, root *sitter.Node, content []byte,
+	config util.RenderConfig,
+) []lsp.Diagnostic {
+	diagnostics := []lsp.Diagnostic{}
+	target, err := getTargetKubeVersion(config)
+	if err != nil {
+		logger.Debug("Could not parse kube version", err)
+		return diagnostics
+	}
+
+	for _, document := range documents {
+		if document.node == nil {
+			continue
+		}
+		_, apiVersionNode := findMappingValue(document.node, "apiVersion")
+		_, kindNode := findMappingValue(document.node, "kind")
+		if apiVersionNode == nil || kindNode == nil {
+			continue
+		}
+		api, ok := getDeprecatedAPI(apiVersionNode.Value, kindNode.Value)
+		if !ok || !target.atLeast(api.deprecatedIn) {
+			continue
+		}
+		if diagnostic, ok := buildDeprecationDiagnostic(sourceMap, root, content, apiVersionNode, api, target); ok {
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+	return diagnostics
+}
+
+func buildDeprecationDiagnostic(sourceMap *helmrender.SourceMap, root *sitter.Node, content []byte,
+	apiVersionNode *yaml.Node, api deprecatedAPI, target kubeVersion,
+) (lsp.Diagnostic, bool) {
+	diagnostic := lsp.Diagnostic{
+		Severity:        lsp.DiagnosticSeverityWarning,
+		Code:            diagnosticrules.RenderLintDeprecatedAPI.Code,
+		CodeDescription: diagnosticrules.RenderLintDeprecatedAPI.CodeDescription(),
+		Source:          diagnosticsSource,
+		Message:         fmt.Sprintf("%s %s is deprecated since Kubernetes %s", api.apiVersion, api.kind, api.deprecatedIn),
+	}
+	if target.atLeast(api.removedIn) {
+		diagnostic.Severity = lsp.DiagnosticSeverityError
+		diagnostic.Message = fmt.Sprintf("%s %s was removed in Kubernetes %s", api.apiVersion, api.kind, api.removedIn)
+	}
+	if api.replacement != "" {
+		diagnostic.Message += fmt.Sprintf(", use %s instead", api.replacement)
+	}
+
+	outputOffset := lineOffset(sourceMap.Output, apiVersionNode.Line-1) + apiVersionNode.Column - 1
+	if action, ok := sourceMap.GetActionAt(outputOffset); ok {
+		if isGuardedByCapabilities(root, content, int(action.Action.StartByte())) {
+			return lsp.Diagnostic{}, false
+		}
+		diagnostic.Range = templateast.GetLspRangeForNode(action.Action)
+		return diagnostic, true
+	}
+
+	// the rendering already chose the branch for the configured capabilities, an apiVersion that is
+	// written in a guarded branch is the intended fallback for other clusters
+	sourceOffset := sourceMap.GetSourceOffset(outputOffset)
+	if isGuardedByCapabilities(root, content, sourceOffset) {
+		return lsp.Diagnostic{}, false
+	}
+	start := offsetToPosition(content, sourceOffset)
+	diagnostic.Range = lsp.Range{Start: start, End: lsp.Position{Line: start.Line, Character: start.Character + uint32(len(apiVersionNode.Value))}}
+	if api.mechanical && strings.HasPrefix(string(content[min(sourceOffset, len(content)):]), api.apiVersion) {
+		diagnostic.Data = QuickFixData{Title: fmt.Sprintf("Use %s", api.replacement), NewText: api.replacement}
+	}
+	return diagnostic, true
+}
+
+// isGuardedByCapabilities returns true if the offset is inside of the fallback branch of an if action
+// that checks the api versions of the cluster, e.g. the else branch of {{ if .Capabilities.APIVersions.Has "policy/v1" }}
+func isGuardedByCapabilities(node *sitter.Node, content []byte, offset int) bool {
+	if offset < int(node.StartByte()) || offset >= int(node.EndByte()) {
+		return false
+	}
+	if node.Type() == gotemplate.NodeTypeIfAction {
+		checked, fallback := false, false
+		for i := 0; i < int(node.ChildCount()); i++ {
+			child := node.Child(i)
+			switch node.FieldNameForChild(i) {
+			case gotemplate.FieldNameCondition:
+				// the branch of a condition is the fallback of all checks before it
+				fallback = checked
+				checked = checked || strings.Contains(child.Content(content), ".Capabilities.APIVersions.Has")
+			case gotemplate.FieldNameAlternative:
+				fallback = checked
+			}
+			if fallback && node.FieldNameForChild(i) != gotemplate.FieldNameCondition &&
+				offset >= int(child.StartByte()) && offset < int(child.EndByte()) {
+				return true
+			}
+		}
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if isGuardedByCapabilities(node.NamedChild(i), content, offset) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package renderlint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+)
+
+// GetIndentDiagnostics reports indent and nindent calls whose indentation does not match the
+// column of the action, e.g. {{ include "labels" . | indent 4 }} in column 4 indents the first
+// line by 8 spaces
+func GetIndentDiagnostics(root *sitter.Node, content []byte) []lsp.Diagnostic {
+	diagnostics := []lsp.Diagnostic{}
+
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if node.Type() == gotemplate.NodeTypeFunctionCall {
+			if diagnostic, ok := getIndentDiagnostic(node, content); ok {
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	walk(root)
+	return diagnostics
+}
+
+func getIndentDiagnostic(node *sitter.Node, content []byte) (lsp.Diagnostic, bool) {
+	function, arguments := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+	if function == nil || arguments == nil || arguments.NamedChildCount() == 0 {
+		return lsp.Diagnostic{}, false
+	}
+	functionName := function.Content(content)
+	spacesNode := arguments.NamedChild(0)
+	if (functionName != "indent" && functionName != "nindent") || spacesNode.Type() != gotemplate.NodeTypeIntLiteral {
+		return lsp.Diagnostic{}, false
+	}
+	spaces, err := strconv.Atoi(spacesNode.Content(content))
+	if err != nil {
+		return lsp.Diagnostic{}, false
+	}
+
+	action := helmrender.GetActionForNode(node)
+	if action == nil || !isResultOfAction(node, action) {
+		return lsp.Diagnostic{}, false
+	}
+	open := action.PrevSibling()
+	if open == nil || (open.Type() != gotemplate.NodeTypeOpenBraces && open.Type() != gotemplate.NodeTypeOpenBracesDash) {
+		return lsp.Diagnostic{}, false
+	}
+	trimmed := open.Type() == gotemplate.NodeTypeOpenBracesDash
+	bracesStart := int(open.EndByte()) - len(open.Type())
+	lineStart := strings.LastIndexByte(string(content[:bracesStart]), '\n') + 1
+	if strings.TrimSpace(string(content[lineStart:bracesStart])) != "" {
+		// the action is not the first element of the line, e.g. key: {{ .Values.foo | nindent 2 }}
+		return lsp.Diagnostic{}, false
+	}
+	column := bracesStart - lineStart
+
+	message, data := "", interface{}(nil)
+	switch {
+	case functionName == "indent" && trimmed:
+		message = fmt.Sprintf("{{- removes the line break before the action, so the first line of indent %d is appended to the previous line. Use nindent %d instead", spaces, spaces)
+	case functionName == "indent" && column > 0:
+		message = fmt.Sprintf("indent %d in column %d indents the first line by %d spaces. Use {{- with nindent %d instead", spaces, column, column+spaces, spaces)
+	case functionName == "nindent" && spaces != column && !isWrittenAtParentColumn(content, lineStart, column, trimmed):
+		message = fmt.Sprintf("nindent %d does not match the column of the action. Use nindent %d", spaces, column)
+		data = QuickFixData{Title: fmt.Sprintf("Use nindent %d", column), NewText: strconv.Itoa(column)}
+	default:
+		return lsp.Diagnostic{}, false
+	}
+	return lsp.Diagnostic{
+		Range:           templateast.GetLspRangeForNode(spacesNode),
+		Severity:        lsp.DiagnosticSeverityWarning,
+		Code:            diagnosticrules.RenderLintIndent.Code,
+		CodeDescription: diagnosticrules.RenderLintIndent.CodeDescription(),
+		Source:          diagnosticsSource,
+		Message:         message,
+		Data:            data,
+	}, true
+}
+
+// isResultOfAction returns true if the output of the function call is the output of the action
+func isResultOfAction(functionCall *sitter.Node, action *sitter.Node) bool {
+	if action.Equal(functionCall) {
+		return true
+	}
+	if action.Type() != gotemplate.NodeTypeChainedPipeline || action.NamedChildCount() == 0 {
+		return false
+	}
+	return action.NamedChild(int(action.NamedChildCount()) - 1).Equal(functionCall)
+}
+
+// isWrittenAtParentColumn returns true if a trimmed action is written at or before the column of
+// the key it belongs to, e.g.
+//
+//	labels:
+//	{{- include "labels" . | nindent 2 }}
+func isWrittenAtParentColumn(content []byte, lineStart int, column int, trimmed bool) bool {
+	if !trimmed {
+		return false
+	}
+	lines := strings.Split(string(content[:max(lineStart-1, 0)]), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimRight(lines[i], " \t")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indentation := len(line) - len(strings.TrimLeft(line, " "))
+		return strings.HasSuffix(line, ":") && indentation >= column
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package renderlint
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	"github.com/mrjosh/helm-ls/internal/log"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/util"
+	lsp "go.lsp.dev/protocol"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const diagnosticsSource = "Helm-ls RenderLint"
+
+// QuickFixData is stored in the data of a diagnostic that can be fixed by replacing its range with NewText
+type QuickFixData struct {
+	Title   string `json:"title"`
+	NewText string `json:"newText"`
+}
+
+// IsRenderLintDiagnostic returns true if the diagnostic was created by this package
+func IsRenderLintDiagnostic(diagnostic lsp.Diagnostic) bool {
+	return diagnostic.Source == diagnosticsSource
+}
+
+var (
+	logger = log.GetLogger()
+
+	yamlErrorRegex = regexp.MustCompile(`^yaml: line ([0-9]+): (.*)$`)
+)
+
+// GetDiagnostics renders the template with the values and reports errors of the rendered YAML
+// at the actions or lines of the template that produced them. Valid YAML is validated against the
+// bundled Kubernetes schemas (or the CRDs of the chart from crdSchemas) and checked for deprecated apiVersions. Additionally indent and nindent calls that do not match the column of
+// their action are reported.
+func GetDiagnostics(chart *charts.Chart, doc *document.TemplateDocument, vals chartutil.Values, config util.RenderConfig,
+	crdSchemas *kubernetesschema.CRDSchemaCache,
+) []lsp.Diagnostic {
+	diagnostics := []lsp.Diagnostic{}
+	if doc.Ast == nil || !isYamlTemplate(doc.Path) {
+		return diagnostics
+	}
+	diagnostics = append(diagnostics, GetIndentDiagnostics(doc.Ast.RootNode(), doc.Content)...)
+
+	sourceMap, err := helmrender.RenderTemplateWithSourceMap(chart, doc.Path, doc.Content, doc.Ast, vals, config)
+	if err != nil {
+		// errors of the template itself are reported by helm lint
+		logger.Debug("Could not render template for linting", doc.Path, err)
+		return diagnostics
+	}
+	// invalid documents are reported, the others are still checked
+	documents := decodeDocuments(sourceMap.Output)
+	for _, document := range documents {
+		if document.err != nil {
+			diagnostics = append(diagnostics, buildYamlDiagnostic(sourceMap, doc.Content, document.err, document.startLine))
+		}
+	}
+	diagnostics = append(diagnostics, getDeprecationDiagnostics(sourceMap, documents, doc.Ast.RootNode(), doc.Content, config)...)
+	if config.ValidationEnabled {
+		diagnostics = append(diagnostics, getValidationDiagnostics(sourceMap, documents, doc.Content, crdSchemas.Get(chart))...)
+	}
+	return diagnostics
+}
+
+func isYamlTemplate(path string) bool {
+	base := filepath.Base(path)
+	extension := filepath.Ext(base)
+	return !strings.HasPrefix(base, "_") && (extension == ".yaml" || extension == ".yml")
+}
+
+// renderedDocument is a YAML document of the rendered output, err is set if it could not be decoded
+type renderedDocument struct {
+	node *yaml.Node
+	err  error
+	// startLine is the zero based line of the output where the document starts
+	startLine int
+}
+
+// decodeDocuments decodes each document of the rendered output on its own, so that an invalid document
+// does not hide the documents after it. The lines of the nodes are the lines of the output.
+func decodeDocuments(output string) []renderedDocument {
+	documents := []renderedDocument{}
+	lines := strings.SplitAfter(output, "\n")
+	start := 0
+	for i := 1; i <= len(lines); i++ {
+		if i < len(lines) && !isDocumentSeparator(lines[i]) {
+			continue
+		}
+		var node yaml.Node
+		err := yaml.Unmarshal([]byte(strings.Join(lines[start:i], "")), &node)
+		if err != nil {
+			documents = append(documents, renderedDocument{err: err, startLine: start})
+		} else if len(node.Content) > 0 {
+			shiftLines(&node, start)
+			documents = append(documents, renderedDocument{node: node.Content[0], startLine: start})
+		}
+		start = i
+	}
+	return documents
+}
+
+func isDocumentSeparator(line string) bool {
+	return strings.HasPrefix(line, "---") && (len(line) == 3 || strings.ContainsRune(" \t\r\n", rune(line[3])))
+}
+
+func shiftLines(node *yaml.Node, offset int) {
+	node.Line += offset
+	for _, child := range node.Content {
+		shiftLines(child, offset)
+	}
+}
+
+// buildYamlDiagnostic maps the error of the document that starts at startLine of the output back to the template
+func buildYamlDiagnostic(sourceMap *helmrender.SourceMap, content []byte, err error, startLine int) lsp.Diagnostic {
+	outputLine, message := startLine, err.Error()
+	if match := yamlErrorRegex.FindStringSubmatch(message); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		outputLine, message = startLine+line-1, match[2]
+	}
+	outputOffset, outputLineEnd := lineOffset(sourceMap.Output, outputLine), lineOffset(sourceMap.Output, outputLine+1)
+
+	// yaml errors only contain the line, the output of actions in the line is the most likely cause
+	if action, ok := sourceMap.GetFirstActionInRange(outputOffset, outputLineEnd); ok {
+		return lsp.Diagnostic{
+			Range:           templateast.GetLspRangeForNode(action.Action),
+			Severity:        lsp.DiagnosticSeverityError,
+			Code:            diagnosticrules.RenderLintInvalidYaml.Code,
+			CodeDescription: diagnosticrules.RenderLintInvalidYaml.CodeDescription(),
+			Source:          diagnosticsSource,
+			Message:         fmt.Sprintf("The rendered YAML is invalid in the output of this action: %s", message),
+		}
+	}
+
+	start := offsetToPosition(content, sourceMap.GetSourceOffset(outputOffset))
+	return lsp.Diagnostic{
+		Range:           lsp.Range{Start: start, End: lsp.Position{Line: start.Line, Character: uint32(lineLength(content, int(start.Line)))}},
+		Severity:        lsp.DiagnosticSeverityError,
+		Code:            diagnosticrules.RenderLintInvalidYaml.Code,
+		CodeDescription: diagnosticrules.RenderLintInvalidYaml.CodeDescription(),
+		Source:          diagnosticsSource,
+		Message:         fmt.Sprintf("The rendered YAML is invalid: %s", message),
+	}
+}
+
+// lineOffset returns the offset of the start of the line (zero based)
+func lineOffset(text string, line int) int {
+	offset := 0
+	for i := 0; i < line; i++ {
+		next := strings.IndexByte(text[offset:], '\n')
+		if next < 0 {
+			return len(text)
+		}
+		offset += next + 1
+	}
+	return offset
+}
+
+func lineLength(content []byte, line int) int {
+	lines := strings.Split(string(content), "\n")
+	if line >= len(lines) {
+		return 0
+	}
+	return len(lines[line])
+}
+
+func offsetToPosition(content []byte, offset int) lsp.Position {
+	offset = min(max(offset, 0), len(content))
+	before := content[:offset]
+	line := strings.Count(string(before), "\n")
+	lineStart := strings.LastIndexByte(string(before), '\n') + 1
+	return lsp.Position{Line: uint32(line), Character: uint32(offset - lineStart)}
+}
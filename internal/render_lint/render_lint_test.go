@@ -0,0 +1,440 @@
+package renderlint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+const renderLintValues = `labels:
+  app: test
+  tier: web
+name: test
+`
+
+func TestGetDiagnosticsForInvalidYaml(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		template      string
+		expectedRange lsp.Range
+		expected      string
+	}{
+		{
+			desc:          "valid",
+			template:      "metadata:\n  labels:\n    {{- toYaml .Values.labels | nindent 4 }}\n",
+			expectedRange: lsp.Range{},
+		},
+		{
+			desc:     "error in the output of an action",
+			template: "metadata:\n  labels: {{ toYaml .Values.labels }}\n",
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 13},
+				End:   lsp.Position{Line: 1, Character: 35},
+			},
+			expected: "The rendered YAML is invalid in the output of this action: ",
+		},
+		{
+			desc:     "error in text after a trimmed action",
+			template: "metadata:\n  name: {{ .Values.name }}\n   labels: {}\n",
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 2, Character: 0},
+				End:   lsp.Position{Line: 2, Character: 13},
+			},
+			expected: "The rendered YAML is invalid: ",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			chart, doc := setupRenderLintTest(t, tt.template)
+
+			diagnostics := GetDiagnostics(chart, doc, chart.ValuesFiles.GetRenderValues(), util.DefaultConfig.RenderConfig, kubernetesschema.NewCRDSchemaCache())
+
+			if tt.expected == "" {
+				assert.Empty(t, diagnostics)
+				return
+			}
+			if !assert.Len(t, diagnostics, 1) {
+				return
+			}
+			assert.Equal(t, tt.expectedRange, diagnostics[0].Range)
+			assert.Equal(t, lsp.DiagnosticSeverityError, diagnostics[0].Severity)
+			assert.Contains(t, diagnostics[0].Message, tt.expected)
+		})
+	}
+}
+
+func TestGetIndentDiagnostics(t *testing.T) {
+	testCases := []struct {
+		template string
+		expected []string
+	}{
+		{"labels:\n  {{- include \"labels\" . | nindent 2 }}", []string{}},
+		{"labels:\n{{- include \"labels\" . | nindent 2 }}", []string{}},
+		{"labels: {{- include \"labels\" . | nindent 2 }}", []string{}},
+		{"labels:\n{{ include \"labels\" . | indent 2 }}", []string{}},
+		{"labels:\n    {{- include \"labels\" . | nindent 2 }}", []string{"nindent 2 does not match the column of the action. Use nindent 4"}},
+		{"labels:\n    {{- nindent 2 (include \"labels\" .) }}", []string{"nindent 2 does not match the column of the action. Use nindent 4"}},
+		{"labels:\n  {{ include \"labels\" . | indent 2 }}", []string{"indent 2 in column 2 indents the first line by 4 spaces. Use {{- with nindent 2 instead"}},
+		{"labels:\n{{- include \"labels\" . | indent 2 }}", []string{"{{- removes the line break before the action, so the first line of indent 2 is appended to the previous line. Use nindent 2 instead"}},
+		{"labels:\n  {{- include \"labels\" . | nindent 2 | quote }}", []string{}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.template, func(t *testing.T) {
+			ast := templateast.ParseAst(nil, []byte(tt.template))
+
+			messages := []string{}
+			for _, diagnostic := range GetIndentDiagnostics(ast.RootNode(), []byte(tt.template)) {
+				messages = append(messages, diagnostic.Message)
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
+
+func setupRenderLintTest(t *testing.T, template string) (*charts.Chart, *document.TemplateDocument) {
+	t.Helper()
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "templates")
+	assert.NoError(t, os.MkdirAll(templateDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(renderLintValues), 0o644))
+	templateFile := filepath.Join(templateDir, "deployment.yaml")
+	assert.NoError(t, os.WriteFile(templateFile, []byte(template), 0o644))
+
+	chartStore := charts.NewChartStore(uri.File(tempDir), charts.NewChart, func(chart *charts.Chart) {})
+	chart, err := chartStore.GetChartForDoc(uri.File(templateFile))
+	assert.NoError(t, err)
+
+	doc, err := document.NewDocumentStore().DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri.File(templateFile), Text: template},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+	return chart, doc
+}
+
+func TestGetDiagnosticsForInvalidKubernetesObjects(t *testing.T) {
+	template := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Values.name }}
+  labels:
+    {{- toYaml .Values.labels | nindent 4 }}
+spec:
+  replicas: {{ .Values.name }}
+  template:
+    spec:
+      containers:
+        - name: app
+          imagee: nginx
+---
+apiVersion: example.com/v1
+kind: Unknown
+spec:
+  foo: bar
+`
+	chart, doc := setupRenderLintTest(t, template)
+
+	diagnostics := GetDiagnostics(chart, doc, chart.ValuesFiles.GetRenderValues(), util.DefaultConfig.RenderConfig, kubernetesschema.NewCRDSchemaCache())
+
+	assert.Len(t, diagnostics, 2)
+	assert.Equal(t, []lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 7, Character: 15},
+				End:   lsp.Position{Line: 7, Character: 27},
+			},
+			Severity:        lsp.DiagnosticSeverityError,
+			Code:            diagnosticrules.RenderLintInvalidObject.Code,
+			CodeDescription: diagnosticrules.RenderLintInvalidObject.CodeDescription(),
+			Source:          diagnosticsSource,
+			Message:         "Invalid Kubernetes object in the output of this action: .spec.replicas: expected numeric (int or float), got string",
+		},
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 12, Character: 10},
+				End:   lsp.Position{Line: 12, Character: 23},
+			},
+			Severity:        lsp.DiagnosticSeverityError,
+			Code:            diagnosticrules.RenderLintInvalidObject.Code,
+			CodeDescription: diagnosticrules.RenderLintInvalidObject.CodeDescription(),
+			Source:          diagnosticsSource,
+			Message:         `Invalid Kubernetes object: .spec.template.spec.containers[name="app"].imagee: field not declared in schema`,
+		},
+	}, diagnostics)
+
+	config := util.DefaultConfig.RenderConfig
+	config.ValidationEnabled = false
+	assert.Empty(t, GetDiagnostics(chart, doc, chart.ValuesFiles.GetRenderValues(), config, kubernetesschema.NewCRDSchemaCache()))
+}
+
+func TestGetDiagnosticsValidatesDocumentsAfterInvalidYaml(t *testing.T) {
+	template := `metadata:
+  labels: {{ toYaml .Values.labels }}
+---
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: {{ .Values.name }}
+`
+	chart, doc := setupRenderLintTest(t, template)
+
+	diagnostics := GetDiagnostics(chart, doc, chart.ValuesFiles.GetRenderValues(), util.DefaultConfig.RenderConfig, kubernetesschema.NewCRDSchemaCache())
+
+	if !assert.Len(t, diagnostics, 2) {
+		return
+	}
+	assert.Equal(t, lsp.Range{Start: lsp.Position{Line: 1, Character: 13}, End: lsp.Position{Line: 1, Character: 35}}, diagnostics[0].Range)
+	assert.Equal(t, diagnosticrules.RenderLintInvalidYaml.Code, diagnostics[0].Code)
+	assert.Equal(t, lsp.Range{Start: lsp.Position{Line: 6, Character: 15}, End: lsp.Position{Line: 6, Character: 27}}, diagnostics[1].Range)
+	assert.Equal(t, "Invalid Kubernetes object in the output of this action: .spec.replicas: expected numeric (int or float), got string",
+		diagnostics[1].Message)
+}
+
+func TestCheckKubeVersion(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		kubeVersion       string
+		validationEnabled bool
+		expectedError     string
+	}{
+		{desc: "default", kubeVersion: "", validationEnabled: true},
+		{desc: "bundled version", kubeVersion: "v1.32.3", validationEnabled: true},
+		{
+			desc: "other minor version", kubeVersion: "v1.29.0", validationEnabled: true,
+			expectedError: "render.kubeVersion v1.29.0 is not supported, the bundled schemas are the ones of Kubernetes 1.32",
+		},
+		{desc: "other minor version without validation", kubeVersion: "v1.29.0", validationEnabled: false},
+		{desc: "invalid version", kubeVersion: "latest", validationEnabled: true, expectedError: "invalid render.kubeVersion latest"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			config := util.DefaultConfig.RenderConfig
+			config.KubeVersion = tt.kubeVersion
+			config.ValidationEnabled = tt.validationEnabled
+
+			err := CheckKubeVersion(config)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestGetDiagnosticsForDeprecatedAPIVersions(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		template      string
+		kubeVersion   string
+		expectedRange lsp.Range
+		expected      string
+		severity      lsp.DiagnosticSeverity
+		quickFix      interface{}
+	}{
+		{
+			desc:        "removed api",
+			template:    "apiVersion: extensions/v1beta1\nkind: NetworkPolicy\nmetadata:\n  name: test\n",
+			kubeVersion: "v1.20.0",
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 12},
+				End:   lsp.Position{Line: 0, Character: 30},
+			},
+			expected: "extensions/v1beta1 NetworkPolicy was removed in Kubernetes 1.16, use networking.k8s.io/v1 instead",
+			severity: lsp.DiagnosticSeverityError,
+			quickFix: QuickFixData{Title: "Use networking.k8s.io/v1", NewText: "networking.k8s.io/v1"},
+		},
+		{
+			desc:        "deprecated api",
+			template:    "apiVersion: policy/v1beta1\nkind: PodDisruptionBudget\nmetadata:\n  name: test\n",
+			kubeVersion: "v1.22.0",
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 12},
+				End:   lsp.Position{Line: 0, Character: 26},
+			},
+			expected: "policy/v1beta1 PodDisruptionBudget is deprecated since Kubernetes 1.21, use policy/v1 instead",
+			severity: lsp.DiagnosticSeverityWarning,
+			quickFix: QuickFixData{Title: "Use policy/v1", NewText: "policy/v1"},
+		},
+		{
+			desc:        "not yet deprecated",
+			template:    "apiVersion: policy/v1beta1\nkind: PodDisruptionBudget\nmetadata:\n  name: test\n",
+			kubeVersion: "v1.20.0",
+		},
+		{
+			desc:        "no mechanical replacement",
+			template:    "apiVersion: extensions/v1beta1\nkind: Ingress\nmetadata:\n  name: test\n",
+			kubeVersion: "v1.22.0",
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 12},
+				End:   lsp.Position{Line: 0, Character: 30},
+			},
+			expected: "extensions/v1beta1 Ingress was removed in Kubernetes 1.22, use networking.k8s.io/v1 instead",
+			severity: lsp.DiagnosticSeverityError,
+		},
+		{
+			desc:        "apiVersion from an action",
+			template:    "apiVersion: {{ \"batch/v1beta1\" }}\nkind: CronJob\nmetadata:\n  name: test\n",
+			kubeVersion: "v1.21.0",
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 15},
+				End:   lsp.Position{Line: 0, Character: 30},
+			},
+			expected: "batch/v1beta1 CronJob is deprecated since Kubernetes 1.21, use batch/v1 instead",
+			severity: lsp.DiagnosticSeverityWarning,
+		},
+		{
+			desc: "guarded fallback",
+			template: `{{- if .Capabilities.APIVersions.Has "example.com/v1" }}
+apiVersion: policy/v1
+{{- else }}
+apiVersion: policy/v1beta1
+{{- end }}
+kind: PodDisruptionBudget
+metadata:
+  name: test
+`,
+			kubeVersion: "v1.22.0",
+		},
+		{
+			desc: "checked branch",
+			template: `{{- if .Capabilities.APIVersions.Has "v1" }}
+apiVersion: policy/v1beta1
+{{- else }}
+apiVersion: policy/v1
+{{- end }}
+kind: PodDisruptionBudget
+metadata:
+  name: test
+`,
+			kubeVersion: "v1.22.0",
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 12},
+				End:   lsp.Position{Line: 1, Character: 26},
+			},
+			expected: "policy/v1beta1 PodDisruptionBudget is deprecated since Kubernetes 1.21, use policy/v1 instead",
+			severity: lsp.DiagnosticSeverityWarning,
+			quickFix: QuickFixData{Title: "Use policy/v1", NewText: "policy/v1"},
+		},
+		{
+			desc: "other capabilities check",
+			template: `{{- if semverCompare ">=1.21-0" .Capabilities.KubeVersion.Version }}
+apiVersion: policy/v1beta1
+{{- end }}
+kind: PodDisruptionBudget
+metadata:
+  name: test
+`,
+			kubeVersion: "v1.22.0",
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 12},
+				End:   lsp.Position{Line: 1, Character: 26},
+			},
+			expected: "policy/v1beta1 PodDisruptionBudget is deprecated since Kubernetes 1.21, use policy/v1 instead",
+			severity: lsp.DiagnosticSeverityWarning,
+			quickFix: QuickFixData{Title: "Use policy/v1", NewText: "policy/v1"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			chart, doc := setupRenderLintTest(t, tt.template)
+			config := util.DefaultConfig.RenderConfig
+			config.ValidationEnabled = false
+			config.KubeVersion = tt.kubeVersion
+
+			diagnostics := GetDiagnostics(chart, doc, chart.ValuesFiles.GetRenderValues(), config, kubernetesschema.NewCRDSchemaCache())
+
+			if tt.expected == "" {
+				assert.Empty(t, diagnostics)
+				return
+			}
+			assert.Equal(t, []lsp.Diagnostic{
+				{
+					Range:           tt.expectedRange,
+					Severity:        tt.severity,
+					Code:            diagnosticrules.RenderLintDeprecatedAPI.Code,
+					CodeDescription: diagnosticrules.RenderLintDeprecatedAPI.CodeDescription(),
+					Source:          diagnosticsSource,
+					Message:         tt.expected,
+					Data:            tt.quickFix,
+				},
+			}, diagnostics)
+		})
+	}
+}
+
+func TestGetDiagnosticsForCustomResources(t *testing.T) {
+	template := `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: {{ .Values.name }}
+spec:
+  size: {{ .Values.name }}
+  colour: red
+`
+	crd := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.com
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: integer
+                color:
+                  type: string
+`
+	chart, doc := setupRenderLintTest(t, template)
+	assert.Empty(t, GetDiagnostics(chart, doc, chart.ValuesFiles.GetRenderValues(), util.DefaultConfig.RenderConfig, kubernetesschema.NewCRDSchemaCache()))
+
+	crdsDir := filepath.Join(chart.RootURI.Filename(), "crds")
+	assert.NoError(t, os.MkdirAll(crdsDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(crdsDir, "widget.yaml"), []byte(crd), 0o644))
+	chart.ReloadCRDs()
+
+	diagnostics := GetDiagnostics(chart, doc, chart.ValuesFiles.GetRenderValues(), util.DefaultConfig.RenderConfig, kubernetesschema.NewCRDSchemaCache())
+
+	assert.Equal(t, []lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 6, Character: 2},
+				End:   lsp.Position{Line: 6, Character: 13},
+			},
+			Severity:        lsp.DiagnosticSeverityError,
+			Code:            diagnosticrules.RenderLintInvalidObject.Code,
+			CodeDescription: diagnosticrules.RenderLintInvalidObject.CodeDescription(),
+			Source:          diagnosticsSource,
+			Message:         "Invalid Kubernetes object: .spec.colour: field not declared in schema",
+		},
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 5, Character: 11},
+				End:   lsp.Position{Line: 5, Character: 23},
+			},
+			Severity:        lsp.DiagnosticSeverityError,
+			Code:            diagnosticrules.RenderLintInvalidObject.Code,
+			CodeDescription: diagnosticrules.RenderLintInvalidObject.CodeDescription(),
+			Source:          diagnosticsSource,
+			Message:         "Invalid Kubernetes object in the output of this action: .spec.size: Invalid type. Expected: integer, given: string",
+		},
+	}, diagnostics)
+}
@@ -0,0 +1,260 @@
+package renderlint
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	helmrender "github.com/mrjosh/helm-ls/internal/helm_render"
+	"github.com/mrjosh/helm-ls/internal/jsonschema"
+	kubernetesschema "github.com/mrjosh/helm-ls/internal/kubernetes_schema"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/util"
+	lsp "go.lsp.dev/protocol"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/applyconfigurations"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// bundledKubeVersion is the version of the kubernetes client libraries (k8s.io/client-go in go.mod),
+// their schemas are used for the validation of built-in kinds
+var bundledKubeVersion = kubeVersion{1, 32}
+
+var (
+	// typeConverter uses the schemas of all built-in kinds that are compiled into the kubernetes client libraries,
+	// so that no network access is needed for the validation
+	typeConverter = applyconfigurations.NewTypeConverter(scheme.Scheme)
+
+	unstructuredValueRegex = regexp.MustCompile(`&value\.valueUnstructured\{Value:(.*?)\}`)
+	pathElementRegex       = regexp.MustCompile(`\.([^.\[]+)|\[([^\]]*)\]`)
+)
+
+// getValidationDiagnostics validates the Kubernetes objects of the rendered documents against the bundled
+// schemas or the schemas of the CRDs and reports the errors at the actions or lines of the template that produced them.
+func getValidationDiagnostics(sourceMap *helmrender.SourceMap, documents []renderedDocument, content []byte,
+	crdSchemas map[kubernetesschema.GroupVersionKind][]byte,
+) []lsp.Diagnostic {
+	diagnostics := []lsp.Diagnostic{}
+	for _, document := range documents {
+		if document.node == nil || document.node.Kind != yaml.MappingNode {
+			continue
+		}
+		object, ok := toUnstructured(document.node)
+		if !ok {
+			continue
+		}
+
+		kind := kubernetesschema.GroupVersionKind{APIVersion: object.GetAPIVersion(), Kind: object.GetKind()}
+		var validationErrors typed.ValidationErrors
+		if schema, ok := crdSchemas[kind]; ok {
+			validationErrors = validateCustomResource(schema, object.Object)
+		} else {
+			validationErrors = validateBuiltInObject(object)
+		}
+
+		// the errors are collected from maps, they are sorted to report them in a stable order
+		slices.SortFunc(validationErrors, func(a, b typed.ValidationError) int {
+			return strings.Compare(a.Path+a.ErrorMessage, b.Path+b.ErrorMessage)
+		})
+		for _, validationError := range validationErrors {
+			// unknown fields are reported at their key, all other errors at the value
+			node := findNodeForPath(document.node, validationError.Path, strings.Contains(validationError.ErrorMessage, "not declared"))
+			message := unstructuredValueRegex.ReplaceAllString(validationError.ErrorMessage, "$1")
+			if validationError.Path != "" {
+				message = fmt.Sprintf("%s: %s", validationError.Path, message)
+			}
+			diagnostics = append(diagnostics, buildValidationDiagnostic(sourceMap, content, node, message))
+		}
+	}
+	return diagnostics
+}
+
+// CheckKubeVersion returns an error if the validation is enabled and render.kubeVersion is set to a minor version
+// that differs from the one of the bundled schemas, objects of built-in kinds could not be validated for it
+func CheckKubeVersion(config util.RenderConfig) error {
+	if !config.ValidationEnabled || config.KubeVersion == "" {
+		return nil
+	}
+	target, err := getTargetKubeVersion(config)
+	if err != nil {
+		return fmt.Errorf("invalid render.kubeVersion %s: %w", config.KubeVersion, err)
+	}
+	if target != bundledKubeVersion {
+		return fmt.Errorf("render.kubeVersion %s is not supported, the bundled schemas are the ones of Kubernetes %s. "+
+			"Disable render.validationEnabled to use another version", config.KubeVersion, bundledKubeVersion)
+	}
+	return nil
+}
+
+// toUnstructured converts the yaml node to a Kubernetes object, false if it has no apiVersion or kind
+func toUnstructured(node *yaml.Node) (*unstructured.Unstructured, bool) {
+	marshalled, err := yaml.Marshal(node)
+	if err != nil {
+		return nil, false
+	}
+	object := map[string]interface{}{}
+	if err := sigsyaml.Unmarshal(marshalled, &object); err != nil {
+		return nil, false
+	}
+	unstructuredObject := &unstructured.Unstructured{Object: object}
+	if unstructuredObject.GetAPIVersion() == "" || unstructuredObject.GetKind() == "" {
+		return nil, false
+	}
+	return unstructuredObject, true
+}
+
+// validateBuiltInObject returns the validation errors of an object of a built-in kind, objects of unknown kinds are not validated
+func validateBuiltInObject(object *unstructured.Unstructured) typed.ValidationErrors {
+	_, err := typeConverter.ObjectToTyped(object)
+	validationErrors := typed.ValidationErrors{}
+	if !errors.As(err, &validationErrors) {
+		if err != nil {
+			logger.Debug("Could not validate object", object.GetKind(), err)
+		}
+		return nil
+	}
+	return validationErrors
+}
+
+// validateCustomResource validates a custom resource against the schema of its CRD,
+// the errors use the same field paths and messages as the errors of the bundled schemas
+func validateCustomResource(schema []byte, object map[string]interface{}) typed.ValidationErrors {
+	schemaErrors, err := jsonschema.Validate(schema, object, "the CRD schema")
+	if err != nil {
+		logger.Debug("Could not validate custom resource", err)
+		return nil
+	}
+
+	validationErrors := typed.ValidationErrors{}
+	for _, schemaError := range schemaErrors {
+		path, message := toFieldPath(schemaError.Path), schemaError.Message
+		if schemaError.Type == "additional_property_not_allowed" {
+			path, message = path+"."+schemaError.Property, "field not declared in schema"
+		}
+		validationErrors = append(validationErrors, typed.ValidationError{Path: path, ErrorMessage: message})
+	}
+	return validationErrors
+}
+
+// toFieldPath formats the path of a JSON schema error like .spec.containers[0].image
+func toFieldPath(path []string) string {
+	result := ""
+	for _, element := range path {
+		if _, err := strconv.Atoi(element); err == nil {
+			result += "[" + element + "]"
+		} else {
+			result += "." + element
+		}
+	}
+	return result
+}
+
+func buildValidationDiagnostic(sourceMap *helmrender.SourceMap, content []byte, node *yaml.Node, message string) lsp.Diagnostic {
+	outputOffset := lineOffset(sourceMap.Output, node.Line-1) + node.Column - 1
+	if action, ok := sourceMap.GetActionAt(outputOffset); ok {
+		return lsp.Diagnostic{
+			Range:           templateast.GetLspRangeForNode(action.Action),
+			Severity:        lsp.DiagnosticSeverityError,
+			Code:            diagnosticrules.RenderLintInvalidObject.Code,
+			CodeDescription: diagnosticrules.RenderLintInvalidObject.CodeDescription(),
+			Source:          diagnosticsSource,
+			Message:         fmt.Sprintf("Invalid Kubernetes object in the output of this action: %s", message),
+		}
+	}
+
+	start := offsetToPosition(content, sourceMap.GetSourceOffset(outputOffset))
+	return lsp.Diagnostic{
+		Range:           lsp.Range{Start: start, End: lsp.Position{Line: start.Line, Character: uint32(lineLength(content, int(start.Line)))}},
+		Severity:        lsp.DiagnosticSeverityError,
+		Code:            diagnosticrules.RenderLintInvalidObject.Code,
+		CodeDescription: diagnosticrules.RenderLintInvalidObject.CodeDescription(),
+		Source:          diagnosticsSource,
+		Message:         fmt.Sprintf("Invalid Kubernetes object: %s", message),
+	}
+}
+
+// findNodeForPath returns the deepest node of the object that matches the field path of a validation error,
+// e.g. .spec.containers[name="nginx"].image. The last field is resolved to its key node if useKey is set.
+func findNodeForPath(object *yaml.Node, path string, useKey bool) *yaml.Node {
+	current, result := object, object
+	for _, match := range pathElementRegex.FindAllStringSubmatch(path, -1) {
+		var key, value *yaml.Node
+		if match[1] != "" {
+			key, value = findMappingValue(current, match[1])
+		} else {
+			value = findListItem(current, match[2])
+			key = value
+		}
+		if value == nil {
+			return result
+		}
+		current, result = value, key
+	}
+	if useKey {
+		return result
+	}
+	return current
+}
+
+func findMappingValue(node *yaml.Node, name string) (key *yaml.Node, value *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// findListItem returns the item of a list for a path element like [name="nginx",port=80], [0] or [="value"]
+func findListItem(node *yaml.Node, selector string) *yaml.Node {
+	if node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index < len(node.Content) {
+			return node.Content[index]
+		}
+		return nil
+	}
+	if strings.HasPrefix(selector, "=") {
+		for _, item := range node.Content {
+			if item.Value == unquote(selector[1:]) {
+				return item
+			}
+		}
+		return nil
+	}
+
+	for _, item := range node.Content {
+		matches := true
+		for _, keyValue := range strings.Split(selector, ",") {
+			name, value, _ := strings.Cut(keyValue, "=")
+			_, itemValue := findMappingValue(item, name)
+			if itemValue == nil || itemValue.Value != unquote(value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return item
+		}
+	}
+	return nil
+}
+
+func unquote(value string) string {
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
@@ -19,21 +19,29 @@ const (
 	NodeTypeEnd                          = "end"
 	NodeTypeError                        = "ERROR"
 	NodeTypeField                        = "field"
+	NodeTypeFalse                        = "false"
 	NodeTypeFieldIdentifier              = "field_identifier"
+	NodeTypeFloatLiteral                 = "float_literal"
 	NodeTypeFunctionCall                 = "function_call"
 	NodeTypeIdentifier                   = "identifier"
 	NodeTypeIf                           = "if"
 	NodeTypeIfAction                     = "if_action"
+	NodeTypeIntLiteral                   = "int_literal"
 	NodeTypeInterpretedStringLiteral     = "interpreted_string_literal"
+	NodeTypeNil                          = "nil"
 	NodeTypeOpenBraces                   = "{{"
 	NodeTypeOpenBracesDash               = "{{-"
+	NodeTypeParenthesizedPipeline        = "parenthesized_pipeline"
 	NodeTypeRange                        = "range"
+	NodeTypeRawStringLiteral             = "raw_string_literal"
 	NodeTypeRangeAction                  = "range_action"
 	NodeTypeRangeVariableDefinition      = "range_variable_definition"
 	NodeTypeSelectorExpression           = "selector_expression"
 	NodeTypeUnfinishedSelectorExpression = "unfinished_selector_expression"
 	NodeTypeTemplate                     = "template"
+	NodeTypeTemplateAction               = "template_action"
 	NodeTypeText                         = "text"
+	NodeTypeTrue                         = "true"
 	NodeTypeVariable                     = "variable"
 	NodeTypeVariableDefinition           = "variable_definition"
 	NodeTypeWith                         = "with"
@@ -0,0 +1,297 @@
+package typecheck
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	diagnosticrules "github.com/mrjosh/helm-ls/internal/diagnostic_rules"
+	helmdocs "github.com/mrjosh/helm-ls/internal/documentation/helm"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/lsp/symboltable"
+	templateast "github.com/mrjosh/helm-ls/internal/lsp/template_ast"
+	"github.com/mrjosh/helm-ls/internal/tree-sitter/gotemplate"
+	"github.com/mrjosh/helm-ls/internal/util"
+	sitter "github.com/smacker/go-tree-sitter"
+	lsp "go.lsp.dev/protocol"
+)
+
+const typeNumber = "number"
+
+var (
+	arithmeticFunctions = map[string]bool{
+		"add": true, "add1": true, "sub": true, "mul": true, "div": true, "mod": true, "max": true, "min": true,
+		"addf": true, "add1f": true, "subf": true, "mulf": true, "divf": true, "maxf": true, "minf": true,
+	}
+	quoteFunctions  = map[string]bool{"quote": true, "squote": true}
+	toYamlFunctions = map[string]bool{"toYaml": true, "toYamlPretty": true}
+)
+
+// exprType is the scalar type of an expression
+type exprType struct {
+	name string
+	// fromValues is true if the type was read from the values files
+	fromValues bool
+}
+
+func (t exprType) String() string {
+	if t.fromValues {
+		return fmt.Sprintf("a %s in all values files", t.name)
+	}
+	return "a " + t.name
+}
+
+type checker struct {
+	chart       *charts.Chart
+	chartStore  *charts.ChartStore
+	symbolTable *symboltable.SymbolTable
+	content     []byte
+	diagnostics []lsp.Diagnostic
+}
+
+// GetDiagnostics returns warnings for likely type errors in the template, that
+// would only be reported by helm when the template is rendered. The types are taken
+// from the values files and from the return types of the helm functions.
+func GetDiagnostics(chart *charts.Chart, chartStore *charts.ChartStore, doc *document.TemplateDocument) []lsp.Diagnostic {
+	c := &checker{
+		chart:       chart,
+		chartStore:  chartStore,
+		symbolTable: doc.SymbolTable,
+		content:     doc.Content,
+		diagnostics: []lsp.Diagnostic{},
+	}
+	if doc.Ast == nil || doc.SymbolTable == nil {
+		return c.diagnostics
+	}
+	c.walk(doc.Ast.RootNode())
+	return c.diagnostics
+}
+
+func (c *checker) walk(node *sitter.Node) {
+	switch node.Type() {
+	case gotemplate.NodeTypeRangeAction:
+		c.checkRange(node)
+	case gotemplate.NodeTypeFunctionCall:
+		c.checkFunctionCall(node)
+	case gotemplate.NodeTypeFieldIdentifier:
+		c.checkFieldAccess(node, node)
+	case gotemplate.NodeTypeField:
+		if name := node.ChildByFieldName("name"); name != nil {
+			c.checkFieldAccess(node, name)
+		}
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		c.walk(node.NamedChild(i))
+	}
+}
+
+// checkRange warns about {{ range .Values.name }} where name is a string
+func (c *checker) checkRange(node *sitter.Node) {
+	expression := node.ChildByFieldName("range")
+	if expression == nil && node.NamedChildCount() > 0 {
+		// range_variable_definition is not stored in the range field
+		expression = node.NamedChild(0)
+	}
+	if expression == nil {
+		return
+	}
+	switch expression.Type() {
+	case gotemplate.NodeTypeVariableDefinition:
+		expression = expression.ChildByFieldName("value")
+	case gotemplate.NodeTypeRangeVariableDefinition:
+		expression = expression.ChildByFieldName("range")
+	}
+	if expression == nil {
+		return
+	}
+	t, ok := c.getType(expression)
+	// ranging over integers is supported since go 1.22
+	if !ok || t.name == helmdocs.ReturnTypeInt {
+		return
+	}
+	c.addDiagnostic(expression, diagnosticrules.TypeCheckRange, fmt.Sprintf("range can't iterate over %s", t))
+}
+
+// checkFunctionCall warns about {{ add "a" 1 }} and {{ toYaml .Values.x | quote }}
+func (c *checker) checkFunctionCall(node *sitter.Node) {
+	function := node.ChildByFieldName("function")
+	if function == nil {
+		return
+	}
+	functionName := function.Content(c.content)
+	if !arithmeticFunctions[functionName] && !quoteFunctions[functionName] {
+		return
+	}
+
+	for _, argument := range c.getArguments(node) {
+		if arithmeticFunctions[functionName] {
+			if t, ok := c.getType(argument); ok && t.name == helmdocs.ReturnTypeString {
+				c.addDiagnostic(argument, diagnosticrules.TypeCheckNumberArgument, fmt.Sprintf("%s expects numbers, but the argument is %s", functionName, t))
+			}
+			continue
+		}
+		if toYamlFunctions[c.getFunctionName(argument)] {
+			c.addDiagnostic(argument, diagnosticrules.TypeCheckQuotedToYaml, fmt.Sprintf("%s of toYaml output results in a single string instead of YAML, use toYaml with nindent instead", functionName))
+		}
+	}
+}
+
+// checkFieldAccess warns about {{ .Values.name.first }} where name is a string
+func (c *checker) checkFieldAccess(node, contextNode *sitter.Node) {
+	templateContext, err := c.symbolTable.GetTemplateContext(contextNode.Range())
+	if err != nil || len(templateContext) < 3 || templateContext[0] != "Values" {
+		return
+	}
+	parent := templateContext[:len(templateContext)-1]
+	t, ok := c.getValuesType(parent)
+	if !ok {
+		return
+	}
+	c.addDiagnostic(node, diagnosticrules.TypeCheckFieldAccess, fmt.Sprintf("can't access field %s of .%s, it is %s", templateContext[len(templateContext)-1], parent.Format(), t))
+}
+
+// getArguments returns the arguments of the function call including the piped argument
+func (c *checker) getArguments(node *sitter.Node) []*sitter.Node {
+	result := []*sitter.Node{}
+	if arguments := node.ChildByFieldName("arguments"); arguments != nil {
+		for i := 0; i < int(arguments.NamedChildCount()); i++ {
+			result = append(result, arguments.NamedChild(i))
+		}
+	}
+	if parent := node.Parent(); parent != nil && parent.Type() == gotemplate.NodeTypeChainedPipeline {
+		if previous := node.PrevNamedSibling(); previous != nil {
+			result = append(result, previous)
+		}
+	}
+	return result
+}
+
+// getFunctionName returns the name of the function whose result is the value of the expression
+func (c *checker) getFunctionName(node *sitter.Node) string {
+	switch node.Type() {
+	case gotemplate.NodeTypeFunctionCall:
+		if function := node.ChildByFieldName("function"); function != nil {
+			return function.Content(c.content)
+		}
+	case gotemplate.NodeTypeParenthesizedPipeline, gotemplate.NodeTypeChainedPipeline:
+		if node.NamedChildCount() > 0 {
+			return c.getFunctionName(node.NamedChild(int(node.NamedChildCount()) - 1))
+		}
+	}
+	return ""
+}
+
+// getType returns the scalar type of the expression, false if the type is unknown or not a scalar
+func (c *checker) getType(node *sitter.Node) (exprType, bool) {
+	switch node.Type() {
+	case gotemplate.NodeTypeInterpretedStringLiteral, gotemplate.NodeTypeRawStringLiteral:
+		return exprType{name: helmdocs.ReturnTypeString}, true
+	case gotemplate.NodeTypeFloatLiteral:
+		return exprType{name: helmdocs.ReturnTypeFloat}, true
+	case gotemplate.NodeTypeIntLiteral:
+		return exprType{name: helmdocs.ReturnTypeInt}, true
+	case gotemplate.NodeTypeTrue, gotemplate.NodeTypeFalse:
+		return exprType{name: helmdocs.ReturnTypeBool}, true
+	case gotemplate.NodeTypeFunctionCall:
+		name, ok := helmdocs.GetFunctionReturnType(c.getFunctionName(node))
+		if !ok || name == helmdocs.ReturnTypeList || name == helmdocs.ReturnTypeDict || strings.Contains(name, ".") {
+			return exprType{}, false
+		}
+		return exprType{name: name}, true
+	case gotemplate.NodeTypeParenthesizedPipeline:
+		if node.NamedChildCount() == 1 {
+			return c.getType(node.NamedChild(0))
+		}
+	case gotemplate.NodeTypeChainedPipeline:
+		if node.NamedChildCount() > 0 {
+			return c.getType(node.NamedChild(int(node.NamedChildCount()) - 1))
+		}
+	case gotemplate.NodeTypeVariable:
+		valueType, err := c.symbolTable.GetVariableValueType(node.Content(c.content), node.Range())
+		if err != nil {
+			return exprType{}, false
+		}
+		return c.getTypeForValueType(valueType)
+	case gotemplate.NodeTypeField:
+		return c.getTypeForContextNode(node.ChildByFieldName("name"))
+	case gotemplate.NodeTypeSelectorExpression:
+		return c.getTypeForContextNode(node.ChildByFieldName("field"))
+	case gotemplate.NodeTypeDot:
+		return c.getTypeForContextNode(node)
+	}
+	return exprType{}, false
+}
+
+func (c *checker) getTypeForContextNode(node *sitter.Node) (exprType, bool) {
+	if node == nil {
+		return exprType{}, false
+	}
+	valueType, err := c.symbolTable.GetValueType(node.Range())
+	if err != nil {
+		return exprType{}, false
+	}
+	return c.getTypeForValueType(valueType)
+}
+
+func (c *checker) getTypeForValueType(valueType symboltable.ValueType) (exprType, bool) {
+	switch valueType.Kind {
+	case symboltable.ValueKindScalar:
+		if strings.Contains(valueType.Name, "|") || strings.Contains(valueType.Name, ".") {
+			return exprType{}, false
+		}
+		return exprType{name: valueType.Name}, true
+	case symboltable.ValueKindTemplateContext:
+		return c.getValuesType(valueType.TemplateContext)
+	}
+	return exprType{}, false
+}
+
+// getValuesType returns the type of the value of the template context if it is a scalar
+// of the same type in all values files that contain it
+func (c *checker) getValuesType(templateContext symboltable.TemplateContext) (exprType, bool) {
+	if c.chart == nil || len(templateContext) < 2 || templateContext[0] != "Values" {
+		return exprType{}, false
+	}
+	result := exprType{}
+	for _, queriedValuesFiles := range c.chart.ResolveValueFiles(templateContext.Tail(), c.chartStore) {
+		for _, valuesFile := range queriedValuesFiles.ValuesFiles.ActiveValuesFiles() {
+			value, err := util.GetValueForSelector(valuesFile.Values, queriedValuesFiles.Selector)
+			if err != nil || value == nil {
+				continue
+			}
+			name, ok := getScalarTypeName(value)
+			if !ok || (result.name != "" && result.name != name) {
+				// the value is not a scalar or has different types in the values files
+				return exprType{}, false
+			}
+			result = exprType{name: name, fromValues: true}
+		}
+	}
+	return result, result.name != ""
+}
+
+func getScalarTypeName(value any) (string, bool) {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String:
+		return helmdocs.ReturnTypeString, true
+	case reflect.Bool:
+		return helmdocs.ReturnTypeBool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return typeNumber, true
+	}
+	return "", false
+}
+
+func (c *checker) addDiagnostic(node *sitter.Node, rule diagnosticrules.Rule, message string) {
+	c.diagnostics = append(c.diagnostics, lsp.Diagnostic{
+		Range:           templateast.GetLspRangeForNode(node),
+		Severity:        lsp.DiagnosticSeverityWarning,
+		Code:            rule.Code,
+		CodeDescription: rule.CodeDescription(),
+		Source:          "Helm-ls TypeCheck",
+		Message:         message,
+	})
+}
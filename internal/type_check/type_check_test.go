@@ -0,0 +1,122 @@
+package typecheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrjosh/helm-ls/internal/charts"
+	"github.com/mrjosh/helm-ls/internal/lsp/document"
+	"github.com/mrjosh/helm-ls/internal/util"
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+const typeCheckValues = `name: test
+port: 80
+enabled: true
+list:
+  - a
+service:
+  port: 80
+nothing:
+`
+
+func TestGetDiagnostics(t *testing.T) {
+	testCases := []struct {
+		template string
+		expected []string
+	}{
+		{`{{ range .Values.name }}{{ end }}`, []string{"range can't iterate over a string in all values files"}},
+		{`{{ range $i, $v := .Values.port }}{{ end }}`, []string{"range can't iterate over a number in all values files"}},
+		{`{{ range .Values.list }}{{ end }}`, []string{}},
+		{`{{ range .Values.nothing }}{{ end }}`, []string{}},
+		{`{{ range .Values.unknown }}{{ end }}`, []string{}},
+		{`{{ range 3 }}{{ end }}`, []string{}},
+		{`{{ range $x := "abc" }}{{ end }}`, []string{"range can't iterate over a string"}},
+		{`{{ $n := .Values.name }}{{ range $n }}{{ end }}`, []string{"range can't iterate over a string in all values files"}},
+		{`{{ add "a" 1 }}`, []string{"add expects numbers, but the argument is a string"}},
+		{`{{ add .Values.port 1 }}`, []string{}},
+		{`{{ .Values.name | add 1 }}`, []string{"add expects numbers, but the argument is a string in all values files"}},
+		{`{{ quote .Values.name | add 1 }}`, []string{"add expects numbers, but the argument is a string"}},
+		{`{{ .Values.port | int | add 1 }}`, []string{}},
+		{`{{ toYaml .Values.service | quote }}`, []string{"quote of toYaml output results in a single string instead of YAML, use toYaml with nindent instead"}},
+		{`{{ quote (toYaml .Values.service) }}`, []string{"quote of toYaml output results in a single string instead of YAML, use toYaml with nindent instead"}},
+		{`{{ toYaml .Values.service | nindent 2 }}`, []string{}},
+		{`{{ .Values.name.first }}`, []string{"can't access field first of .Values.name, it is a string in all values files"}},
+		{`{{ .Values.name.first.second }}`, []string{"can't access field first of .Values.name, it is a string in all values files"}},
+		{`{{ .Values.service.port }}`, []string{}},
+		{`{{ with .Values.enabled }}{{ .value }}{{ end }}`, []string{"can't access field value of .Values.enabled, it is a bool in all values files"}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.template, func(t *testing.T) {
+			chart, chartStore, doc := setupTypeCheckTest(t, tt.template, "")
+
+			messages := []string{}
+			for _, diagnostic := range GetDiagnostics(chart, chartStore, doc) {
+				assert.Equal(t, lsp.DiagnosticSeverityWarning, diagnostic.Severity)
+				messages = append(messages, diagnostic.Message)
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
+
+func TestGetDiagnosticsRange(t *testing.T) {
+	chart, chartStore, doc := setupTypeCheckTest(t, "{{ range .Values.name }}{{ end }}", "")
+
+	diagnostics := GetDiagnostics(chart, chartStore, doc)
+
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, lsp.Range{
+		Start: lsp.Position{Line: 0, Character: 9},
+		End:   lsp.Position{Line: 0, Character: 21},
+	}, diagnostics[0].Range)
+}
+
+func TestGetDiagnosticsWithDifferentTypesInValuesFiles(t *testing.T) {
+	testCases := []struct {
+		template string
+		expected []string
+	}{
+		{`{{ add .Values.port 1 }}`, []string{}},
+		{`{{ .Values.port.first }}`, []string{}},
+		{`{{ .Values.name.first }}`, []string{"can't access field first of .Values.name, it is a string in all values files"}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.template, func(t *testing.T) {
+			chart, chartStore, doc := setupTypeCheckTest(t, tt.template, "port: \"80\"\nname: prod\n")
+
+			messages := []string{}
+			for _, diagnostic := range GetDiagnostics(chart, chartStore, doc) {
+				messages = append(messages, diagnostic.Message)
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
+
+func setupTypeCheckTest(t *testing.T, template string, additionalValues string) (*charts.Chart, *charts.ChartStore, *document.TemplateDocument) {
+	t.Helper()
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "templates")
+	assert.NoError(t, os.MkdirAll(templateDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\napiVersion: v2"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(typeCheckValues), 0o644))
+	if additionalValues != "" {
+		assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.prod.yaml"), []byte(additionalValues), 0o644))
+	}
+	templateFile := filepath.Join(templateDir, "deployment.yaml")
+	assert.NoError(t, os.WriteFile(templateFile, []byte(template), 0o644))
+
+	chartStore := charts.NewChartStore(uri.File(tempDir), charts.NewChart, func(chart *charts.Chart) {})
+	chart, err := chartStore.GetChartForDoc(uri.File(templateFile))
+	assert.NoError(t, err)
+
+	doc, err := document.NewDocumentStore().DidOpenTemplateDocument(&lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri.File(templateFile), Text: template},
+	}, util.DefaultConfig)
+	assert.NoError(t, err)
+	return chart, chartStore, doc
+}
@@ -7,13 +7,40 @@ import (
 type HelmlsConfiguration struct {
 	YamllsConfiguration YamllsConfiguration `json:"yamlls,omitempty"`
 	ValuesFilesConfig   ValuesFilesConfig   `json:"valuesFiles,omitempty"`
+	RenderConfig        RenderConfig        `json:"render,omitempty"`
 	LogLevel            string              `json:"logLevel,omitempty"`
+	// DisabledRules are codes, IDs, names or groups of diagnostic rules that are not reported
+	DisabledRules []string `json:"disabledRules,omitempty"`
+}
+
+// RenderConfig holds the options that are used when templates are rendered with the helm engine
+type RenderConfig struct {
+	ReleaseName string `json:"releaseName,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	// KubeVersion is the version used for .Capabilities.KubeVersion, the helm default is used if empty
+	KubeVersion string `json:"kubeVersion,omitempty"`
+	// APIVersions are added to the default .Capabilities.APIVersions, e.g. monitoring.coreos.com/v1/ServiceMonitor
+	APIVersions []string `json:"apiVersions,omitempty"`
+	// IsUpgrade renders the templates as an upgrade instead of an install
+	IsUpgrade bool `json:"isUpgrade,omitempty"`
+	// HoverEnabled shows the rendered output of an action when hovering over it
+	HoverEnabled bool `json:"hoverEnabled,omitempty"`
+	// ValidationEnabled validates the rendered Kubernetes objects against the bundled schemas
+	ValidationEnabled bool `json:"validationEnabled,omitempty"`
 }
 
 type ValuesFilesConfig struct {
 	MainValuesFileName               string `json:"mainValuesFile,omitempty"`
 	LintOverlayValuesFileName        string `json:"lintOverlayValuesFile,omitempty"`
 	AdditionalValuesFilesGlobPattern string `json:"additionalValuesFilesGlobPattern,omitempty"`
+	// ActiveProfiles maps chart names to an ordered list of values files (relative to the chart root)
+	// that are coalesced like `helm -f a -f b` for hover, completion, lint and rendering
+	ActiveProfiles map[string][]string `json:"activeProfiles,omitempty"`
+	// SetValues, SetStringValues and SetJSONValues are applied on top of the values files
+	// like the --set, --set-string and --set-json flags of helm
+	SetValues       []string `json:"set,omitempty"`
+	SetStringValues []string `json:"setString,omitempty"`
+	SetJSONValues   []string `json:"setJson,omitempty"`
 }
 
 type YamllsConfiguration struct {
@@ -22,14 +49,21 @@ type YamllsConfiguration struct {
 	EnabledForFilesGlobObject glob.Glob
 	Path                      string `json:"path,omitempty"`
 	InitTimeoutSeconds        int    `json:"initTimeoutSeconds,omitempty"`
-	DiagnosticsEnabled        bool   `json:"diagnosticsEnabled,omitempty"`
+	// MaxRestarts is the number of times yamlls is restarted after it exited unexpectedly
+	MaxRestarts        int  `json:"maxRestarts,omitempty"`
+	DiagnosticsEnabled bool `json:"diagnosticsEnabled,omitempty"`
 	// max diagnostics from yamlls that are shown for a single file
 	DiagnosticsLimit int `json:"diagnosticsLimit,omitempty"`
 	// if set to false diagnostics will only be shown after saving the file
 	// otherwise writing a template will cause a lot of diagnostics to be shown because
 	// the structure of the document is broken during typing
 	ShowDiagnosticsDirectly bool `json:"showDiagnosticsDirectly,omitempty"`
-	YamllsSettings          any  `json:"config,omitempty"`
+	// DiagnosticsFilters suppress the yamlls diagnostics they match
+	DiagnosticsFilters []YamllsDiagnosticsFilter `json:"diagnosticsFilters,omitempty"`
+	// if yamlls can not be started, completion, hover and schema validation for templates
+	// are provided by helm-ls itself
+	NativeFallback bool `json:"nativeFallback,omitempty"`
+	YamllsSettings any  `json:"config,omitempty"`
 }
 
 func (y *YamllsConfiguration) CompileEnabledForFilesGlobObject() {
@@ -48,15 +82,24 @@ var DefaultConfig = HelmlsConfiguration{
 		LintOverlayValuesFileName:        "values.lint.yaml",
 		AdditionalValuesFilesGlobPattern: "values*.yaml",
 	},
+	RenderConfig: RenderConfig{
+		ReleaseName:       "release-name",
+		Namespace:         "default",
+		HoverEnabled:      true,
+		ValidationEnabled: true,
+	},
 	YamllsConfiguration: YamllsConfiguration{
 		Enabled:                   true,
 		EnabledForFilesGlob:       "*.{yaml,yml}",
 		EnabledForFilesGlobObject: glob.MustCompile("*.{yaml,yml}"),
 		Path:                      "yaml-language-server",
 		InitTimeoutSeconds:        3,
+		MaxRestarts:               5,
 		DiagnosticsEnabled:        true,
 		DiagnosticsLimit:          50,
 		ShowDiagnosticsDirectly:   false,
+		DiagnosticsFilters:        defaultYamllsDiagnosticsFilters(),
+		NativeFallback:            true,
 		YamllsSettings:            DefaultYamllsSettings,
 	},
 }
@@ -0,0 +1,93 @@
+package util
+
+import (
+	"regexp"
+	"runtime"
+	"strings"
+
+	lsp "go.lsp.dev/protocol"
+)
+
+// Values of YamllsDiagnosticsFilter.Branch
+const (
+	// BranchIf matches diagnostics inside of any branch of an if action
+	BranchIf = "if"
+	// BranchElse matches diagnostics inside of an else or else if branch of an if action
+	BranchElse = "else"
+)
+
+// YamllsDiagnosticsFilter suppresses the yamlls diagnostics that match all of its set fields
+type YamllsDiagnosticsFilter struct {
+	// Message is a regular expression for the message of the diagnostic
+	Message string `json:"message,omitempty"`
+	// Severity is one of error, warning, information or hint
+	Severity string `json:"severity,omitempty"`
+	// Source is a regular expression for the source of the diagnostic (e.g. yaml-schema: https://...)
+	Source string `json:"source,omitempty"`
+	// Branch restricts the filter to diagnostics inside of if actions, see BranchIf and BranchElse
+	Branch string `json:"branch,omitempty"`
+
+	messageRegex *regexp.Regexp
+	sourceRegex  *regexp.Regexp
+}
+
+// defaultYamllsDiagnosticsFilters suppresses diagnostics that are caused by the templates
+func defaultYamllsDiagnosticsFilters() []YamllsDiagnosticsFilter {
+	filters := []YamllsDiagnosticsFilter{
+		// the keys of all branches of an if action are part of the yaml
+		{Message: "^Map keys must be unique$", Branch: BranchElse},
+		// caused by actions that yamlls can not see through, e.g. keys that are set by a range over a dict:
+		// {{ $key }}:
+		//   {{- range $value }}
+		//   - {{ . | quote }}
+		//   {{- end }}
+		{Message: "^All mapping items must start at the same column$"},
+		{Message: "^Implicit map keys need to be followed by map values$"},
+		{Message: "^Implicit keys need to be on a single line$"},
+		{Message: "^A block sequence may not be used as an implicit map key$"},
+		// block scalars that start with an action, e.g.
+		// smtp-password: |
+		//   {{- if not .Values.existingSecret }}
+		{Message: "^Block scalars with more-indented leading empty lines must use an explicit indentation indicator$"},
+	}
+	if runtime.GOOS == "windows" {
+		// TODO: remove this once the tree-sitter grammar behavior for windows newlines is the same as for unix
+		filters = append(filters, YamllsDiagnosticsFilter{Message: "^Incorrect type\\. Expected"})
+	}
+	return compileDiagnosticsFilters(filters)
+}
+
+// CompileDiagnosticsFilters compiles the regular expressions of the diagnostics filters,
+// filters with invalid regular expressions are removed
+func (y *YamllsConfiguration) CompileDiagnosticsFilters() {
+	y.DiagnosticsFilters = compileDiagnosticsFilters(y.DiagnosticsFilters)
+}
+
+func compileDiagnosticsFilters(filters []YamllsDiagnosticsFilter) []YamllsDiagnosticsFilter {
+	result := []YamllsDiagnosticsFilter{}
+	for _, filter := range filters {
+		var err error
+		if filter.messageRegex, err = regexp.Compile(filter.Message); err != nil {
+			logger.Error("Error compiling message of yamlls diagnostics filter", filter.Message, err)
+			continue
+		}
+		if filter.sourceRegex, err = regexp.Compile(filter.Source); err != nil {
+			logger.Error("Error compiling source of yamlls diagnostics filter", filter.Source, err)
+			continue
+		}
+		result = append(result, filter)
+	}
+	return result
+}
+
+// Matches returns true if the diagnostic matches all fields of the filter except the branch,
+// which has to be checked against the template
+func (f YamllsDiagnosticsFilter) Matches(diagnostic lsp.Diagnostic) bool {
+	if f.messageRegex == nil || f.sourceRegex == nil {
+		return false
+	}
+	if f.Severity != "" && !strings.EqualFold(f.Severity, diagnostic.Severity.String()) {
+		return false
+	}
+	return f.messageRegex.MatchString(diagnostic.Message) && f.sourceRegex.MatchString(diagnostic.Source)
+}
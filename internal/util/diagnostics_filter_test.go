@@ -0,0 +1,50 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	lsp "go.lsp.dev/protocol"
+)
+
+func TestYamllsDiagnosticsFilterMatches(t *testing.T) {
+	diagnostic := lsp.Diagnostic{
+		Message:  "Property foo is not allowed.",
+		Severity: lsp.DiagnosticSeverityWarning,
+		Source:   "yaml-schema: https://example.com/deployment.json",
+	}
+
+	testCases := []struct {
+		desc     string
+		filter   YamllsDiagnosticsFilter
+		expected bool
+	}{
+		{"empty filter", YamllsDiagnosticsFilter{}, true},
+		{"message", YamllsDiagnosticsFilter{Message: "^Property \\w+ is not allowed"}, true},
+		{"other message", YamllsDiagnosticsFilter{Message: "^Incorrect type"}, false},
+		{"severity", YamllsDiagnosticsFilter{Severity: "warning"}, true},
+		{"other severity", YamllsDiagnosticsFilter{Severity: "error"}, false},
+		{"source", YamllsDiagnosticsFilter{Source: "example\\.com"}, true},
+		{"all fields must match", YamllsDiagnosticsFilter{Message: "not allowed", Severity: "error"}, false},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			filters := compileDiagnosticsFilters([]YamllsDiagnosticsFilter{tt.filter})
+			assert.Len(t, filters, 1)
+			assert.Equal(t, tt.expected, filters[0].Matches(diagnostic))
+		})
+	}
+}
+
+func TestCompileDiagnosticsFiltersRemovesInvalidFilters(t *testing.T) {
+	config := YamllsConfiguration{DiagnosticsFilters: []YamllsDiagnosticsFilter{
+		{Message: "("},
+		{Source: "[a-"},
+		{Message: "valid"},
+	}}
+	config.CompileDiagnosticsFilters()
+
+	assert.Len(t, config.DiagnosticsFilters, 1)
+	assert.Equal(t, "valid", config.DiagnosticsFilters[0].Message)
+	assert.False(t, YamllsDiagnosticsFilter{Message: "valid"}.Matches(lsp.Diagnostic{Message: "valid"}), "uncompiled filters never match")
+}
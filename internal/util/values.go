@@ -18,6 +18,11 @@ func GetTableOrValueForSelector(values chartutil.Values, selector []string) (str
 	return FormatToYAML(reflect.Indirect(reflect.ValueOf(value)), strings.Join(selector, ".")), err
 }
 
+// GetValueForSelector returns the value at the selector, [] suffixes select the first element of lists or maps
+func GetValueForSelector(values chartutil.Values, selector []string) (any, error) {
+	return pathLookup(values, selector)
+}
+
 func GetSubValuesForSelector(values chartutil.Values, selector []string) (map[string]any, error) {
 	if len(selector) <= 0 || selector[0] == "" {
 		return values, nil
@@ -3,6 +3,7 @@ package util
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	lsp "go.lsp.dev/protocol"
@@ -94,6 +95,54 @@ func GetNodeForPosition(node *yamlv3.Node, position lsp.Position) *yamlv3.Node {
 	return nil
 }
 
+// GetYamlNodesForPath returns the key node (nil for items of sequences) and the value node for the path,
+// aliases are resolved
+func GetYamlNodesForPath(node *yamlv3.Node, path []string) (key *yamlv3.Node, value *yamlv3.Node) {
+	value = node
+	for _, part := range path {
+		value = resolveYamlNode(value)
+		if value == nil {
+			return nil, nil
+		}
+		key = nil
+		switch value.Kind {
+		case yamlv3.MappingNode:
+			var next *yamlv3.Node
+			for i := 0; i+1 < len(value.Content); i += 2 {
+				if value.Content[i].Value == part {
+					key, next = value.Content[i], value.Content[i+1]
+					break
+				}
+			}
+			value = next
+		case yamlv3.SequenceNode:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(value.Content) {
+				return nil, nil
+			}
+			value = value.Content[index]
+		default:
+			return nil, nil
+		}
+	}
+	return key, resolveYamlNode(value)
+}
+
+// resolveYamlNode returns the content of documents and the node of aliases
+func resolveYamlNode(node *yamlv3.Node) *yamlv3.Node {
+	for node != nil && (node.Kind == yamlv3.DocumentNode || node.Kind == yamlv3.AliasNode) {
+		if node.Kind == yamlv3.AliasNode {
+			node = node.Alias
+			continue
+		}
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	return node
+}
+
 // ReadYamlToNode will parse a YAML file into a yaml Node.
 func ReadYamlToNode(data []byte) (node yamlv3.Node, err error) {
 	err = yamlv3.Unmarshal(data, &node)
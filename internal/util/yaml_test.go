@@ -99,3 +99,45 @@ func TestGetNodeForPosition(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, "repository", result.Value)
 }
+
+func TestGetYamlNodesForPath(t *testing.T) {
+	content := `defaults: &defaults
+  image: nginx
+app:
+  <<: *defaults
+  ports:
+    - 80
+aliased: *defaults
+`
+	var node yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(content), &node))
+
+	testCases := []struct {
+		path          []string
+		expectedKey   string
+		expectedValue string
+		expectedLine  int
+	}{
+		{[]string{"defaults", "image"}, "image", "nginx", 2},
+		{[]string{"app", "ports", "0"}, "", "80", 6},
+		{[]string{"aliased", "image"}, "image", "nginx", 2},
+		{[]string{"app", "missing"}, "", "", 0},
+		{[]string{"app", "ports", "1"}, "", "", 0},
+	}
+	for _, tt := range testCases {
+		t.Run(fmt.Sprint(tt.path), func(t *testing.T) {
+			key, value := GetYamlNodesForPath(&node, tt.path)
+			if tt.expectedValue == "" {
+				assert.Nil(t, value)
+				return
+			}
+			assert.Equal(t, tt.expectedValue, value.Value)
+			assert.Equal(t, tt.expectedLine, value.Line)
+			if tt.expectedKey == "" {
+				assert.Nil(t, key)
+			} else {
+				assert.Equal(t, tt.expectedKey, key.Value)
+			}
+		})
+	}
+}